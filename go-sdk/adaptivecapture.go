@@ -0,0 +1,180 @@
+package langwatch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// CaptureLevel names how much span content an AdaptiveExporter is
+// currently willing to ship downstream.
+type CaptureLevel string
+
+const (
+	// CaptureFull ships input, outputs and raw_response unmodified.
+	CaptureFull CaptureLevel = "full"
+	// CaptureTruncated ships input/outputs shortened to a bounded size.
+	CaptureTruncated CaptureLevel = "truncated"
+	// CaptureMetadataOnly drops input/outputs entirely; span structure,
+	// timing, errors and metrics still ship.
+	CaptureMetadataOnly CaptureLevel = "metadata_only"
+)
+
+// metadataCaptureLevel records which CaptureLevel was active for a span at
+// export time, so a downgrade during a load spike shows up in the trace
+// itself instead of just looking like content that was never recorded.
+const metadataCaptureLevel = "langwatch.capture.level"
+
+// defaultAdaptiveTruncateBytes bounds each text/JSON attribute kept under
+// CaptureTruncated.
+const defaultAdaptiveTruncateBytes = 512
+
+// AdaptiveExporterOption configures an AdaptiveExporter built with
+// NewAdaptiveExporter.
+type AdaptiveExporterOption func(*AdaptiveExporter)
+
+// WithCaptureThresholds sets the number of Export calls AdaptiveExporter
+// must have in flight to downgrade capture from full to truncated, and
+// from truncated to metadata-only. Defaults to 8 and 32.
+func WithCaptureThresholds(truncateAt, metadataOnlyAt int) AdaptiveExporterOption {
+	return func(e *AdaptiveExporter) {
+		e.truncateAt = truncateAt
+		e.metadataOnlyAt = metadataOnlyAt
+	}
+}
+
+// WithRecoveryMargin sets how far in-flight depth must drop back below a
+// threshold before AdaptiveExporter upgrades capture again, so depth
+// hovering right at a threshold doesn't flap between levels on every other
+// call. Defaults to 4.
+func WithRecoveryMargin(n int) AdaptiveExporterOption {
+	return func(e *AdaptiveExporter) { e.recoveryMargin = n }
+}
+
+// WithAdaptiveTruncateBytes sets how many bytes of each text/JSON
+// attribute survive under CaptureTruncated. Defaults to 512.
+func WithAdaptiveTruncateBytes(n int) AdaptiveExporterOption {
+	return func(e *AdaptiveExporter) { e.truncateBytes = n }
+}
+
+// AdaptiveExporter wraps another Exporter and downgrades how much span
+// content it ships - full, then truncated, then metadata-only - as the
+// number of Export calls it has in flight grows, so a trace's structure
+// (spans, timing, errors, metrics) always reaches LangWatch even under load
+// that would otherwise back up or overwhelm the collector, at the cost of
+// the heaviest content (input/outputs) first.
+//
+// This SDK's exporters are synchronous and don't maintain a background
+// queue of their own, so "queue depth" here is the number of Export calls
+// currently in flight through this wrapper - a caller exporting from many
+// goroutines at once (e.g. one per in-flight request under Middleware)
+// produces exactly the backlog this is meant to shed.
+type AdaptiveExporter struct {
+	next Exporter
+
+	truncateAt     int
+	metadataOnlyAt int
+	recoveryMargin int
+	truncateBytes  int
+
+	mu       sync.Mutex
+	level    CaptureLevel
+	inFlight int64
+}
+
+// NewAdaptiveExporter wraps next with queue-depth-aware capture shedding.
+func NewAdaptiveExporter(next Exporter, opts ...AdaptiveExporterOption) *AdaptiveExporter {
+	e := &AdaptiveExporter{
+		next:           next,
+		truncateAt:     8,
+		metadataOnlyAt: 32,
+		recoveryMargin: 4,
+		truncateBytes:  defaultAdaptiveTruncateBytes,
+		level:          CaptureFull,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var _ Exporter = (*AdaptiveExporter)(nil)
+
+// Export downgrades trace's spans to the capture level implied by the
+// current in-flight depth (with hysteresis, see WithRecoveryMargin) before
+// handing it to the wrapped Exporter.
+func (e *AdaptiveExporter) Export(ctx context.Context, trace *Trace) error {
+	depth := atomic.AddInt64(&e.inFlight, 1)
+	defer atomic.AddInt64(&e.inFlight, -1)
+
+	level := e.levelFor(int(depth))
+	for _, span := range trace.Spans() {
+		span.applyCaptureLevel(level, e.truncateBytes)
+	}
+	return e.next.Export(ctx, trace)
+}
+
+// Level reports the capture level AdaptiveExporter is currently applying.
+func (e *AdaptiveExporter) Level() CaptureLevel {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.level
+}
+
+func (e *AdaptiveExporter) levelFor(depth int) CaptureLevel {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch e.level {
+	case CaptureFull:
+		if depth >= e.metadataOnlyAt {
+			e.level = CaptureMetadataOnly
+		} else if depth >= e.truncateAt {
+			e.level = CaptureTruncated
+		}
+	case CaptureTruncated:
+		if depth >= e.metadataOnlyAt {
+			e.level = CaptureMetadataOnly
+		} else if depth < e.truncateAt-e.recoveryMargin {
+			e.level = CaptureFull
+		}
+	case CaptureMetadataOnly:
+		if depth < e.metadataOnlyAt-e.recoveryMargin {
+			e.level = CaptureTruncated
+		}
+	}
+	return e.level
+}
+
+// applyCaptureLevel mutates the span's recorded content to match level,
+// stamping which level was applied so it's visible on the exported span.
+// Errors, metrics and params are left alone at every level: they're
+// structural/diagnostic, not the heavy user content this is meant to shed.
+func (s *Span) applyCaptureLevel(level CaptureLevel, truncateBytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch level {
+	case CaptureTruncated:
+		if s.input != nil {
+			inputCategory := CategoryInput
+			if s.spanType == SpanTypeTool {
+				inputCategory = CategoryToolArgs
+			}
+			truncateTypedValue(s.input, truncateBytes, inputCategory)
+		}
+		for i := range s.outputs {
+			truncateTypedValue(&s.outputs[i], truncateBytes, CategoryOutput)
+		}
+	case CaptureMetadataOnly:
+		s.input = nil
+		s.outputs = nil
+	}
+
+	if level != CaptureFull {
+		if s.metadata == nil {
+			s.metadata = map[string]string{}
+		}
+		s.metadata[metadataCaptureLevel] = string(level)
+	}
+}
@@ -0,0 +1,139 @@
+package langwatch
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// blockingExporter blocks every Export call until release is closed, so a
+// test can control how many calls are in flight through an AdaptiveExporter
+// at once. It signals entered as soon as a call arrives, i.e. after
+// AdaptiveExporter has already applied its capture-level decision, so a
+// test can wait for a specific number of calls to be in flight before
+// inspecting or adding to them.
+type blockingExporter struct {
+	release chan struct{}
+	entered chan struct{}
+
+	mu      sync.Mutex
+	exports []*Trace
+}
+
+func (e *blockingExporter) Export(ctx context.Context, trace *Trace) error {
+	e.entered <- struct{}{}
+	<-e.release
+	e.mu.Lock()
+	e.exports = append(e.exports, trace)
+	e.mu.Unlock()
+	return nil
+}
+
+func newTraceWithSpan(t *testing.T, text string) *Trace {
+	t.Helper()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue(text))
+	span.End()
+	return trace
+}
+
+func TestAdaptiveExporterStaysFullUnderLightLoad(t *testing.T) {
+	next := &blockingExporter{release: make(chan struct{}), entered: make(chan struct{}, 1)}
+	close(next.release)
+	e := NewAdaptiveExporter(next, WithCaptureThresholds(8, 32))
+
+	trace := newTraceWithSpan(t, "hello")
+	if err := e.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if e.Level() != CaptureFull {
+		t.Fatalf("Level() = %v, want %v", e.Level(), CaptureFull)
+	}
+	if trace.Spans()[0].toRecord().Input.Value != "hello" {
+		t.Fatal("expected input to survive under CaptureFull")
+	}
+}
+
+func TestAdaptiveExporterDowngradesToMetadataOnlyUnderLoad(t *testing.T) {
+	next := &blockingExporter{release: make(chan struct{}), entered: make(chan struct{}, 4)}
+	e := NewAdaptiveExporter(next, WithCaptureThresholds(2, 4), WithRecoveryMargin(1), WithAdaptiveTruncateBytes(3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = e.Export(context.Background(), newTraceWithSpan(t, "background"))
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-next.entered
+	}
+
+	// With 3 calls already in flight, this 4th call deterministically sees
+	// depth 4 against thresholds (2, 4) and should be captured metadata-only.
+	fourth := newTraceWithSpan(t, "a long input string")
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = e.Export(context.Background(), fourth)
+	}()
+	<-next.entered
+
+	record := fourth.Spans()[0].toRecord()
+	if record.Input != nil {
+		t.Fatalf("expected input to be dropped under metadata-only, got %+v", record.Input)
+	}
+	if record.Metadata[metadataCaptureLevel] != string(CaptureMetadataOnly) {
+		t.Fatalf("expected the active capture level to be stamped, got %+v", record.Metadata)
+	}
+	if e.Level() != CaptureMetadataOnly {
+		t.Fatalf("Level() = %v, want %v", e.Level(), CaptureMetadataOnly)
+	}
+
+	close(next.release)
+	wg.Wait()
+}
+
+func TestAdaptiveExporterRecoversPastMarginOnceLoadDrains(t *testing.T) {
+	next := &blockingExporter{release: make(chan struct{}), entered: make(chan struct{}, 4)}
+	e := NewAdaptiveExporter(next, WithCaptureThresholds(3, 6), WithRecoveryMargin(1))
+
+	// Drive depth up to 3, escalating to truncated (>= 3, staying below the
+	// metadata-only threshold of 6).
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = e.Export(context.Background(), newTraceWithSpan(t, "background"))
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-next.entered
+	}
+	if e.Level() != CaptureTruncated {
+		t.Fatalf("Level() = %v, want %v once 3 exports are in flight", e.Level(), CaptureTruncated)
+	}
+
+	close(next.release)
+	wg.Wait()
+
+	// Depth is back to 0; a fresh call sees depth 1, below
+	// truncateAt(3) - recoveryMargin(1) = 2, so it should recover to full.
+	next2 := &blockingExporter{release: make(chan struct{}), entered: make(chan struct{}, 1)}
+	close(next2.release)
+	e.next = next2
+
+	recovered := newTraceWithSpan(t, "hello again")
+	if err := e.Export(context.Background(), recovered); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if e.Level() != CaptureFull {
+		t.Fatalf("Level() = %v, want %v after load drains", e.Level(), CaptureFull)
+	}
+	if recovered.Spans()[0].toRecord().Input.Value != "hello again" {
+		t.Fatal("expected input to be restored under CaptureFull")
+	}
+}
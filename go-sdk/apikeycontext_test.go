@@ -0,0 +1,108 @@
+package langwatch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyFromContextRoundTrip(t *testing.T) {
+	if _, ok := APIKeyFromContext(context.Background()); ok {
+		t.Fatal("expected no API key on a bare context")
+	}
+
+	ctx := ContextWithAPIKey(context.Background(), "tenant-a-key")
+	key, ok := APIKeyFromContext(ctx)
+	if !ok || key != "tenant-a-key" {
+		t.Fatalf("APIKeyFromContext = (%q, %v), want (%q, true)", key, ok, "tenant-a-key")
+	}
+}
+
+func TestResolveAPIKeyPrefersContextOverConfigured(t *testing.T) {
+	if got := resolveAPIKey(context.Background(), "configured-key"); got != "configured-key" {
+		t.Fatalf("resolveAPIKey = %q, want %q", got, "configured-key")
+	}
+
+	ctx := ContextWithAPIKey(context.Background(), "tenant-key")
+	if got := resolveAPIKey(ctx, "configured-key"); got != "tenant-key" {
+		t.Fatalf("resolveAPIKey = %q, want %q", got, "tenant-key")
+	}
+}
+
+func TestExporterUsesAPIKeyFromContextPerTenant(t *testing.T) {
+	var mu sync.Mutex
+	var seenKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seenKeys = append(seenKeys, r.Header.Get("X-Auth-Token"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "fallback-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(ContextWithAPIKey(context.Background(), "tenant-a-key"))
+	_, span := StartSpan(ctx, "op")
+	span.End()
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seenKeys) != 1 || seenKeys[0] != "tenant-a-key" {
+		t.Fatalf("seenKeys = %v, want [tenant-a-key]", seenKeys)
+	}
+}
+
+// keyRecordingExporter records the API key attached to each Export call's
+// ctx, distinguishing it from recordingExporter (which only records traces)
+// so BatchProcessor's per-tenant isolation can be asserted on its own.
+type keyRecordingExporter struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (e *keyRecordingExporter) Export(ctx context.Context, trace *Trace) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key, _ := APIKeyFromContext(ctx)
+	e.keys = append(e.keys, key)
+	return nil
+}
+
+func (e *keyRecordingExporter) snapshot() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]string, len(e.keys))
+	copy(out, e.keys)
+	return out
+}
+
+func TestBatchProcessorIsolatesAPIKeyPerEnqueuedTrace(t *testing.T) {
+	exporter := &keyRecordingExporter{}
+	p := NewBatchProcessor(exporter, WithFlushInterval(time.Hour))
+
+	p.Enqueue(ContextWithAPIKey(context.Background(), "tenant-a-key"), newTestTrace(t, false))
+	p.Enqueue(ContextWithAPIKey(context.Background(), "tenant-b-key"), newTestTrace(t, false))
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := exporter.snapshot()
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 exports, got %d", len(keys))
+	}
+	seen := map[string]bool{keys[0]: true, keys[1]: true}
+	if !seen["tenant-a-key"] || !seen["tenant-b-key"] {
+		t.Fatalf("keys = %v, want both tenant-a-key and tenant-b-key", keys)
+	}
+}
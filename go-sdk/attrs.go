@@ -0,0 +1,33 @@
+package langwatch
+
+import "context"
+
+// PushAttrs returns a context carrying attrs merged on top of any attrs
+// already attached to ctx, so every span started from the returned context
+// (and its descendants) picks them up automatically without threading them
+// through every function signature in a deep call stack.
+//
+// Because attrs live on the context, they scope naturally with it: once the
+// caller stops using the returned context (e.g. it goes out of scope at the
+// end of a request handler), spans started from the original ctx are
+// unaffected. There is no separate PopAttrs - that's just using ctx instead
+// of the value PushAttrs returned.
+func PushAttrs(ctx context.Context, attrs ...map[string]string) context.Context {
+	merged := map[string]string{}
+	for k, v := range AttrsFromContext(ctx) {
+		merged[k] = v
+	}
+	for _, m := range attrs {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return context.WithValue(ctx, attrsContextKey, merged)
+}
+
+// AttrsFromContext returns the attributes accumulated on ctx via PushAttrs,
+// or nil if none have been pushed.
+func AttrsFromContext(ctx context.Context) map[string]string {
+	attrs, _ := ctx.Value(attrsContextKey).(map[string]string)
+	return attrs
+}
@@ -0,0 +1,38 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPushAttrsAppliedToDescendantSpans(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx = PushAttrs(ctx, map[string]string{"tenant": "acme"})
+
+	_, span := StartSpan(ctx, "step")
+
+	if got := span.metadata["tenant"]; got != "acme" {
+		t.Fatalf("expected span to inherit pushed attr, got %q", got)
+	}
+}
+
+func TestPushAttrsMergesAndOverrides(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx = PushAttrs(ctx, map[string]string{"tenant": "acme", "region": "eu"})
+	ctx = PushAttrs(ctx, map[string]string{"tenant": "globex"})
+
+	attrs := AttrsFromContext(ctx)
+	if attrs["tenant"] != "globex" || attrs["region"] != "eu" {
+		t.Fatalf("expected merged attrs with override, got %v", attrs)
+	}
+}
+
+func TestPushAttrsDoesNotLeakToOriginalContext(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	pushed := PushAttrs(ctx, map[string]string{"tenant": "acme"})
+	_ = pushed
+
+	if AttrsFromContext(ctx) != nil {
+		t.Fatal("expected the original context to be unaffected by PushAttrs")
+	}
+}
@@ -0,0 +1,109 @@
+package langwatch
+
+import "encoding/json"
+
+// requiredSpanAttributes is this SDK's published contract for which fields
+// a SpanRecord of a given SpanType is expected to carry - e.g. an LLM span
+// without a model is almost always an instrumentation bug, not a valid
+// span. It's the single source both ValidateSpanRecord and
+// SpanAttributeJSONSchema read from, so the two can never drift apart.
+var requiredSpanAttributes = map[SpanType][]string{
+	SpanTypeLLM:       {"vendor", "model"},
+	SpanTypeRAG:       {"contexts"},
+	SpanTypeTool:      {"input"},
+	SpanTypeGuardrail: {"input"},
+}
+
+// ValidateSpanRecord checks record against requiredSpanAttributes and
+// returns the name of every required field missing for its SpanType. It
+// returns nil for a SpanType with no declared requirements (SpanTypeSpan,
+// SpanTypeChain, SpanTypeAgent - these are general-purpose and carry no
+// fields this SDK considers mandatory).
+func ValidateSpanRecord(record SpanRecord) []string {
+	var missing []string
+	for _, field := range requiredSpanAttributes[record.Type] {
+		if !hasSpanAttribute(record, field) {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+func hasSpanAttribute(record SpanRecord, field string) bool {
+	switch field {
+	case "vendor":
+		return record.Vendor != ""
+	case "model":
+		return record.Model != ""
+	case "input":
+		return record.Input != nil
+	case "contexts":
+		return len(record.Contexts) > 0
+	default:
+		return true
+	}
+}
+
+// jsonSchemaSpanType is one SpanType's entry in the document
+// SpanAttributeJSONSchema returns.
+type jsonSchemaSpanType struct {
+	Required []string `json:"required,omitempty"`
+}
+
+// SpanAttributeJSONSchema renders requiredSpanAttributes as a small JSON
+// Schema-shaped document (one entry per SpanType with declared
+// requirements, each listing its "required" field names), so the attribute
+// contract can be published, diffed, or handed to a non-Go consumer instead
+// of only living as Go source.
+func SpanAttributeJSONSchema() ([]byte, error) {
+	definitions := make(map[string]jsonSchemaSpanType, len(requiredSpanAttributes))
+	for spanType, fields := range requiredSpanAttributes {
+		definitions[string(spanType)] = jsonSchemaSpanType{Required: fields}
+	}
+
+	doc := struct {
+		Schema      string                        `json:"$schema"`
+		Title       string                        `json:"title"`
+		Definitions map[string]jsonSchemaSpanType `json:"definitions"`
+	}{
+		Schema:      "http://json-schema.org/draft-07/schema#",
+		Title:       "LangWatch Go SDK span attribute contract",
+		Definitions: definitions,
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// validateAttributes checks s's fields against requiredSpanAttributes and,
+// when the SDK's debug logging is enabled, warns about anything missing.
+// It's a dev-mode aid for catching instrumentation bugs (an LLM span with
+// no model set) before they reach production, not a hard failure - it
+// never blocks export. Must be called with s.mu held, mirroring checkSLO;
+// it inspects s's fields directly rather than through toRecord to avoid
+// re-locking s.mu.
+func (s *Span) validateAttributes() {
+	if !debugEnabled() {
+		return
+	}
+
+	var missing []string
+	for _, field := range requiredSpanAttributes[s.spanType] {
+		present := true
+		switch field {
+		case "vendor":
+			present = s.vendor != ""
+		case "model":
+			present = s.model != ""
+		case "input":
+			present = s.input != nil
+		case "contexts":
+			present = len(s.contexts) > 0
+		}
+		if !present {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	debugLog("span %q (type %s) is missing expected attribute(s): %v", s.name, s.spanType, missing)
+}
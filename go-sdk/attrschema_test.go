@@ -0,0 +1,96 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidateSpanRecordFlagsLLMSpanWithoutModel(t *testing.T) {
+	missing := ValidateSpanRecord(SpanRecord{Type: SpanTypeLLM})
+	if len(missing) != 2 {
+		t.Fatalf("missing = %v, want vendor and model", missing)
+	}
+}
+
+func TestValidateSpanRecordPassesCompleteLLMSpan(t *testing.T) {
+	missing := ValidateSpanRecord(SpanRecord{Type: SpanTypeLLM, Vendor: "openai", Model: "gpt-4o"})
+	if len(missing) != 0 {
+		t.Fatalf("missing = %v, want none", missing)
+	}
+}
+
+func TestValidateSpanRecordHasNoRequirementsForGenericSpan(t *testing.T) {
+	missing := ValidateSpanRecord(SpanRecord{Type: SpanTypeSpan})
+	if len(missing) != 0 {
+		t.Fatalf("missing = %v, want none", missing)
+	}
+}
+
+func TestValidateSpanRecordFlagsRAGSpanWithoutContexts(t *testing.T) {
+	missing := ValidateSpanRecord(SpanRecord{Type: SpanTypeRAG})
+	if len(missing) != 1 || missing[0] != "contexts" {
+		t.Fatalf("missing = %v, want [contexts]", missing)
+	}
+}
+
+func TestSpanAttributeJSONSchemaIsValidJSONWithLLMRequirements(t *testing.T) {
+	doc, err := SpanAttributeJSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		Schema      string `json:"$schema"`
+		Definitions map[string]struct {
+			Required []string `json:"required"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+	if parsed.Schema == "" {
+		t.Fatal("expected a $schema URI")
+	}
+	llm, ok := parsed.Definitions[string(SpanTypeLLM)]
+	if !ok || len(llm.Required) != 2 {
+		t.Fatalf("llm definition = %+v", llm)
+	}
+}
+
+func TestValidateAttributesWarnsInDebugMode(t *testing.T) {
+	os.Setenv(EnvDebug, "true")
+	defer os.Unsetenv(EnvDebug)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	span.End()
+
+	if !strings.Contains(buf.String(), "model") {
+		t.Fatalf("expected a warning mentioning the missing model attribute, got %q", buf.String())
+	}
+}
+
+func TestValidateAttributesSilentOutsideDebugMode(t *testing.T) {
+	os.Unsetenv(EnvDebug)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	span.End()
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected no log output outside debug mode, got %q", buf.String())
+	}
+}
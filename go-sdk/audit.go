@@ -0,0 +1,81 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// metadataAudit is the metadata key an AuditRecord is stamped onto when no
+// AuditSink is configured, so the audit trail still travels with the trace
+// as a span-level annotation instead of being dropped.
+const metadataAudit = "langwatch.audit"
+
+// AuditRecord proves what content-capture decisions were made for a single
+// span at export time: whether capture was enabled, which metadata keys
+// were redacted, and which attributes were truncated for size.
+type AuditRecord struct {
+	TraceID         string    `json:"trace_id"`
+	SpanID          string    `json:"span_id"`
+	CaptureEnabled  bool      `json:"capture_enabled"`
+	RedactedKeys    []string  `json:"redacted_keys,omitempty"`
+	TruncatedFields []string  `json:"truncated_fields,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// AuditSink receives an AuditRecord for every span exported while audit
+// logging is enabled (see WithAuditLog). Implementations should return
+// quickly: RecordAudit is called synchronously on the exporting goroutine.
+type AuditSink interface {
+	RecordAudit(ctx context.Context, record AuditRecord)
+}
+
+var auditSink AuditSink
+
+// SetAuditSink registers sink to receive every AuditRecord going forward,
+// in place of the default of stamping the record onto the span's own
+// metadata. Intended to be called once at startup.
+func SetAuditSink(sink AuditSink) {
+	auditSink = sink
+}
+
+// redactMetadata removes each key in deniedKeys from record's metadata,
+// returning the keys that were actually present and removed.
+func redactMetadata(record *SpanRecord, deniedKeys []string) []string {
+	if len(deniedKeys) == 0 || record.Metadata == nil {
+		return nil
+	}
+	var redacted []string
+	for _, key := range deniedKeys {
+		if _, ok := record.Metadata[key]; ok {
+			delete(record.Metadata, key)
+			redacted = append(redacted, key)
+		}
+	}
+	return redacted
+}
+
+// emitAudit dispatches an AuditRecord to auditSink if one is configured, or
+// stamps it onto record's own metadata otherwise.
+func emitAudit(ctx context.Context, record *SpanRecord, traceID string, captureEnabled bool, redacted, truncated []string) {
+	entry := AuditRecord{
+		TraceID:         traceID,
+		SpanID:          record.ID,
+		CaptureEnabled:  captureEnabled,
+		RedactedKeys:    redacted,
+		TruncatedFields: truncated,
+		Timestamp:       time.Now(),
+	}
+	if auditSink != nil {
+		auditSink.RecordAudit(ctx, entry)
+		return
+	}
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if record.Metadata == nil {
+		record.Metadata = map[string]string{}
+	}
+	record.Metadata[metadataAudit] = string(body)
+}
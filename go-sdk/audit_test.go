@@ -0,0 +1,67 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type fakeAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *fakeAuditSink) RecordAudit(ctx context.Context, record AuditRecord) {
+	s.records = append(s.records, record)
+}
+
+func TestRedactMetadataRemovesDeniedKeys(t *testing.T) {
+	record := SpanRecord{Metadata: map[string]string{"keep": "1", "drop": "2"}}
+	redacted := redactMetadata(&record, []string{"drop", "missing"})
+
+	if len(redacted) != 1 || redacted[0] != "drop" {
+		t.Fatalf("redacted = %v, want [drop]", redacted)
+	}
+	if _, ok := record.Metadata["drop"]; ok {
+		t.Fatal("expected drop to be removed from metadata")
+	}
+	if record.Metadata["keep"] != "1" {
+		t.Fatal("expected keep to be left untouched")
+	}
+}
+
+func TestEmitAuditStampsMetadataWithoutSink(t *testing.T) {
+	auditSink = nil
+	record := SpanRecord{ID: "span-1"}
+	emitAudit(context.Background(), &record, "trace-1", true, []string{"secret"}, []string{"input"})
+
+	raw, ok := record.Metadata[metadataAudit]
+	if !ok {
+		t.Fatal("expected an audit record to be stamped onto metadata")
+	}
+	var got AuditRecord
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("failed to unmarshal audit record: %v", err)
+	}
+	if got.TraceID != "trace-1" || got.SpanID != "span-1" || !got.CaptureEnabled {
+		t.Fatalf("unexpected audit record: %+v", got)
+	}
+	if len(got.RedactedKeys) != 1 || got.RedactedKeys[0] != "secret" {
+		t.Fatalf("unexpected RedactedKeys: %v", got.RedactedKeys)
+	}
+}
+
+func TestEmitAuditUsesConfiguredSink(t *testing.T) {
+	sink := &fakeAuditSink{}
+	SetAuditSink(sink)
+	defer SetAuditSink(nil)
+
+	record := SpanRecord{ID: "span-1"}
+	emitAudit(context.Background(), &record, "trace-1", false, nil, nil)
+
+	if len(sink.records) != 1 {
+		t.Fatalf("expected 1 record delivered to the sink, got %d", len(sink.records))
+	}
+	if _, ok := record.Metadata[metadataAudit]; ok {
+		t.Fatal("expected metadata to be left untouched when a sink is configured")
+	}
+}
@@ -0,0 +1,199 @@
+package langwatch
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metadata keys AutoReviewRule conditions read, and the one they write on a
+// match.
+const (
+	metadataEvaluatorScore = "langwatch.evaluator.score"
+	metadataFinishReason   = "langwatch.finish_reason"
+	metadataReviewRule     = "langwatch.review.rule"
+)
+
+// RecordEvaluatorScore records a score assigned by an evaluator (an LLM
+// judge, a classifier, a heuristic) grading this span's output, so an
+// AutoReviewRule's MaxEvaluatorScore condition has something to compare
+// against.
+func (s *Span) RecordEvaluatorScore(score float64) {
+	if s.noop {
+		return
+	}
+	s.SetMetadata(metadataEvaluatorScore, strconv.FormatFloat(score, 'f', -1, 64))
+}
+
+// RecordFinishReason records the reason the model stopped generating (e.g.
+// "stop", "length", "content_filter"), so an AutoReviewRule's FinishReason
+// condition has something to compare against.
+func (s *Span) RecordFinishReason(reason string) {
+	if s.noop {
+		return
+	}
+	s.SetMetadata(metadataFinishReason, reason)
+}
+
+// AutoReviewRule is a locally evaluated condition, checked against every
+// span when it ends, that automatically files a human review request
+// through the ReviewClient configured with ConfigureAutoReview when it
+// matches. Register rules with RegisterAutoReviewRule.
+//
+// Every condition field that's set must match for the rule to fire; a rule
+// with no condition set never matches.
+type AutoReviewRule struct {
+	// Name identifies the rule. It's recorded as the langwatch.review.rule
+	// metadata on the matching span and, if Reason is empty, used as the
+	// review request's reason, so a reviewer knows why a trace was flagged.
+	Name string
+
+	// MaxEvaluatorScore, if set, matches spans whose RecordEvaluatorScore
+	// value is at or below the threshold.
+	MaxEvaluatorScore *float64
+	// FinishReason, if non-empty, matches spans whose RecordFinishReason
+	// value equals it exactly.
+	FinishReason string
+	// OutputContains, if non-empty, matches spans whose output text
+	// contains it (case-insensitive).
+	OutputContains string
+
+	// Reason overrides the review request's reason; defaults to Name.
+	Reason string
+	// Assignees, if set, are passed through to ReviewClient.Request.
+	Assignees []string
+	// DailyCap limits how many review requests this rule can file per UTC
+	// calendar day. Zero means unlimited.
+	DailyCap int
+}
+
+// matches reports whether s satisfies every condition rule sets. Must be
+// called with s.mu held.
+func (r AutoReviewRule) matches(s *Span) bool {
+	matched := false
+
+	if r.MaxEvaluatorScore != nil {
+		score, err := strconv.ParseFloat(s.metadata[metadataEvaluatorScore], 64)
+		if err != nil || score > *r.MaxEvaluatorScore {
+			return false
+		}
+		matched = true
+	}
+	if r.FinishReason != "" {
+		if s.metadata[metadataFinishReason] != r.FinishReason {
+			return false
+		}
+		matched = true
+	}
+	if r.OutputContains != "" {
+		if !strings.Contains(strings.ToLower(outputText(s.outputs)), strings.ToLower(r.OutputContains)) {
+			return false
+		}
+		matched = true
+	}
+
+	return matched
+}
+
+// outputText concatenates the plain-text values of outputs, ignoring
+// non-string values (chat messages, JSON payloads) since those don't have
+// a single natural string to search.
+func outputText(outputs []TypedValue) string {
+	var b strings.Builder
+	for _, o := range outputs {
+		if text, ok := o.Value.(string); ok {
+			b.WriteString(text)
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+// autoReviewRegistry is the process-wide set of AutoReviewRules checked at
+// the end of every span, mirroring capabilityRegistry's shape: package
+// state guarded by a mutex, since rules apply SDK-wide rather than to one
+// span or trace.
+var autoReviewRegistry = struct {
+	mu     sync.Mutex
+	rules  []AutoReviewRule
+	client *ReviewClient
+	// dailyCounts tracks how many requests each rule has filed today,
+	// keyed by rule name and UTC calendar day.
+	dailyCounts map[string]int
+}{}
+
+// ConfigureAutoReview sets the ReviewClient that RegisterAutoReviewRule
+// matches are filed through. Rules are evaluated regardless, but file
+// nothing until this is called.
+func ConfigureAutoReview(client *ReviewClient) {
+	autoReviewRegistry.mu.Lock()
+	defer autoReviewRegistry.mu.Unlock()
+	autoReviewRegistry.client = client
+}
+
+// RegisterAutoReviewRule registers rule to be checked against every span
+// when it ends, for the remainder of the process's lifetime.
+func RegisterAutoReviewRule(rule AutoReviewRule) {
+	autoReviewRegistry.mu.Lock()
+	defer autoReviewRegistry.mu.Unlock()
+	autoReviewRegistry.rules = append(autoReviewRegistry.rules, rule)
+}
+
+func dailyCapKey(ruleName string) string {
+	return ruleName + "@" + time.Now().UTC().Format("2006-01-02")
+}
+
+// checkAutoReviewRules evaluates every registered rule against s and files
+// a review request for each match, subject to its daily cap. Must be
+// called with s.mu held, mirroring checkSLO.
+func (s *Span) checkAutoReviewRules() {
+	autoReviewRegistry.mu.Lock()
+	rules := autoReviewRegistry.rules
+	client := autoReviewRegistry.client
+	autoReviewRegistry.mu.Unlock()
+	if client == nil {
+		return
+	}
+
+	for _, rule := range rules {
+		if !rule.matches(s) {
+			continue
+		}
+
+		if rule.DailyCap > 0 {
+			autoReviewRegistry.mu.Lock()
+			if autoReviewRegistry.dailyCounts == nil {
+				autoReviewRegistry.dailyCounts = map[string]int{}
+			}
+			key := dailyCapKey(rule.Name)
+			if autoReviewRegistry.dailyCounts[key] >= rule.DailyCap {
+				autoReviewRegistry.mu.Unlock()
+				continue
+			}
+			autoReviewRegistry.dailyCounts[key]++
+			autoReviewRegistry.mu.Unlock()
+		}
+
+		if s.metadata == nil {
+			s.metadata = map[string]string{}
+		}
+		s.metadata[metadataReviewRule] = rule.Name
+
+		reason := rule.Reason
+		if reason == "" {
+			reason = rule.Name
+		}
+		// Filing the request is a network call, so it's kept off the
+		// goroutine calling End - like SLO's OnViolation, that goroutine
+		// should stay quick. The request carries only the trace ID
+		// (context.WithValue with traceContextKey, not spanContextKey),
+		// so ReviewClient.Request never touches s and can't deadlock on
+		// the s.mu End already holds.
+		traceCtx := context.WithValue(context.Background(), traceContextKey, s.trace)
+		go func(reason string, assignees []string) {
+			_ = client.Request(traceCtx, reason, assignees...)
+		}(reason, rule.Assignees)
+	}
+}
@@ -0,0 +1,152 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func resetAutoReviewRegistry() {
+	autoReviewRegistry.mu.Lock()
+	defer autoReviewRegistry.mu.Unlock()
+	autoReviewRegistry.rules = nil
+	autoReviewRegistry.client = nil
+	autoReviewRegistry.dailyCounts = nil
+}
+
+func waitForReviewRequest(t *testing.T, got chan reviewRequestBody) reviewRequestBody {
+	t.Helper()
+	select {
+	case body := <-got:
+		return body
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for review request")
+		return reviewRequestBody{}
+	}
+}
+
+func newAutoReviewTestServer(t *testing.T) (*httptest.Server, chan reviewRequestBody) {
+	got := make(chan reviewRequestBody, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body reviewRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		got <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+func TestAutoReviewRuleFilesRequestOnMaxEvaluatorScoreMatch(t *testing.T) {
+	resetAutoReviewRegistry()
+	defer resetAutoReviewRegistry()
+
+	server, got := newAutoReviewTestServer(t)
+	ConfigureAutoReview(NewReviewClient(Config{APIKey: "key", Endpoint: server.URL}))
+	threshold := 0.5
+	RegisterAutoReviewRule(AutoReviewRule{Name: "low-confidence", MaxEvaluatorScore: &threshold})
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "answer")
+	span.RecordEvaluatorScore(0.2)
+	span.End()
+
+	body := waitForReviewRequest(t, got)
+	if body.TraceID != trace.ID() {
+		t.Fatalf("TraceID = %q, want %q", body.TraceID, trace.ID())
+	}
+	if body.Reason != "low-confidence" {
+		t.Fatalf("Reason = %q, want %q", body.Reason, "low-confidence")
+	}
+
+	record := span.toRecord()
+	if record.Metadata[metadataReviewRule] != "low-confidence" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataReviewRule, record.Metadata[metadataReviewRule], "low-confidence")
+	}
+}
+
+func TestAutoReviewRuleDoesNotFireWhenScoreAboveThreshold(t *testing.T) {
+	resetAutoReviewRegistry()
+	defer resetAutoReviewRegistry()
+
+	server, got := newAutoReviewTestServer(t)
+	ConfigureAutoReview(NewReviewClient(Config{APIKey: "key", Endpoint: server.URL}))
+	threshold := 0.5
+	RegisterAutoReviewRule(AutoReviewRule{Name: "low-confidence", MaxEvaluatorScore: &threshold})
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "answer")
+	span.RecordEvaluatorScore(0.9)
+	span.End()
+
+	select {
+	case body := <-got:
+		t.Fatalf("expected no review request, got %+v", body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAutoReviewRuleMatchesFinishReasonAndOutputContains(t *testing.T) {
+	resetAutoReviewRegistry()
+	defer resetAutoReviewRegistry()
+
+	server, got := newAutoReviewTestServer(t)
+	ConfigureAutoReview(NewReviewClient(Config{APIKey: "key", Endpoint: server.URL}))
+	RegisterAutoReviewRule(AutoReviewRule{Name: "truncated", FinishReason: "length"})
+	RegisterAutoReviewRule(AutoReviewRule{Name: "self-harm-phrase", OutputContains: "as an AI I cannot"})
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "answer")
+	span.RecordFinishReason("length")
+	span.RecordOutput(NewTextValue("Sorry, As an AI I cannot help with that."))
+	span.End()
+
+	first := waitForReviewRequest(t, got)
+	second := waitForReviewRequest(t, got)
+	reasons := map[string]bool{first.Reason: true, second.Reason: true}
+	if !reasons["truncated"] || !reasons["self-harm-phrase"] {
+		t.Fatalf("expected both rules to fire, got reasons %v", reasons)
+	}
+}
+
+func TestAutoReviewRuleRespectsDailyCap(t *testing.T) {
+	resetAutoReviewRegistry()
+	defer resetAutoReviewRegistry()
+
+	server, got := newAutoReviewTestServer(t)
+	ConfigureAutoReview(NewReviewClient(Config{APIKey: "key", Endpoint: server.URL}))
+	threshold := 0.5
+	RegisterAutoReviewRule(AutoReviewRule{Name: "capped", MaxEvaluatorScore: &threshold, DailyCap: 1})
+
+	ctx, _ := NewTrace(context.Background())
+
+	_, span1 := StartSpan(ctx, "answer")
+	span1.RecordEvaluatorScore(0.1)
+	span1.End()
+	waitForReviewRequest(t, got)
+
+	_, span2 := StartSpan(ctx, "answer")
+	span2.RecordEvaluatorScore(0.1)
+	span2.End()
+
+	select {
+	case body := <-got:
+		t.Fatalf("expected daily cap to suppress second request, got %+v", body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAutoReviewRuleDoesNothingWithoutConfiguredClient(t *testing.T) {
+	resetAutoReviewRegistry()
+	defer resetAutoReviewRegistry()
+
+	RegisterAutoReviewRule(AutoReviewRule{Name: "no-client", OutputContains: "x"})
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "answer")
+	span.RecordOutput(NewTextValue("x"))
+	span.End()
+}
@@ -0,0 +1,116 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BackfillSpan describes a single historical call to be reported after the
+// fact - for example a completion a third-party webhook tells us about well
+// after it actually happened, which we never had a live context.Context to
+// instrument with StartSpan.
+type BackfillSpan struct {
+	// TraceID links this span into an existing trace. Backfill uses the
+	// first non-empty TraceID among the spans passed to it; if every span's
+	// TraceID is empty, a new trace ID is generated.
+	TraceID string
+	// ID uniquely identifies this span. If empty, one is generated.
+	ID string
+	// ParentID optionally names another span (typically also part of this
+	// backfill) that this span is nested under.
+	ParentID string
+
+	Name string
+	Type SpanType
+
+	Vendor string
+	Model  string
+
+	Input   *TypedValue
+	Outputs []TypedValue
+	Error   *ErrorCapture
+	Metrics *Metrics
+	Params  *Params
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func (bs BackfillSpan) validate() error {
+	if bs.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if bs.StartedAt.IsZero() {
+		return fmt.Errorf("started_at is required")
+	}
+	if bs.FinishedAt.IsZero() {
+		return fmt.Errorf("finished_at is required")
+	}
+	if bs.FinishedAt.Before(bs.StartedAt) {
+		return fmt.Errorf("finished_at (%s) is before started_at (%s)", bs.FinishedAt, bs.StartedAt)
+	}
+	return nil
+}
+
+// Backfill validates and exports a set of historical spans as a single
+// trace, for calls that happened in systems we can't instrument live. All
+// spans share the trace named by the first non-empty TraceID found among
+// them (or a newly generated one), so passing an existing trace's ID links
+// the backfilled calls into it instead of creating a new trace.
+func Backfill(ctx context.Context, exporter Exporter, spans []BackfillSpan) error {
+	if len(spans) == 0 {
+		return fmt.Errorf("langwatch: backfill requires at least one span")
+	}
+
+	traceID := spans[0].TraceID
+	for _, bs := range spans {
+		if bs.TraceID != "" {
+			traceID = bs.TraceID
+			break
+		}
+	}
+	if traceID == "" {
+		traceID = newTraceID()
+	}
+	trace := &Trace{id: traceID}
+
+	for i, bs := range spans {
+		if err := bs.validate(); err != nil {
+			return fmt.Errorf("langwatch: backfill span %d: %w", i, err)
+		}
+
+		id := bs.ID
+		if id == "" {
+			id = newSpanID()
+		}
+		spanType := bs.Type
+		if spanType == "" {
+			spanType = SpanTypeSpan
+		}
+
+		s := &Span{
+			trace:          trace,
+			id:             id,
+			name:           bs.Name,
+			spanType:       spanType,
+			startedAt:      bs.StartedAt,
+			finishedAt:     bs.FinishedAt,
+			ended:          true,
+			input:          bs.Input,
+			outputs:        bs.Outputs,
+			err:            bs.Error,
+			vendor:         bs.Vendor,
+			model:          bs.Model,
+			params:         bs.Params,
+			metrics:        bs.Metrics,
+			captureEnabled: true,
+		}
+		if bs.ParentID != "" {
+			s.parent = &Span{id: bs.ParentID}
+		}
+		trace.addSpan(s)
+	}
+
+	return exporter.Export(ctx, trace)
+}
@@ -0,0 +1,54 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeExporter struct {
+	trace *Trace
+}
+
+func (f *fakeExporter) Export(ctx context.Context, trace *Trace) error {
+	f.trace = trace
+	return nil
+}
+
+func TestBackfillExportsSpanWithExplicitTimestamps(t *testing.T) {
+	exporter := &fakeExporter{}
+	started := time.Now().Add(-time.Hour)
+	finished := started.Add(time.Second)
+
+	err := Backfill(context.Background(), exporter, []BackfillSpan{
+		{TraceID: "trace-123", Name: "webhook-completion", Type: SpanTypeLLM, StartedAt: started, FinishedAt: finished},
+	})
+	if err != nil {
+		t.Fatalf("Backfill returned error: %v", err)
+	}
+	if exporter.trace.ID() != "trace-123" {
+		t.Fatalf("expected trace to be linked to trace-123, got %q", exporter.trace.ID())
+	}
+	spans := exporter.trace.Spans()
+	if len(spans) != 1 || spans[0].toRecord().Timestamps.StartedAt != started.UnixMilli() {
+		t.Fatalf("expected the backfilled span's timestamps to be preserved")
+	}
+}
+
+func TestBackfillRejectsFinishedBeforeStarted(t *testing.T) {
+	exporter := &fakeExporter{}
+	now := time.Now()
+
+	err := Backfill(context.Background(), exporter, []BackfillSpan{
+		{Name: "bad", StartedAt: now, FinishedAt: now.Add(-time.Minute)},
+	})
+	if err == nil {
+		t.Fatal("expected an error for finished_at before started_at")
+	}
+}
+
+func TestBackfillRequiresAtLeastOneSpan(t *testing.T) {
+	if err := Backfill(context.Background(), &fakeExporter{}, nil); err == nil {
+		t.Fatal("expected an error for an empty span list")
+	}
+}
@@ -0,0 +1,73 @@
+package langwatch
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SpanExportError reports that one batch of spans was rejected by the
+// collector, naming the spans that were part of it and the response status,
+// so a caller can tell exactly what was dropped instead of getting an
+// all-or-nothing error for the whole trace.
+type SpanExportError struct {
+	SpanIDs []string
+	Status  int
+}
+
+func (e *SpanExportError) Error() string {
+	return fmt.Sprintf("langwatch: %d span(s) rejected with status %d", len(e.SpanIDs), e.Status)
+}
+
+// BatchExportError aggregates the batches that failed during a partially
+// successful Export call. Spans not named in any Failures entry were
+// exported successfully.
+type BatchExportError struct {
+	Failures []*SpanExportError
+}
+
+func (e *BatchExportError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		parts[i] = f.Error()
+	}
+	return fmt.Sprintf("langwatch: export partially failed for %d batch(es): %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// splitIntoBatches groups records into chunks whose combined serialized size
+// stays under maxBytes. maxBytes <= 0 disables splitting (a single batch).
+func splitIntoBatches(records []SpanRecord, maxBytes int) [][]SpanRecord {
+	if maxBytes <= 0 || len(records) == 0 {
+		return [][]SpanRecord{records}
+	}
+
+	var batches [][]SpanRecord
+	var current []SpanRecord
+	currentSize := 0
+	for _, r := range records {
+		size := jsonSize(r)
+		if len(current) > 0 && currentSize+size > maxBytes {
+			batches = append(batches, current)
+			current = nil
+			currentSize = 0
+		}
+		current = append(current, r)
+		currentSize += size
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+func spanIDs(records []SpanRecord) []string {
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+	return ids
+}
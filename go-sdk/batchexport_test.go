@@ -0,0 +1,108 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExportSplitsIntoBatchesBySize(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	for i := 0; i < 5; i++ {
+		_, span := StartSpan(ctx, "span")
+		span.RecordInput(NewTextValue("x"))
+		span.End()
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL}, WithMaxBatchBytes(1))
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("expected export to succeed, got %v", err)
+	}
+	if requests != 5 {
+		t.Fatalf("expected one request per span with a tiny batch limit, got %d", requests)
+	}
+}
+
+func TestExportReportsFailedBatchesAsBatchExportError(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, ok := StartSpan(ctx, "ok")
+	ok.End()
+	_, bad := StartSpan(ctx, "bad")
+	bad.End()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	_ = ok
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL}, WithMaxBatchBytes(1))
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("expected export to succeed against an always-200 server, got %v", err)
+	}
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+
+	failingExporter, err := NewExporter(Config{APIKey: "key", Endpoint: failingServer.URL}, WithMaxBatchBytes(1))
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	err = failingExporter.Export(ctx, trace)
+	if err == nil {
+		t.Fatal("expected a BatchExportError")
+	}
+	var batchErr *BatchExportError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *BatchExportError, got %T: %v", err, err)
+	}
+	if len(batchErr.Failures) != 2 {
+		t.Fatalf("expected both single-span batches to fail, got %d", len(batchErr.Failures))
+	}
+}
+
+func TestExportBatchRetriesOnRetryableStatus(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "flaky")
+	span.End()
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL}, WithBatchRetries(2))
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
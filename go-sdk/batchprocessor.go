@@ -0,0 +1,246 @@
+package langwatch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metadataGuardrailFailed marks a span whose automated evaluation or
+// guardrail check failed, e.g. an output that tripped a content filter or
+// failed a scored eval. BatchProcessor treats these like error spans: they
+// are retained under backpressure instead of being shed as low-value.
+const metadataGuardrailFailed = "langwatch.guardrail.failed"
+
+// EventSpanDropped is published whenever BatchProcessor sheds a trace under
+// backpressure instead of exporting it.
+const EventSpanDropped EventType = "span_dropped"
+
+// RecordGuardrailFailed marks span as having failed an automated evaluation
+// or guardrail check, so BatchProcessor retains its trace under
+// backpressure the same way it would an error span. span accepts a
+// SpanRecorder so callers can pass a test fake in unit tests.
+func RecordGuardrailFailed(span SpanRecorder) {
+	span.SetMetadata(metadataGuardrailFailed, "true")
+}
+
+// tracePriority reports whether trace is high priority: it contains an
+// error span or a span flagged as a failed evaluation/guardrail. High
+// priority traces are retained under backpressure; everything else is
+// shed first.
+func tracePriority(trace *Trace) bool {
+	for _, s := range trace.Spans() {
+		s.mu.Lock()
+		highPriority := s.err != nil || s.metadata[metadataGuardrailFailed] == "true"
+		s.mu.Unlock()
+		if highPriority {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchProcessorStats reports how a BatchProcessor has performed since it
+// was created.
+type BatchProcessorStats struct {
+	Enqueued     int64
+	Exported     int64
+	ExportErrors int64
+	DroppedLow   int64
+	DroppedHigh  int64
+}
+
+// enqueuedTrace pairs a buffered trace with the API key attached to its
+// Enqueue call's ctx, if any. The processor's flush loop runs on its own
+// background ctx (see run), so a per-tenant key from ContextWithAPIKey has
+// to be captured at Enqueue time or it would be lost by the time the trace
+// is actually exported, potentially alongside other tenants' traces.
+type enqueuedTrace struct {
+	trace  *Trace
+	apiKey string
+	hasKey bool
+}
+
+// BatchProcessor buffers completed traces in memory and exports them on a
+// background goroutine, so callers on the request path aren't blocked on
+// the network. Its buffer is split into a high-priority lane (traces with
+// an error span, or a span flagged via metadataGuardrailFailed) and a
+// low-priority lane for everything else; when the buffer is full, low
+// priority traces are shed first, and only spill into shedding
+// high-priority traces once the low lane is already empty.
+//
+// A single BatchProcessor can be shared by a multi-tenant process serving
+// several LangWatch projects: Enqueue captures any API key attached to ctx
+// with ContextWithAPIKey, and flush re-attaches it before exporting, so
+// traces from different tenants batched together on the same flush tick
+// each still export under their own project.
+type BatchProcessor struct {
+	exporter      Exporter
+	laneCapacity  int
+	flushInterval time.Duration
+	batchSize     int
+
+	mu   sync.Mutex
+	high []enqueuedTrace
+	low  []enqueuedTrace
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	stats BatchProcessorStats
+}
+
+// BatchProcessorOption configures a BatchProcessor built with
+// NewBatchProcessor.
+type BatchProcessorOption func(*BatchProcessor)
+
+// WithLaneCapacity sets how many traces each priority lane buffers before
+// the processor starts shedding. Defaults to 1000.
+func WithLaneCapacity(n int) BatchProcessorOption {
+	return func(p *BatchProcessor) { p.laneCapacity = n }
+}
+
+// WithFlushInterval sets how often the processor exports buffered traces.
+// Defaults to one second.
+func WithFlushInterval(d time.Duration) BatchProcessorOption {
+	return func(p *BatchProcessor) { p.flushInterval = d }
+}
+
+// WithBatchSize sets the maximum number of traces exported per flush tick.
+// Defaults to 100.
+func WithBatchSize(n int) BatchProcessorOption {
+	return func(p *BatchProcessor) { p.batchSize = n }
+}
+
+// NewBatchProcessor starts a BatchProcessor that flushes buffered traces to
+// exporter every flush interval. Callers must call Close to stop the
+// background goroutine and flush anything still buffered.
+func NewBatchProcessor(exporter Exporter, opts ...BatchProcessorOption) *BatchProcessor {
+	p := &BatchProcessor{
+		exporter:      exporter,
+		laneCapacity:  1000,
+		flushInterval: time.Second,
+		batchSize:     100,
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// Enqueue buffers trace for background export, shedding a low-priority
+// trace first if the relevant lane is already full.
+func (p *BatchProcessor) Enqueue(ctx context.Context, trace *Trace) {
+	atomic.AddInt64(&p.stats.Enqueued, 1)
+
+	high := tracePriority(trace)
+	apiKey, hasKey := APIKeyFromContext(ctx)
+	entry := enqueuedTrace{trace: trace, apiKey: apiKey, hasKey: hasKey}
+
+	p.mu.Lock()
+	if high {
+		if len(p.high) >= p.laneCapacity {
+			p.shed(ctx, true)
+		}
+		p.high = append(p.high, entry)
+	} else {
+		if len(p.low) >= p.laneCapacity {
+			p.shed(ctx, false)
+		}
+		p.low = append(p.low, entry)
+	}
+	p.mu.Unlock()
+}
+
+// shed drops the oldest trace to make room for an incoming one, preferring
+// the low-priority lane. It must be called with p.mu held. wantHigh is
+// whether the incoming trace being made room for is itself high priority.
+func (p *BatchProcessor) shed(ctx context.Context, wantHigh bool) {
+	if len(p.low) > 0 {
+		p.low = p.low[1:]
+		atomic.AddInt64(&p.stats.DroppedLow, 1)
+		publish(ctx, Event{Type: EventSpanDropped, Reason: "low priority lane full"})
+		return
+	}
+	if !wantHigh || len(p.high) == 0 {
+		return
+	}
+	p.high = p.high[1:]
+	atomic.AddInt64(&p.stats.DroppedHigh, 1)
+	publish(ctx, Event{Type: EventSpanDropped, Reason: "high priority lane full"})
+}
+
+// Stats returns a snapshot of the processor's counters.
+func (p *BatchProcessor) Stats() BatchProcessorStats {
+	return BatchProcessorStats{
+		Enqueued:     atomic.LoadInt64(&p.stats.Enqueued),
+		Exported:     atomic.LoadInt64(&p.stats.Exported),
+		ExportErrors: atomic.LoadInt64(&p.stats.ExportErrors),
+		DroppedLow:   atomic.LoadInt64(&p.stats.DroppedLow),
+		DroppedHigh:  atomic.LoadInt64(&p.stats.DroppedHigh),
+	}
+}
+
+// Close stops the background flush loop and exports everything still
+// buffered before returning.
+func (p *BatchProcessor) Close() error {
+	close(p.done)
+	p.wg.Wait()
+	p.flush(context.Background())
+	return nil
+}
+
+func (p *BatchProcessor) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.flush(context.Background())
+		}
+	}
+}
+
+// flush exports up to batchSize buffered traces, high priority first. Each
+// trace exports under the API key captured at its own Enqueue call, so
+// batching traces from different tenants together doesn't misattribute one
+// tenant's trace to another's project.
+func (p *BatchProcessor) flush(ctx context.Context) {
+	p.mu.Lock()
+	batch := p.take(p.batchSize)
+	p.mu.Unlock()
+
+	for _, entry := range batch {
+		exportCtx := ctx
+		if entry.hasKey {
+			exportCtx = ContextWithAPIKey(ctx, entry.apiKey)
+		}
+		if err := p.exporter.Export(exportCtx, entry.trace); err != nil {
+			atomic.AddInt64(&p.stats.ExportErrors, 1)
+			debugLog("batch processor: export failed: %v", err)
+			continue
+		}
+		atomic.AddInt64(&p.stats.Exported, 1)
+	}
+}
+
+// take removes and returns up to n traces from the high lane, then the low
+// lane. It must be called with p.mu held.
+func (p *BatchProcessor) take(n int) []enqueuedTrace {
+	var batch []enqueuedTrace
+	for len(batch) < n && len(p.high) > 0 {
+		batch = append(batch, p.high[0])
+		p.high = p.high[1:]
+	}
+	for len(batch) < n && len(p.low) > 0 {
+		batch = append(batch, p.low[0])
+		p.low = p.low[1:]
+	}
+	return batch
+}
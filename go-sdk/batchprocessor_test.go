@@ -0,0 +1,137 @@
+package langwatch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingExporter struct {
+	mu     sync.Mutex
+	traces []*Trace
+}
+
+func (e *recordingExporter) Export(ctx context.Context, trace *Trace) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.traces = append(e.traces, trace)
+	return nil
+}
+
+func (e *recordingExporter) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.traces)
+}
+
+func newTestTrace(t *testing.T, withErr bool) *Trace {
+	t.Helper()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op")
+	if withErr {
+		span.RecordError(ErrorCapture{Message: "boom"})
+	}
+	span.End()
+	return trace
+}
+
+func TestBatchProcessorFlushesOnInterval(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, WithFlushInterval(10*time.Millisecond))
+	defer p.Close()
+
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+
+	deadline := time.Now().Add(time.Second)
+	for exporter.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if exporter.count() != 1 {
+		t.Fatalf("expected 1 exported trace, got %d", exporter.count())
+	}
+	if p.Stats().Exported != 1 {
+		t.Fatalf("Stats().Exported = %d, want 1", p.Stats().Exported)
+	}
+}
+
+func newGuardrailFailedTrace(t *testing.T) *Trace {
+	t.Helper()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op")
+	RecordGuardrailFailed(span)
+	span.End()
+	return trace
+}
+
+func TestBatchProcessorRetainsGuardrailFailedOverPlainSuccess(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, WithLaneCapacity(1), WithFlushInterval(time.Hour))
+	defer p.Close()
+
+	guardrailTrace := newGuardrailFailedTrace(t)
+	p.Enqueue(context.Background(), guardrailTrace)
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+	// Second low-priority trace should evict the first, not the guardrail-failed one.
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+
+	stats := p.Stats()
+	if stats.DroppedLow != 1 {
+		t.Fatalf("DroppedLow = %d, want 1", stats.DroppedLow)
+	}
+	if stats.DroppedHigh != 0 {
+		t.Fatalf("DroppedHigh = %d, want 0", stats.DroppedHigh)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.high) != 1 || p.high[0].trace != guardrailTrace {
+		t.Fatal("expected the guardrail-failed trace to survive shedding")
+	}
+}
+
+func TestBatchProcessorShedsLowPriorityFirst(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, WithLaneCapacity(1), WithFlushInterval(time.Hour))
+	defer p.Close()
+
+	highTrace := newTestTrace(t, true)
+	p.Enqueue(context.Background(), highTrace)
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+	// Second low-priority trace should evict the first, not the high one.
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+
+	stats := p.Stats()
+	if stats.DroppedLow != 1 {
+		t.Fatalf("DroppedLow = %d, want 1", stats.DroppedLow)
+	}
+	if stats.DroppedHigh != 0 {
+		t.Fatalf("DroppedHigh = %d, want 0", stats.DroppedHigh)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.high) != 1 || p.high[0].trace != highTrace {
+		t.Fatal("expected the high priority trace to survive shedding")
+	}
+}
+
+func TestBatchProcessorPublishesDropEvent(t *testing.T) {
+	var reasons []string
+	Subscribe(func(ctx context.Context, event Event) {
+		if event.Type == EventSpanDropped {
+			reasons = append(reasons, event.Reason)
+		}
+	})
+
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, WithLaneCapacity(1), WithFlushInterval(time.Hour))
+	defer p.Close()
+
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+	p.Enqueue(context.Background(), newTestTrace(t, false))
+
+	if len(reasons) != 1 {
+		t.Fatalf("expected 1 drop event, got %d", len(reasons))
+	}
+}
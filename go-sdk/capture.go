@@ -0,0 +1,66 @@
+package langwatch
+
+import (
+	"context"
+	"os"
+)
+
+// BaggageCaptureKey is the baggage/context key used to carry the capture
+// kill-switch flag across service boundaries.
+const BaggageCaptureKey = "langwatch.capture"
+
+// EnvCaptureDisabled, when set to "off" (or "false"), disables content
+// capture process-wide, regardless of any other configuration. It is meant
+// as an incident-response lever that can be flipped without a redeploy.
+const EnvCaptureDisabled = "LANGWATCH_CAPTURE"
+
+// WithBaggage attaches an arbitrary key/value pair to ctx's LangWatch
+// baggage. Baggage travels with the context the same way thread/user IDs do,
+// and unlike them is intended to be forwarded across process boundaries by
+// carriers such as InjectJob/ExtractJob.
+func WithBaggage(ctx context.Context, key, value string) context.Context {
+	baggage := baggageFromContext(ctx)
+	next := make(map[string]string, len(baggage)+1)
+	for k, v := range baggage {
+		next[k] = v
+	}
+	next[key] = value
+	return context.WithValue(ctx, baggageContextKey, next)
+}
+
+// Baggage returns the value for key in ctx's LangWatch baggage, if any.
+func Baggage(ctx context.Context, key string) (string, bool) {
+	v, ok := baggageFromContext(ctx)[key]
+	return v, ok
+}
+
+func baggageFromContext(ctx context.Context) map[string]string {
+	m, _ := ctx.Value(baggageContextKey).(map[string]string)
+	return m
+}
+
+// WithCaptureDisabled marks ctx (and anything derived from it, including
+// across process boundaries via baggage carriers) as having capture turned
+// off. This is the same switch flipped by the LANGWATCH_CAPTURE environment
+// variable, exposed for programmatic incident response.
+func WithCaptureDisabled(ctx context.Context) context.Context {
+	return WithBaggage(ctx, BaggageCaptureKey, "off")
+}
+
+// CaptureEnabled reports whether content capture should occur for ctx. It is
+// the single source of truth consulted by the HTTP middleware, the event
+// hooks and every Record* helper before they touch input/output content -
+// once disabled, nothing downstream can re-enable it for that context.
+func CaptureEnabled(ctx context.Context) bool {
+	switch os.Getenv(EnvCaptureDisabled) {
+	case "off", "false", "0":
+		return false
+	}
+	if activePolicyOrDefault().CaptureDisabled {
+		return false
+	}
+	if v, ok := Baggage(ctx, BaggageCaptureKey); ok && v == "off" {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCaptureEnabledDefault(t *testing.T) {
+	if !CaptureEnabled(context.Background()) {
+		t.Fatal("expected capture to be enabled by default")
+	}
+}
+
+func TestCaptureEnabledEnvOverride(t *testing.T) {
+	t.Setenv(EnvCaptureDisabled, "off")
+	if CaptureEnabled(context.Background()) {
+		t.Fatal("expected LANGWATCH_CAPTURE=off to disable capture")
+	}
+}
+
+func TestCaptureEnabledBaggageOverride(t *testing.T) {
+	ctx := WithCaptureDisabled(context.Background())
+	if CaptureEnabled(ctx) {
+		t.Fatal("expected baggage kill-switch to disable capture")
+	}
+}
+
+func TestCaptureDisabledSkipsRecording(t *testing.T) {
+	ctx := WithCaptureDisabled(context.Background())
+	ctx, span := StartSpan(ctx, "test")
+	RecordInput(ctx, NewTextValue("secret"))
+	span.RecordOutput(NewTextValue("secret"))
+
+	record := span.toRecord()
+	if record.Input != nil || len(record.Outputs) != 0 {
+		t.Fatalf("expected no input/output to be captured, got %+v", record)
+	}
+}
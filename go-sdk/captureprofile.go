@@ -0,0 +1,75 @@
+package langwatch
+
+import "sync"
+
+// CaptureProfileRule declares the capture policy for spans matching Model
+// and/or Operation. Model and Operation are matched independently and both
+// must match if both are set; an empty field matches anything. Rules are
+// tried in order and the first match wins.
+type CaptureProfileRule struct {
+	// Model matches a span's vendor/model, formatted like WithModel's
+	// arguments joined with "/" (e.g. "openai/gpt-4o"). Empty matches any
+	// model, including spans with none set.
+	Model string
+	// Operation matches a span's name exactly. Empty matches any
+	// operation.
+	Operation string
+	// CaptureEnabled is the capture decision for spans this rule matches.
+	CaptureEnabled bool
+}
+
+// CaptureProfile is a declarative capture policy keyed by (model,
+// operation) pairs, so a caller can turn content capture off for a
+// PII-heavy model or on for an internal one without threading
+// WithCaptureDisabled through every call site for that model. It's applied
+// on top of, and can only narrow, the ctx-derived decision from
+// CaptureEnabled: a profile rule can turn capture off for a span that would
+// otherwise capture, but can never re-enable capture the kill switch (the
+// LANGWATCH_CAPTURE env var or WithCaptureDisabled) already turned off.
+type CaptureProfile struct {
+	Rules []CaptureProfileRule
+}
+
+// lookup returns the CaptureEnabled value of the first rule matching
+// vendor, model and operation, and whether any rule matched.
+func (p CaptureProfile) lookup(vendor, model, operation string) (enabled bool, matched bool) {
+	key := capabilityKey(vendor, model)
+	for _, r := range p.Rules {
+		if r.Model != "" && r.Model != key {
+			continue
+		}
+		if r.Operation != "" && r.Operation != operation {
+			continue
+		}
+		return r.CaptureEnabled, true
+	}
+	return false, false
+}
+
+var activeCaptureProfile = struct {
+	mu      sync.RWMutex
+	profile CaptureProfile
+}{}
+
+// SetCaptureProfile installs profile as the process-wide capture policy,
+// consulted by StartSpan for every new span once its type/model options
+// have been applied. Passing an empty CaptureProfile disables it.
+func SetCaptureProfile(profile CaptureProfile) {
+	activeCaptureProfile.mu.Lock()
+	defer activeCaptureProfile.mu.Unlock()
+	activeCaptureProfile.profile = profile
+}
+
+// applyCaptureProfile narrows s.captureEnabled according to the active
+// CaptureProfile, if any rule matches s's vendor, model and name. Must be
+// called after every SpanOption has run, since WithModel is what sets
+// s.vendor/s.model.
+func (s *Span) applyCaptureProfile() {
+	activeCaptureProfile.mu.RLock()
+	profile := activeCaptureProfile.profile
+	activeCaptureProfile.mu.RUnlock()
+
+	if enabled, matched := profile.lookup(s.vendor, s.model, s.name); matched {
+		s.captureEnabled = s.captureEnabled && enabled
+	}
+}
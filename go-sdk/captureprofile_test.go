@@ -0,0 +1,75 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func resetCaptureProfile() {
+	SetCaptureProfile(CaptureProfile{})
+}
+
+func TestCaptureProfileDisablesCaptureForMatchingModel(t *testing.T) {
+	defer resetCaptureProfile()
+	SetCaptureProfile(CaptureProfile{Rules: []CaptureProfileRule{
+		{Model: "internal/support-pii", CaptureEnabled: false},
+	}})
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithModel("internal", "support-pii"))
+	if span.captureEnabled {
+		t.Fatal("expected capture disabled for matching model rule")
+	}
+}
+
+func TestCaptureProfileEnablesCaptureForMatchingModel(t *testing.T) {
+	defer resetCaptureProfile()
+	SetCaptureProfile(CaptureProfile{Rules: []CaptureProfileRule{
+		{Model: "internal/coding", CaptureEnabled: true},
+		{CaptureEnabled: false},
+	}})
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithModel("internal", "coding"))
+	if !span.captureEnabled {
+		t.Fatal("expected capture enabled for the internal coding model rule")
+	}
+
+	_, other := StartSpan(ctx, "chat", WithModel("internal", "support-pii"))
+	if other.captureEnabled {
+		t.Fatal("expected the catch-all rule to disable capture for unmatched models")
+	}
+}
+
+func TestCaptureProfileMatchesByOperation(t *testing.T) {
+	defer resetCaptureProfile()
+	SetCaptureProfile(CaptureProfile{Rules: []CaptureProfileRule{
+		{Operation: "support.answer", CaptureEnabled: false},
+	}})
+
+	ctx, _ := NewTrace(context.Background())
+	_, flagged := StartSpan(ctx, "support.answer")
+	if flagged.captureEnabled {
+		t.Fatal("expected capture disabled for the matching operation")
+	}
+
+	_, unrelated := StartSpan(ctx, "billing.charge")
+	if !unrelated.captureEnabled {
+		t.Fatal("expected capture unaffected for a non-matching operation")
+	}
+}
+
+func TestCaptureProfileCannotOverrideKillSwitch(t *testing.T) {
+	defer resetCaptureProfile()
+	SetCaptureProfile(CaptureProfile{Rules: []CaptureProfileRule{
+		{CaptureEnabled: true},
+	}})
+
+	ctx := WithCaptureDisabled(context.Background())
+	ctx, trace := NewTrace(ctx)
+	_ = trace
+	_, span := StartSpan(ctx, "chat")
+	if span.captureEnabled {
+		t.Fatal("expected the capture kill switch to win over a profile rule")
+	}
+}
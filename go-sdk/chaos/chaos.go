@@ -0,0 +1,183 @@
+// Package chaos injects synthetic faults into outbound LLM provider calls,
+// for exercising a service's retry/fallback logic against realistic
+// failure modes (rate limits, slow responses, truncated completions)
+// without waiting for the real provider to misbehave in staging.
+//
+// Faults are labeled on the current span's metadata so they're
+// distinguishable in LangWatch from a genuine provider failure.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// metadataInjectedFault marks a span whose outbound call was intercepted by
+// a chaos Fault, and which kind, so it can be filtered out of real
+// incident investigations.
+const metadataInjectedFault = "langwatch.chaos.fault"
+
+// FaultKind identifies the kind of failure a Fault simulates.
+type FaultKind string
+
+const (
+	FaultRateLimit  FaultKind = "rate_limit"
+	FaultLatency    FaultKind = "latency"
+	FaultTruncation FaultKind = "truncation"
+)
+
+// Fault describes one failure mode to inject, and how often.
+type Fault struct {
+	Kind        FaultKind
+	Probability float64
+	Latency     time.Duration
+}
+
+// RateLimit injects a synthetic 429 response, short-circuiting the real
+// request, with the given probability (0-1) per call.
+func RateLimit(probability float64) Fault {
+	return Fault{Kind: FaultRateLimit, Probability: probability}
+}
+
+// Latency delays the real request by d, with the given probability (0-1)
+// per call.
+func Latency(d time.Duration, probability float64) Fault {
+	return Fault{Kind: FaultLatency, Probability: probability, Latency: d}
+}
+
+// Truncation lets the real request through but cuts its response body off
+// partway, with the given probability (0-1) per call, simulating a
+// connection drop mid-stream.
+func Truncation(probability float64) Fault {
+	return Fault{Kind: FaultTruncation, Probability: probability}
+}
+
+// RoundTripper wraps an http.RoundTripper, injecting faults into a
+// configurable fraction of requests. Build one with WithFaults.
+type RoundTripper struct {
+	next   http.RoundTripper
+	faults []Fault
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// WithFaults returns a RoundTripper that forwards to http.DefaultTransport,
+// injecting the given faults. Use Wrap to forward to a different
+// transport instead, e.g. an existing http.Client's Transport.
+func WithFaults(faults ...Fault) *RoundTripper {
+	return &RoundTripper{
+		next:   http.DefaultTransport,
+		faults: faults,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Wrap sets the transport RoundTrip forwards non-faulted requests to, and
+// returns rt for chaining, e.g.:
+//
+//	client.Transport = chaos.WithFaults(chaos.RateLimit(0.05)).Wrap(client.Transport)
+func (rt *RoundTripper) Wrap(next http.RoundTripper) *RoundTripper {
+	if next != nil {
+		rt.next = next
+	}
+	return rt
+}
+
+func (rt *RoundTripper) triggers(f Fault) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.rng.Float64() < f.Probability
+}
+
+func (rt *RoundTripper) mark(ctx context.Context, kind FaultKind) {
+	if span, ok := langwatch.SpanFromContext(ctx); ok {
+		span.SetMetadata(metadataInjectedFault, string(kind))
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for _, f := range rt.faults {
+		if f.Kind != FaultLatency || !rt.triggers(f) {
+			continue
+		}
+		rt.mark(ctx, FaultLatency)
+		select {
+		case <-time.After(f.Latency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for _, f := range rt.faults {
+		if f.Kind != FaultRateLimit || !rt.triggers(f) {
+			continue
+		}
+		rt.mark(ctx, FaultRateLimit)
+		return rateLimitedResponse(req), nil
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, f := range rt.faults {
+		if f.Kind != FaultTruncation || !rt.triggers(f) {
+			continue
+		}
+		rt.mark(ctx, FaultTruncation)
+		resp = truncateResponse(resp)
+		break
+	}
+
+	return resp, nil
+}
+
+func rateLimitedResponse(req *http.Request) *http.Response {
+	body := io.NopCloser(strings.NewReader(`{"error":"chaos: injected rate limit"}`))
+	return &http.Response{
+		Status:     "429 Too Many Requests",
+		StatusCode: http.StatusTooManyRequests,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       body,
+		Request:    req,
+	}
+}
+
+// truncateResponse reads at most half of the original body (at least one
+// byte, if there was any body at all) and replaces resp.Body with it, so
+// the caller sees a connection that dropped mid-response.
+func truncateResponse(resp *http.Response) *http.Response {
+	defer resp.Body.Close()
+
+	full, err := io.ReadAll(resp.Body)
+	if err != nil || len(full) == 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(nil))
+		return resp
+	}
+
+	cut := len(full) / 2
+	if cut == 0 {
+		cut = 1
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(full[:cut]))
+	resp.ContentLength = int64(cut)
+	resp.Header.Set("Content-Length", strconv.Itoa(cut))
+	return resp
+}
@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestWithFaultsRateLimitShortCircuitsAndMarksSpan(t *testing.T) {
+	upstreamCalled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalled = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := WithFaults(RateLimit(1))
+	client := &http.Client{Transport: rt.Wrap(http.DefaultTransport)}
+
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	ctx, span := langwatch.StartSpan(ctx, "chat")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if upstreamCalled {
+		t.Fatal("expected upstream not to be called when rate limit fault triggers")
+	}
+
+	span.End()
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	if matches[0].Span.Metadata[metadataInjectedFault] != string(FaultRateLimit) {
+		t.Fatalf("expected span metadata %q = %q, got %q", metadataInjectedFault, FaultRateLimit, matches[0].Span.Metadata[metadataInjectedFault])
+	}
+}
+
+func TestWithFaultsTruncationShortensBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	rt := WithFaults(Truncation(1))
+	client := &http.Client{Transport: rt.Wrap(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if len(body) == 0 || len(body) >= 10 {
+		t.Fatalf("expected truncated body shorter than 10 bytes, got %d", len(body))
+	}
+}
+
+func TestWithFaultsZeroProbabilityNeverTriggers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := WithFaults(RateLimit(0), Latency(time.Hour, 0), Truncation(0))
+	client := &http.Client{Transport: rt.Wrap(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,106 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// timelineEventCitation is the timeline event name RecordCitations uses.
+const timelineEventCitation = "citation"
+
+// Metadata keys set on a citation's timeline event attributes.
+const (
+	metadataCitationURL        = "url"
+	metadataCitationTitle      = "title"
+	metadataCitationStartIndex = "start_index"
+	metadataCitationEndIndex   = "end_index"
+)
+
+// Citation is a single message-part-level annotation on a model's output,
+// mirroring a url_citation entry from the OpenAI Responses API's
+// `annotations` array: a source URL and title, plus the character range in
+// the output text the citation covers.
+type Citation struct {
+	URL        string
+	Title      string
+	StartIndex int
+	EndIndex   int
+}
+
+// RecordCitations records each citation as a timeline event on the span
+// found in ctx, if any, so RAG citation coverage - which parts of an answer
+// cite a source, and which don't - can be evaluated per span instead of
+// re-parsing the raw response for annotations. A no-op when capture is
+// disabled, since citation URLs and titles are response content.
+func RecordCitations(ctx context.Context, citations []Citation) {
+	if publishIfCaptureDisabled(ctx) {
+		return
+	}
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	for _, c := range citations {
+		span.AddTimelineEvent(timelineEventCitation, map[string]string{
+			metadataCitationURL:        c.URL,
+			metadataCitationTitle:      c.Title,
+			metadataCitationStartIndex: strconv.Itoa(c.StartIndex),
+			metadataCitationEndIndex:   strconv.Itoa(c.EndIndex),
+		})
+	}
+}
+
+// responsesAnnotation mirrors one entry of an OpenAI Responses API
+// output_text content part's `annotations` array. Only url_citation entries
+// carry the fields ParseResponseCitations extracts; other annotation types
+// (e.g. file_citation) are skipped.
+type responsesAnnotation struct {
+	Type       string `json:"type"`
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+}
+
+type responsesContentPart struct {
+	Type        string                `json:"type"`
+	Annotations []responsesAnnotation `json:"annotations"`
+}
+
+type responsesOutputItem struct {
+	Type    string                 `json:"type"`
+	Content []responsesContentPart `json:"content"`
+}
+
+type responsesPayload struct {
+	Output []responsesOutputItem `json:"output"`
+}
+
+// ParseResponseCitations extracts url_citation annotations from a raw
+// OpenAI Responses API response body, across every output item's content
+// parts, in the order they appear.
+func ParseResponseCitations(body []byte) ([]Citation, error) {
+	var parsed responsesPayload
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+
+	var citations []Citation
+	for _, item := range parsed.Output {
+		for _, part := range item.Content {
+			for _, a := range part.Annotations {
+				if a.Type != "url_citation" {
+					continue
+				}
+				citations = append(citations, Citation{
+					URL:        a.URL,
+					Title:      a.Title,
+					StartIndex: a.StartIndex,
+					EndIndex:   a.EndIndex,
+				})
+			}
+		}
+	}
+	return citations, nil
+}
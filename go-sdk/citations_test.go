@@ -0,0 +1,84 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordCitationsAddsTimelineEventPerCitation(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "answer", WithType(SpanTypeLLM))
+
+	RecordCitations(ctx, []Citation{
+		{URL: "https://example.com/a", Title: "Source A", StartIndex: 0, EndIndex: 12},
+		{URL: "https://example.com/b", Title: "Source B", StartIndex: 12, EndIndex: 30},
+	})
+	span.End()
+
+	if len(span.timelineEvents) != 2 {
+		t.Fatalf("expected 2 timeline events, got %d", len(span.timelineEvents))
+	}
+	first := span.timelineEvents[0]
+	if first.Name != timelineEventCitation {
+		t.Fatalf("Name = %q, want %q", first.Name, timelineEventCitation)
+	}
+	if first.Attributes[metadataCitationURL] != "https://example.com/a" || first.Attributes[metadataCitationTitle] != "Source A" {
+		t.Fatalf("unexpected attributes: %+v", first.Attributes)
+	}
+	if first.Attributes[metadataCitationStartIndex] != "0" || first.Attributes[metadataCitationEndIndex] != "12" {
+		t.Fatalf("unexpected char range: %+v", first.Attributes)
+	}
+}
+
+func TestRecordCitationsNoOpWhenCaptureDisabled(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "answer", WithType(SpanTypeLLM))
+	ctx = WithCaptureDisabled(ctx)
+
+	RecordCitations(ctx, []Citation{{URL: "https://example.com/a"}})
+	span.End()
+
+	if len(span.timelineEvents) != 0 {
+		t.Fatalf("expected no timeline events with capture disabled, got %d", len(span.timelineEvents))
+	}
+}
+
+func TestParseResponseCitationsExtractsURLCitations(t *testing.T) {
+	body := []byte(`{
+		"output": [
+			{
+				"type": "message",
+				"content": [
+					{
+						"type": "output_text",
+						"text": "Go is great [1].",
+						"annotations": [
+							{"type": "url_citation", "url": "https://go.dev", "title": "The Go Programming Language", "start_index": 0, "end_index": 16},
+							{"type": "file_citation", "file_id": "file-123"}
+						]
+					}
+				]
+			}
+		]
+	}`)
+
+	citations, err := ParseResponseCitations(body)
+	if err != nil {
+		t.Fatalf("ParseResponseCitations: %v", err)
+	}
+	if len(citations) != 1 {
+		t.Fatalf("expected 1 url_citation, got %d: %+v", len(citations), citations)
+	}
+	if citations[0].URL != "https://go.dev" || citations[0].Title != "The Go Programming Language" {
+		t.Fatalf("unexpected citation: %+v", citations[0])
+	}
+	if citations[0].StartIndex != 0 || citations[0].EndIndex != 16 {
+		t.Fatalf("unexpected char range: %+v", citations[0])
+	}
+}
+
+func TestParseResponseCitationsInvalidJSON(t *testing.T) {
+	if _, err := ParseResponseCitations([]byte("not json")); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,51 @@
+// Command sdkverify runs the go-sdk's scenario matrix against a real
+// LangWatch endpoint, so customers can confirm their setup actually
+// delivers traces end-to-end instead of trusting that no local error was
+// returned.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+	"github.com/langwatch/langwatch/go-sdk/sdkverify"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "", "LangWatch endpoint (defaults to LANGWATCH_ENDPOINT)")
+	apiKey := flag.String("api-key", "", "LangWatch API key (defaults to LANGWATCH_API_KEY)")
+	flag.Parse()
+
+	exporter, err := langwatch.NewExporter(langwatch.Config{Endpoint: *endpoint, APIKey: *apiKey}, langwatch.WithStrictValidation())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sdkverify:", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	if err := langwatch.Probe(ctx, exporter); err != nil {
+		fmt.Fprintln(os.Stderr, "sdkverify: probe failed:", err)
+		os.Exit(1)
+	}
+
+	results := sdkverify.RunMatrix(ctx, exporter, nil, sdkverify.DefaultScenarios())
+
+	failed := 0
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%-4s %s\n", status, r.Scenario)
+		if !r.Passed() {
+			fmt.Printf("     %v\n", r.Err)
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,248 @@
+// Package cohere instruments outbound calls to Cohere's chat, embed, and
+// rerank HTTP APIs, so Cohere usage shows up in LangWatch next to other
+// providers. Like the ollama package, it wraps an http.RoundTripper rather
+// than providing its own client, since it doesn't need to hold any
+// provider-specific state (auth, base URL) beyond what the caller's own
+// http.Client already carries.
+//
+// Cohere's endpoints don't share a response shape the way OpenAI's chat and
+// embeddings calls loosely do, so each endpoint gets its own request and
+// response types and its own attribute extraction, rather than a unified
+// struct.
+package cohere
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("cohere")
+}
+
+// Metadata keys set on a rerank call's span.
+const (
+	metadataRerankDocumentCount = "langwatch.cohere.rerank.document_count"
+	metadataRerankTopScore      = "langwatch.cohere.rerank.top_score"
+)
+
+// RoundTripper instruments requests to the Cohere API paths this package
+// understands with a LangWatch span, forwarding every other request to
+// next untouched. Build one with Wrap.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Wrap returns a RoundTripper instrumenting Cohere API calls and forwarding
+// to next. A nil next forwards to http.DefaultTransport.
+func Wrap(next http.RoundTripper) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RoundTripper{next: next}
+}
+
+type endpoint string
+
+const (
+	endpointChat   endpoint = "chat"
+	endpointEmbed  endpoint = "embed"
+	endpointRerank endpoint = "rerank"
+)
+
+func endpointKind(path string) (endpoint, bool) {
+	switch path {
+	case "/v1/chat", "/v2/chat":
+		return endpointChat, true
+	case "/v1/embed", "/v2/embed":
+		return endpointEmbed, true
+	case "/v1/rerank", "/v2/rerank":
+		return endpointRerank, true
+	default:
+		return "", false
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind, ok := endpointKind(req.URL.Path)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	reqBody, err := drain(&req.Body)
+	if err != nil {
+		return rt.next.RoundTrip(req)
+	}
+	model, input := describeRequest(kind, reqBody)
+
+	ctx, span := langwatch.StartSpan(req.Context(), "cohere."+string(kind), langwatch.WithType(spanTypeFor(kind)), langwatch.WithModel("cohere", model))
+	defer span.End()
+	if input != nil {
+		span.RecordInput(*input)
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, err
+	}
+
+	respBody, err := drain(&resp.Body)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, nil
+	}
+	if resp.StatusCode >= 400 {
+		span.RecordError(langwatch.ErrorCapture{Message: string(respBody)})
+		return resp, nil
+	}
+	recordResponse(span, kind, respBody)
+
+	return resp, nil
+}
+
+func spanTypeFor(kind endpoint) langwatch.SpanType {
+	if kind == endpointRerank {
+		return langwatch.SpanTypeRAG
+	}
+	return langwatch.SpanTypeLLM
+}
+
+// drain reads *body fully and replaces it with a fresh reader over the same
+// bytes, so the caller (RoundTrip's caller, or the real transport) still
+// sees a complete, unread body after this package has inspected it.
+func drain(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+type chatRequest struct {
+	Model   string `json:"model"`
+	Message string `json:"message"`
+}
+
+type embedRequest struct {
+	Model string   `json:"model"`
+	Texts []string `json:"texts"`
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+// describeRequest extracts the model name and a TypedValue worth recording
+// as the span's input from body, whose shape depends on kind.
+func describeRequest(kind endpoint, body []byte) (model string, input *langwatch.TypedValue) {
+	switch kind {
+	case endpointChat:
+		var req chatRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewTextValue(req.Message)
+			return req.Model, &value
+		}
+	case endpointEmbed:
+		var req embedRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewJSONValue(req.Texts)
+			return req.Model, &value
+		}
+	case endpointRerank:
+		var req rerankRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewJSONValue(map[string]interface{}{"query": req.Query, "documents": req.Documents})
+			return req.Model, &value
+		}
+	}
+	return "", nil
+}
+
+type chatResponse struct {
+	Text string `json:"text"`
+	Meta struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+type embedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type rerankResponse struct {
+	Results []rerankResult `json:"results"`
+}
+
+// recordResponse extracts attributes from body - Cohere's response for
+// kind's endpoint - and records them on span.
+func recordResponse(span *langwatch.Span, kind endpoint, body []byte) {
+	switch kind {
+	case endpointChat:
+		var resp chatResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return
+		}
+		span.RecordOutput(langwatch.NewTextValue(resp.Text))
+		promptTokens, completionTokens := resp.Meta.BilledUnits.InputTokens, resp.Meta.BilledUnits.OutputTokens
+		span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+
+	case endpointEmbed:
+		var resp embedResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return
+		}
+		dimensions := 0
+		if len(resp.Embeddings) > 0 {
+			dimensions = len(resp.Embeddings[0])
+		}
+		span.RecordOutput(langwatch.NewJSONValue(map[string]interface{}{
+			"count":      len(resp.Embeddings),
+			"dimensions": dimensions,
+		}))
+
+	case endpointRerank:
+		var resp rerankResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return
+		}
+		span.RecordOutput(langwatch.NewJSONValue(resp.Results))
+		span.SetMetadata(metadataRerankDocumentCount, strconv.Itoa(len(resp.Results)))
+		if top := topRelevanceScore(resp.Results); top != nil {
+			span.SetMetadata(metadataRerankTopScore, strconv.FormatFloat(*top, 'f', -1, 64))
+		}
+	}
+}
+
+func topRelevanceScore(results []rerankResult) *float64 {
+	var top *float64
+	for _, r := range results {
+		score := r.RelevanceScore
+		if top == nil || score > *top {
+			top = &score
+		}
+	}
+	return top
+}
@@ -0,0 +1,151 @@
+package cohere
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func recordedSpan(t *testing.T, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(t.TempDir() + "/traces.jsonl")
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected exactly 1 recorded span, got %d", len(matches))
+	}
+	return matches[0].Span
+}
+
+func TestRoundTripRecordsChatUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, `{"text":"hello there","meta":{"billed_units":{"input_tokens":5,"output_tokens":2}}}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/chat", strings.NewReader(`{"model":"command-r","message":"hi"}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "command-r" {
+		t.Fatalf("Model = %q, want %q", record.Model, "command-r")
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "hello there" {
+		t.Fatalf("unexpected outputs: %+v", record.Outputs)
+	}
+	if record.Metrics == nil || record.Metrics.PromptTokens == nil || *record.Metrics.PromptTokens != 5 {
+		t.Fatalf("unexpected metrics: %+v", record.Metrics)
+	}
+}
+
+func TestRoundTripRecordsEmbedDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"embeddings":[[0.1,0.2,0.3],[0.4,0.5,0.6]]}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/embed", strings.NewReader(`{"model":"embed-english-v3.0","texts":["a","b"]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "embed-english-v3.0" {
+		t.Fatalf("Model = %q, want %q", record.Model, "embed-english-v3.0")
+	}
+	if len(record.Outputs) != 1 {
+		t.Fatalf("expected 1 output, got %+v", record.Outputs)
+	}
+}
+
+func TestRoundTripRecordsRerankDocumentCountAndTopScore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"results":[{"index":0,"relevance_score":0.3},{"index":1,"relevance_score":0.92}]}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/rerank", strings.NewReader(`{"model":"rerank-english-v3.0","query":"q","documents":["a","b"]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Metadata[metadataRerankDocumentCount] != "2" {
+		t.Fatalf("expected document count metadata of 2, got %+v", record.Metadata)
+	}
+	if record.Metadata[metadataRerankTopScore] != "0.92" {
+		t.Fatalf("expected top score metadata of 0.92, got %+v", record.Metadata)
+	}
+}
+
+func TestRoundTripPassesThroughUnrelatedPaths(t *testing.T) {
+	var hit bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	resp, err := client.Get(server.URL + "/v1/models")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if !hit {
+		t.Fatal("expected the request to reach the server")
+	}
+}
+
+func TestRoundTripRecordsErrorOn4xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"message":"invalid request"}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/chat", strings.NewReader(`{"model":"command-r","message":"hi"}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Error == nil {
+		t.Fatal("expected an error to be recorded for a 4xx response")
+	}
+}
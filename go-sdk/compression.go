@@ -0,0 +1,86 @@
+package langwatch
+
+import "strconv"
+
+// metadataMergedCount records how many consecutive identical spans a merged
+// span in an exported batch stands in for.
+const metadataMergedCount = "langwatch.merged_count"
+
+// WithSpanCompression makes the exporter collapse runs of consecutive
+// spans that share a name, parent and type into a single span before
+// sending, tagged with langwatch.merged_count. Agent loops that emit
+// hundreds of identical tiny spans (e.g. a token-count check run before
+// every step) otherwise bloat a trace without adding information: the
+// merged span keeps the first span's start time and the last span's finish
+// time, so the trace's total duration is unaffected, and keeps the first
+// span's input/output/metrics as representative of the run.
+//
+// Spans with an error are never merged, so failures stay individually
+// visible. Compression only ever merges spans it finds adjacent within a
+// trace's span list, which - since spans are appended in the order they're
+// started - means back-to-back siblings, not spans separated by unrelated
+// work.
+func WithSpanCompression() ExporterOption {
+	return func(e *httpExporter) { e.compressSpans = true }
+}
+
+// compressSpans collapses runs of length >= 2 of consecutive mergeable
+// spans in records into one merged span each, preserving the relative
+// order of everything else.
+func compressSpans(records []SpanRecord) []SpanRecord {
+	if len(records) < 2 {
+		return records
+	}
+
+	compressed := make([]SpanRecord, 0, len(records))
+	i := 0
+	for i < len(records) {
+		run := 1
+		for i+run < len(records) && mergeable(records[i], records[i+run]) {
+			run++
+		}
+		if run == 1 {
+			compressed = append(compressed, records[i])
+			i++
+			continue
+		}
+		compressed = append(compressed, mergeRun(records[i:i+run]))
+		i += run
+	}
+	return compressed
+}
+
+// mergeable reports whether b can be folded into a run started by a: same
+// name, parent and type, and neither carries an error.
+func mergeable(a, b SpanRecord) bool {
+	if a.Error != nil || b.Error != nil {
+		return false
+	}
+	if a.Name != b.Name || a.Type != b.Type {
+		return false
+	}
+	if (a.ParentID == nil) != (b.ParentID == nil) {
+		return false
+	}
+	if a.ParentID != nil && *a.ParentID != *b.ParentID {
+		return false
+	}
+	return true
+}
+
+// mergeRun folds a run of mergeable spans into one, representative of the
+// first span in the run, spanning from the first span's start to the last
+// span's finish.
+func mergeRun(run []SpanRecord) SpanRecord {
+	merged := run[0]
+	merged.Timestamps.FinishedAt = run[len(run)-1].Timestamps.FinishedAt
+
+	metadata := make(map[string]string, len(merged.Metadata)+1)
+	for k, v := range merged.Metadata {
+		metadata[k] = v
+	}
+	metadata[metadataMergedCount] = strconv.Itoa(len(run))
+	merged.Metadata = metadata
+
+	return merged
+}
@@ -0,0 +1,76 @@
+package langwatch
+
+import "testing"
+
+func TestCompressSpansMergesConsecutiveIdenticalSpans(t *testing.T) {
+	parent := "parent_1"
+	records := []SpanRecord{
+		{ID: "s1", Name: "token_check", Type: SpanTypeSpan, ParentID: &parent, Timestamps: Timestamps{StartedAt: 100, FinishedAt: 101}},
+		{ID: "s2", Name: "token_check", Type: SpanTypeSpan, ParentID: &parent, Timestamps: Timestamps{StartedAt: 101, FinishedAt: 102}},
+		{ID: "s3", Name: "token_check", Type: SpanTypeSpan, ParentID: &parent, Timestamps: Timestamps{StartedAt: 102, FinishedAt: 103}},
+	}
+
+	got := compressSpans(records)
+
+	if len(got) != 1 {
+		t.Fatalf("expected 3 identical spans to merge into 1, got %d: %+v", len(got), got)
+	}
+	if got[0].ID != "s1" {
+		t.Fatalf("expected the merged span to keep the first span's ID, got %q", got[0].ID)
+	}
+	if got[0].Timestamps.StartedAt != 100 || got[0].Timestamps.FinishedAt != 103 {
+		t.Fatalf("expected merged span to span the full run's duration, got %+v", got[0].Timestamps)
+	}
+	if got[0].Metadata[metadataMergedCount] != "3" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataMergedCount, got[0].Metadata[metadataMergedCount], "3")
+	}
+}
+
+func TestCompressSpansLeavesDistinctSpansAlone(t *testing.T) {
+	records := []SpanRecord{
+		{ID: "s1", Name: "step_a", Type: SpanTypeSpan},
+		{ID: "s2", Name: "step_b", Type: SpanTypeSpan},
+	}
+
+	got := compressSpans(records)
+
+	if len(got) != 2 {
+		t.Fatalf("expected distinct spans to remain separate, got %d: %+v", len(got), got)
+	}
+}
+
+func TestCompressSpansNeverMergesErroredSpans(t *testing.T) {
+	records := []SpanRecord{
+		{ID: "s1", Name: "token_check", Type: SpanTypeSpan},
+		{ID: "s2", Name: "token_check", Type: SpanTypeSpan, Error: &ErrorCapture{Message: "boom"}},
+		{ID: "s3", Name: "token_check", Type: SpanTypeSpan},
+	}
+
+	got := compressSpans(records)
+
+	if len(got) != 3 {
+		t.Fatalf("expected a span with an error not to be merged, got %d: %+v", len(got), got)
+	}
+}
+
+func TestCompressSpansDistinguishesDifferentParents(t *testing.T) {
+	parentA, parentB := "a", "b"
+	records := []SpanRecord{
+		{ID: "s1", Name: "token_check", Type: SpanTypeSpan, ParentID: &parentA},
+		{ID: "s2", Name: "token_check", Type: SpanTypeSpan, ParentID: &parentB},
+	}
+
+	got := compressSpans(records)
+
+	if len(got) != 2 {
+		t.Fatalf("expected spans under different parents not to merge, got %d: %+v", len(got), got)
+	}
+}
+
+func TestWithSpanCompressionEnablesTheExporterField(t *testing.T) {
+	var e httpExporter
+	WithSpanCompression()(&e)
+	if !e.compressSpans {
+		t.Fatal("expected WithSpanCompression to enable compressSpans")
+	}
+}
@@ -0,0 +1,88 @@
+// Package concurrency gates in-flight LLM calls per model, so a burst of
+// traffic against one provider/model can't exhaust a shared connection
+// pool or trip its rate limiter, without throttling calls to other models
+// that have headroom.
+package concurrency
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// metadataQueueWait records how long a call spent waiting for a
+// concurrency slot before it ran, as milliseconds.
+const metadataQueueWait = "langwatch.concurrency.queue_wait_ms"
+
+// LimiterOption configures a Limiter built with NewLimiter.
+type LimiterOption func(*Limiter)
+
+// WithConcurrencyLimit caps model at n in-flight calls at a time. Models
+// without a configured limit are never gated.
+func WithConcurrencyLimit(model string, n int) LimiterOption {
+	return func(l *Limiter) { l.limits[model] = n }
+}
+
+// Limiter gates calls to Do with a weighted semaphore per model.
+type Limiter struct {
+	limits map[string]int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewLimiter creates a Limiter configured with WithConcurrencyLimit
+// options.
+func NewLimiter(opts ...LimiterOption) *Limiter {
+	l := &Limiter{
+		limits: map[string]int{},
+		sems:   map[string]chan struct{}{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Do runs fn once a concurrency slot for model is available, blocking
+// until one is (or ctx is done). Models with no configured limit run fn
+// immediately. The time spent waiting for a slot is recorded as
+// langwatch.concurrency.queue_wait_ms on the span found in ctx, if any.
+func (l *Limiter) Do(ctx context.Context, model string, fn func(ctx context.Context) error) error {
+	sem := l.semaphoreFor(model)
+	if sem == nil {
+		return fn(ctx)
+	}
+
+	start := time.Now()
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-sem }()
+
+	if span, ok := langwatch.SpanFromContext(ctx); ok {
+		span.SetMetadata(metadataQueueWait, strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+	}
+	return fn(ctx)
+}
+
+func (l *Limiter) semaphoreFor(model string) chan struct{} {
+	limit, ok := l.limits[model]
+	if !ok || limit <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.sems[model]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		l.sems[model] = sem
+	}
+	return sem
+}
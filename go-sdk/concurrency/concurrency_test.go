@@ -0,0 +1,90 @@
+package concurrency
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestDoLimitsInFlightCallsPerModel(t *testing.T) {
+	limiter := NewLimiter(WithConcurrencyLimit("gpt-4o", 2))
+
+	var inFlight int32
+	var maxInFlight int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Do(context.Background(), "gpt-4o", func(ctx context.Context) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Fatalf("max in-flight = %d, want <= 2", got)
+	}
+}
+
+func TestDoPassesThroughUnconfiguredModels(t *testing.T) {
+	limiter := NewLimiter(WithConcurrencyLimit("gpt-4o", 1))
+
+	called := false
+	err := limiter.Do(context.Background(), "claude-3", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !called {
+		t.Fatal("expected fn to be called for an unconfigured model")
+	}
+}
+
+func TestDoRecordsQueueWaitOnSpan(t *testing.T) {
+	limiter := NewLimiter(WithConcurrencyLimit("gpt-4o", 1))
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	ctx, span := langwatch.StartSpan(ctx, "chat")
+
+	if err := limiter.Do(ctx, "gpt-4o", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	span.End()
+
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	if _, ok := matches[0].Span.Metadata[metadataQueueWait]; !ok {
+		t.Fatal("expected queue wait metadata to be recorded")
+	}
+}
@@ -0,0 +1,65 @@
+package langwatch
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	defaultEndpoint = "https://app.langwatch.ai"
+
+	envAPIKey   = "LANGWATCH_API_KEY"
+	envEndpoint = "LANGWATCH_ENDPOINT"
+)
+
+// Config holds the settings used to construct an Exporter.
+type Config struct {
+	// APIKey authenticates with the LangWatch project. Defaults to the
+	// LANGWATCH_API_KEY environment variable when empty.
+	APIKey string
+	// Endpoint is the base URL of the LangWatch instance to export to.
+	// Defaults to the LANGWATCH_ENDPOINT environment variable, falling back
+	// to the LangWatch SaaS endpoint.
+	Endpoint string
+}
+
+// withDefaults fills unset fields from the environment and built-in defaults.
+func (c Config) withDefaults() Config {
+	if c.APIKey == "" {
+		c.APIKey = os.Getenv(envAPIKey)
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = os.Getenv(envEndpoint)
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint
+	}
+	return c
+}
+
+// Validate checks that c is well-formed enough to export traces with:
+// a non-empty, whitespace-free API key and an http(s) endpoint URL. Without
+// it, NewExporter happily runs with an empty API key and spans simply
+// vanish server-side with no client-visible error.
+func (c Config) Validate() error {
+	if strings.TrimSpace(c.APIKey) == "" {
+		return fmt.Errorf("langwatch: API key is required (set Config.APIKey or %s)", envAPIKey)
+	}
+	if c.APIKey != strings.TrimSpace(c.APIKey) {
+		return fmt.Errorf("langwatch: API key must not contain leading/trailing whitespace")
+	}
+
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return fmt.Errorf("langwatch: invalid endpoint %q: %w", c.Endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("langwatch: endpoint %q must use http or https", c.Endpoint)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("langwatch: endpoint %q is missing a host", c.Endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package langwatch
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"valid", Config{APIKey: "sk-1234", Endpoint: "https://app.langwatch.ai"}, false},
+		{"empty key", Config{Endpoint: "https://app.langwatch.ai"}, true},
+		{"whitespace key", Config{APIKey: " sk-1234 ", Endpoint: "https://app.langwatch.ai"}, true},
+		{"bad scheme", Config{APIKey: "sk-1234", Endpoint: "ftp://app.langwatch.ai"}, true},
+		{"no host", Config{APIKey: "sk-1234", Endpoint: "https://"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewExporterStrictValidation(t *testing.T) {
+	if _, err := NewExporter(Config{}, WithStrictValidation()); err == nil {
+		t.Fatal("expected error for missing API key under strict validation")
+	}
+	if _, err := NewExporter(Config{APIKey: "sk-1234"}, WithStrictValidation()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewExporter(Config{}); err != nil {
+		t.Fatalf("expected non-strict NewExporter to tolerate an empty API key, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const consoleTruncateLen = 200
+
+// ANSI color codes used by ConsoleExporter. Kept minimal and dependency-free
+// rather than pulling in a terminal color library for a handful of codes.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiCyan   = "\x1b[36m"
+	ansiYellow = "\x1b[33m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+)
+
+// ConsoleExporter prints spans to a terminal as they're exported, colored
+// and truncated for readability, so a trace can be inspected live during
+// local development without opening the LangWatch web UI. It's usually
+// composed with the real Exporter via a small wrapper that calls both.
+type ConsoleExporter struct {
+	// Writer is where spans are printed. Defaults to os.Stdout.
+	Writer io.Writer
+	// NoColor disables ANSI color codes, e.g. when output is piped to a
+	// file or a terminal that doesn't support them.
+	NoColor bool
+}
+
+// Export prints every span in trace to Writer, one line per span.
+func (c ConsoleExporter) Export(ctx context.Context, trace *Trace) error {
+	w := c.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	for _, s := range trace.Spans() {
+		fmt.Fprintln(w, c.formatLine(trace, s))
+	}
+	return nil
+}
+
+func (c ConsoleExporter) formatLine(trace *Trace, s *Span) string {
+	r := s.toRecord()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s ", c.color(ansiDim, trace.ID()[:min(8, len(trace.ID()))]))
+	fmt.Fprintf(&b, "%s", c.color(ansiCyan, string(r.Type)))
+	if r.Name != "" {
+		fmt.Fprintf(&b, " %s", r.Name)
+	}
+	if r.Model != "" {
+		fmt.Fprintf(&b, " %s", c.color(ansiYellow, r.Vendor+"/"+r.Model))
+	}
+	if r.Metrics != nil {
+		if r.Metrics.PromptTokens != nil || r.Metrics.CompletionTokens != nil {
+			prompt, completion := 0, 0
+			if r.Metrics.PromptTokens != nil {
+				prompt = *r.Metrics.PromptTokens
+			}
+			if r.Metrics.CompletionTokens != nil {
+				completion = *r.Metrics.CompletionTokens
+			}
+			fmt.Fprintf(&b, " %s", c.color(ansiDim, fmt.Sprintf("(%d+%d tok)", prompt, completion)))
+		}
+	}
+	duration := r.Timestamps.FinishedAt - r.Timestamps.StartedAt
+	fmt.Fprintf(&b, " %s", c.color(ansiDim, fmt.Sprintf("%dms", duration)))
+	if r.Error != nil {
+		fmt.Fprintf(&b, " %s", c.color(ansiRed, "error: "+truncate(r.Error.Message, consoleTruncateLen)))
+	} else {
+		fmt.Fprintf(&b, " %s", c.color(ansiGreen, "ok"))
+	}
+	return b.String()
+}
+
+func (c ConsoleExporter) color(code, s string) string {
+	if c.NoColor {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
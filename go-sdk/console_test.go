@@ -0,0 +1,42 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConsoleExporterPrintsSpanSummary(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM), WithModel("openai", "gpt-4o"))
+	tokens := 42
+	span.RecordMetrics(Metrics{PromptTokens: &tokens})
+	span.End()
+
+	var buf bytes.Buffer
+	exporter := ConsoleExporter{Writer: &buf, NoColor: true}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "chat") || !strings.Contains(out, "openai/gpt-4o") || !strings.Contains(out, "42+0 tok") {
+		t.Fatalf("expected formatted span summary, got %q", out)
+	}
+}
+
+func TestConsoleExporterMarksErrors(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordError(ErrorCapture{Message: "boom"})
+	span.End()
+
+	var buf bytes.Buffer
+	exporter := ConsoleExporter{Writer: &buf, NoColor: true}
+	_ = exporter.Export(ctx, trace)
+
+	if !strings.Contains(buf.String(), "error: boom") {
+		t.Fatalf("expected error to be printed, got %q", buf.String())
+	}
+}
@@ -0,0 +1,99 @@
+package langwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// typedValueContentRef is the TypedValue.Type used for a value the exporter
+// replaced with a ContentStore reference. Its Value is the hex sha256 hash
+// content was stored under.
+const typedValueContentRef = "content_ref"
+
+// ContentStore uploads and retrieves span content (typically a long,
+// frequently-repeated system prompt) by content hash, so WithContentStore
+// can store it once instead of inline in every span that used it.
+// Implementations must be safe for concurrent use and Put must be
+// idempotent - callers don't check existence before calling it.
+type ContentStore interface {
+	Put(ctx context.Context, hash string, content []byte) error
+	Get(ctx context.Context, hash string) ([]byte, error)
+}
+
+// WithContentStore installs store on the exporter: any span's input or
+// output text at least thresholdBytes long is uploaded to store keyed by
+// its sha256 hash, and replaced inline with a content_ref TypedValue
+// holding just the hash - so a long system prompt repeated across
+// thousands of traces is uploaded once instead of duplicated in every
+// span. A store failure is logged and that value is left inline rather
+// than failing the export.
+func WithContentStore(store ContentStore, thresholdBytes int) ExporterOption {
+	return func(e *httpExporter) {
+		e.contentStore = store
+		e.contentStoreThreshold = thresholdBytes
+	}
+}
+
+func dedupContent(ctx context.Context, store ContentStore, threshold int, records []SpanRecord) {
+	if store == nil || threshold <= 0 {
+		return
+	}
+	for i := range records {
+		records[i].Input = dedupTypedValue(ctx, store, threshold, records[i].Input)
+		for j := range records[i].Outputs {
+			if v := dedupTypedValue(ctx, store, threshold, &records[i].Outputs[j]); v != nil {
+				records[i].Outputs[j] = *v
+			}
+		}
+	}
+}
+
+func dedupTypedValue(ctx context.Context, store ContentStore, threshold int, value *TypedValue) *TypedValue {
+	if value == nil || value.Type != "text" {
+		return value
+	}
+	text, ok := value.Value.(string)
+	if !ok || len(text) < threshold {
+		return value
+	}
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+	if err := store.Put(ctx, hash, []byte(text)); err != nil {
+		debugLog("content store: put %s: %v", hash, err)
+		return value
+	}
+	return &TypedValue{Type: typedValueContentRef, Value: hash}
+}
+
+// ResolveContentRefs replaces any content_ref TypedValue in record with the
+// original text fetched from store, so a read client sees the same content
+// a caller recorded rather than a bare hash. A resolution failure leaves
+// the reference in place and is logged, not returned.
+func ResolveContentRefs(ctx context.Context, store ContentStore, record *SpanRecord) {
+	if store == nil {
+		return
+	}
+	record.Input = resolveTypedValue(ctx, store, record.Input)
+	for i := range record.Outputs {
+		if v := resolveTypedValue(ctx, store, &record.Outputs[i]); v != nil {
+			record.Outputs[i] = *v
+		}
+	}
+}
+
+func resolveTypedValue(ctx context.Context, store ContentStore, value *TypedValue) *TypedValue {
+	if value == nil || value.Type != typedValueContentRef {
+		return value
+	}
+	hash, ok := value.Value.(string)
+	if !ok {
+		return value
+	}
+	content, err := store.Get(ctx, hash)
+	if err != nil {
+		debugLog("content store: get %s: %v", hash, err)
+		return value
+	}
+	return &TypedValue{Type: "text", Value: string(content)}
+}
@@ -0,0 +1,144 @@
+package langwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memoryContentStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	puts  int
+}
+
+func newMemoryContentStore() *memoryContentStore {
+	return &memoryContentStore{blobs: map[string][]byte{}}
+}
+
+func (s *memoryContentStore) Put(ctx context.Context, hash string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puts++
+	s.blobs[hash] = content
+	return nil
+}
+
+func (s *memoryContentStore) Get(ctx context.Context, hash string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blobs[hash], nil
+}
+
+func TestExporterDedupsLongContentAboveThreshold(t *testing.T) {
+	longPrompt := strings.Repeat("x", 100)
+	var seenBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seenBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMemoryContentStore()
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL}, WithContentStore(store, 50))
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue(longPrompt))
+	span.End()
+
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if store.puts != 1 {
+		t.Fatalf("expected 1 store.Put call, got %d", store.puts)
+	}
+	if strings.Contains(seenBody, longPrompt) {
+		t.Fatal("expected the long prompt to be replaced with a content_ref, not sent inline")
+	}
+	if !strings.Contains(seenBody, "content_ref") {
+		t.Fatalf("expected the exported body to contain a content_ref, got %s", seenBody)
+	}
+}
+
+func TestExporterLeavesShortContentInline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMemoryContentStore()
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL}, WithContentStore(store, 1000))
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue("short"))
+	span.End()
+
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if store.puts != 0 {
+		t.Fatalf("expected content below the threshold to be left alone, got %d store.Put calls", store.puts)
+	}
+}
+
+func TestResolveContentRefsRestoresOriginalText(t *testing.T) {
+	store := newMemoryContentStore()
+	ctx := context.Background()
+	store.Put(ctx, "abc123", []byte("the original long prompt"))
+
+	record := &SpanRecord{Input: &TypedValue{Type: typedValueContentRef, Value: "abc123"}}
+	ResolveContentRefs(ctx, store, record)
+
+	if record.Input.Type != "text" || record.Input.Value != "the original long prompt" {
+		t.Fatalf("unexpected resolved input: %+v", record.Input)
+	}
+}
+
+func TestLocalStoreQueryResolvesContentRefsTransparently(t *testing.T) {
+	longPrompt := strings.Repeat("y", 100)
+	store := newMemoryContentStore()
+
+	dir := t.TempDir()
+	ls, err := OpenLocalStore(dir+"/traces.jsonl", WithLocalStoreContentResolution(store))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte(longPrompt))
+	sum := hex.EncodeToString(digest[:])
+	store.Put(context.Background(), sum, []byte(longPrompt))
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.mu.Lock()
+	span.input = &TypedValue{Type: typedValueContentRef, Value: sum}
+	span.mu.Unlock()
+	span.End()
+
+	if err := ls.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	records, err := ls.Query(LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(records) != 1 || records[0].Span.Input == nil || records[0].Span.Input.Value != longPrompt {
+		t.Fatalf("expected the content_ref to resolve to the original prompt, got %+v", records)
+	}
+}
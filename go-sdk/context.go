@@ -0,0 +1,115 @@
+package langwatch
+
+import "context"
+
+type contextKey int
+
+const (
+	traceContextKey contextKey = iota
+	spanContextKey
+	threadIDContextKey
+	userIDContextKey
+	customerIDContextKey
+	labelsContextKey
+	baggageContextKey
+	attrsContextKey
+	inFlightLLMSpanContextKey
+	apiKeyContextKey
+)
+
+// NewTrace starts a new trace and returns a context carrying it. Spans
+// started from the returned context (or descendants of it) belong to this
+// trace unless a different trace is attached further down the tree.
+func NewTrace(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{id: newTraceID(), captureEnabled: CaptureEnabled(ctx)}
+	return context.WithValue(ctx, traceContextKey, t), t
+}
+
+// TraceFromContext returns the trace attached to ctx, if any.
+func TraceFromContext(ctx context.Context) (*Trace, bool) {
+	t, ok := ctx.Value(traceContextKey).(*Trace)
+	return t, ok
+}
+
+// SpanFromContext returns the innermost span attached to ctx, if any.
+func SpanFromContext(ctx context.Context) (*Span, bool) {
+	s, ok := ctx.Value(spanContextKey).(*Span)
+	return s, ok
+}
+
+// WithThreadID attaches a conversation thread identifier to ctx. It is
+// picked up by StartSpan and by the exporter when building the collector
+// request.
+func WithThreadID(ctx context.Context, threadID string) context.Context {
+	return context.WithValue(ctx, threadIDContextKey, threadID)
+}
+
+// ThreadID returns the thread identifier attached to ctx, if any.
+func ThreadID(ctx context.Context) string {
+	id, _ := ctx.Value(threadIDContextKey).(string)
+	return id
+}
+
+// WithUserID attaches an end-user identifier to ctx.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserID returns the user identifier attached to ctx, if any.
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDContextKey).(string)
+	return id
+}
+
+// WithCustomerID attaches a customer/tenant identifier to ctx.
+func WithCustomerID(ctx context.Context, customerID string) context.Context {
+	return context.WithValue(ctx, customerIDContextKey, customerID)
+}
+
+// CustomerID returns the customer identifier attached to ctx, if any.
+func CustomerID(ctx context.Context) string {
+	id, _ := ctx.Value(customerIDContextKey).(string)
+	return id
+}
+
+// WithLabels attaches free-form labels to ctx, replacing any previous ones.
+func WithLabels(ctx context.Context, labels ...string) context.Context {
+	return context.WithValue(ctx, labelsContextKey, labels)
+}
+
+// Labels returns the labels attached to ctx, if any.
+func Labels(ctx context.Context) []string {
+	labels, _ := ctx.Value(labelsContextKey).([]string)
+	return labels
+}
+
+// ContextWithAPIKey attaches a LangWatch project API key to ctx, overriding
+// an Exporter's or API client's configured Config.APIKey for calls made
+// with this ctx. It's meant for a single process serving many customers,
+// each with their own LangWatch project: a request-scoped middleware can
+// resolve the right key per tenant and attach it here, instead of running
+// one Exporter/client per tenant or routing every tenant's data into one
+// project. BatchProcessor.Enqueue captures the key at enqueue time so a
+// trace still exports under the right project even if it's flushed on a
+// later tick alongside other tenants' traces.
+func ContextWithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey, apiKey)
+}
+
+// APIKeyFromContext returns the API key attached with ContextWithAPIKey, if
+// any.
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(apiKeyContextKey).(string)
+	return key, ok
+}
+
+// resolveAPIKey returns the API key attached to ctx with ContextWithAPIKey,
+// falling back to configured when ctx carries none. It's the shared
+// precedence rule every API client (the exporter, ReviewClient,
+// TracesClient, PrivacyClient, ...) applies before setting X-Auth-Token.
+func resolveAPIKey(ctx context.Context, configured string) string {
+	if key, ok := APIKeyFromContext(ctx); ok {
+		return key
+	}
+	return configured
+}
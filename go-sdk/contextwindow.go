@@ -0,0 +1,145 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// Metadata keys set by EnsureContextWindow when it trims (or fails to trim)
+// an overflowing prompt.
+const (
+	metadataContextTrimStrategy      = "langwatch.context_trim.strategy"
+	metadataContextTrimTokensRemoved = "langwatch.context_trim.tokens_removed"
+)
+
+// ErrContextWindowExceeded is returned by EnsureContextWindow when messages
+// don't fit within the model's context window and either no TrimStrategy
+// was given, or the strategy's result still doesn't fit.
+var ErrContextWindowExceeded = errors.New("langwatch: prompt exceeds model's context window")
+
+// TrimStrategy shrinks messages by roughly overBy estimated tokens,
+// returning the trimmed messages, the name of the action taken (recorded as
+// metadataContextTrimStrategy, e.g. "drop_oldest"), and how many tokens the
+// action is estimated to have removed.
+type TrimStrategy func(messages []ChatMessage, overBy int) (trimmed []ChatMessage, action string, tokensRemoved int)
+
+// EnsureContextWindow estimates messages' token count with the same
+// chars-per-token heuristic StreamBridge's early-stop uses, and, if it
+// exceeds vendor/model's registered MaxContextTokens, calls strategy to
+// trim them rather than letting the provider reject the call outright. The
+// action taken and estimated tokens removed are recorded as metadata on the
+// span found in ctx.
+//
+// Returns messages unchanged, with no error, when they already fit or
+// vendor/model has no registered capabilities (see
+// RegisterModelCapabilities) to check against. Returns
+// ErrContextWindowExceeded if strategy is nil, or if the trimmed result
+// still doesn't fit.
+func EnsureContextWindow(ctx context.Context, vendor, model string, messages []ChatMessage, strategy TrimStrategy) ([]ChatMessage, error) {
+	caps, ok := LookupModelCapabilities(vendor, model)
+	if !ok || caps.MaxContextTokens <= 0 {
+		return messages, nil
+	}
+
+	estimated := estimateMessageTokens(messages)
+	if estimated <= caps.MaxContextTokens {
+		return messages, nil
+	}
+
+	if strategy == nil {
+		recordContextTrim(ctx, "fail", 0)
+		return messages, ErrContextWindowExceeded
+	}
+
+	trimmed, action, tokensRemoved := strategy(messages, estimated-caps.MaxContextTokens)
+	recordContextTrim(ctx, action, tokensRemoved)
+
+	if estimateMessageTokens(trimmed) > caps.MaxContextTokens {
+		return trimmed, ErrContextWindowExceeded
+	}
+	return trimmed, nil
+}
+
+func recordContextTrim(ctx context.Context, action string, tokensRemoved int) {
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	span.SetMetadata(metadataContextTrimStrategy, action)
+	span.SetMetadata(metadataContextTrimTokensRemoved, strconv.Itoa(tokensRemoved))
+}
+
+func estimateMessageTokens(messages []ChatMessage) int {
+	var total int
+	for _, m := range messages {
+		if m.Content != nil {
+			total += estimateTokens(*m.Content)
+		}
+	}
+	return total
+}
+
+// DropOldestMessages returns a TrimStrategy that removes messages from the
+// front of the conversation - oldest first, skipping system messages
+// wherever they appear - until the estimated token count is back under the limit or
+// there's nothing left to drop.
+func DropOldestMessages() TrimStrategy {
+	return func(messages []ChatMessage, overBy int) ([]ChatMessage, string, int) {
+		trimmed := append([]ChatMessage(nil), messages...)
+		var removed int
+		for removed < overBy {
+			idx := firstDroppableIndex(trimmed)
+			if idx == -1 {
+				break
+			}
+			if trimmed[idx].Content != nil {
+				removed += estimateTokens(*trimmed[idx].Content)
+			}
+			trimmed = append(trimmed[:idx], trimmed[idx+1:]...)
+		}
+		return trimmed, "drop_oldest", removed
+	}
+}
+
+func firstDroppableIndex(messages []ChatMessage) int {
+	for i, m := range messages {
+		if m.Role != RoleSystem {
+			return i
+		}
+	}
+	return -1
+}
+
+// SummarizeMessages returns a TrimStrategy that replaces every message
+// before the last keepRecent with a single system message produced by
+// summarizer - e.g. a call to a cheaper model. This SDK has no built-in
+// summarization model of its own, so summarizer is supplied by the caller.
+func SummarizeMessages(keepRecent int, summarizer func(messages []ChatMessage) string) TrimStrategy {
+	return func(messages []ChatMessage, overBy int) ([]ChatMessage, string, int) {
+		if keepRecent >= len(messages) {
+			return messages, "summarize", 0
+		}
+		cut := len(messages) - keepRecent
+		toSummarize := messages[:cut]
+		kept := messages[cut:]
+
+		before := estimateMessageTokens(toSummarize)
+		summary := summarizer(toSummarize)
+		summaryMessage := ChatMessage{Role: RoleSystem, Content: &summary}
+
+		trimmed := append([]ChatMessage{summaryMessage}, kept...)
+		return trimmed, "summarize", before - estimateTokens(summary)
+	}
+}
+
+// FailStrategy is a TrimStrategy that makes no changes, so
+// EnsureContextWindow always returns ErrContextWindowExceeded on overflow -
+// an explicit "give up" choice alongside DropOldestMessages/
+// SummarizeMessages, for callers that want overflow to stay a hard error
+// but still want it recorded consistently on the span.
+func FailStrategy() TrimStrategy {
+	return func(messages []ChatMessage, overBy int) ([]ChatMessage, string, int) {
+		return messages, "fail", 0
+	}
+}
@@ -0,0 +1,120 @@
+package langwatch
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func longContent(chars int) *string {
+	s := strings.Repeat("a", chars)
+	return &s
+}
+
+func TestEnsureContextWindowPassesThroughWhenUnderLimit(t *testing.T) {
+	RegisterModelCapabilities("openai", "test-small", ModelCapabilities{MaxContextTokens: 1000})
+	ctx, _ := NewTrace(context.Background())
+
+	messages := []ChatMessage{{Role: RoleUser, Content: strPtr("hello")}}
+	trimmed, err := EnsureContextWindow(ctx, "openai", "test-small", messages, nil)
+	if err != nil {
+		t.Fatalf("EnsureContextWindow: %v", err)
+	}
+	if len(trimmed) != 1 {
+		t.Fatalf("expected messages unchanged, got %+v", trimmed)
+	}
+}
+
+func TestEnsureContextWindowFailsWithoutStrategy(t *testing.T) {
+	RegisterModelCapabilities("openai", "test-tiny", ModelCapabilities{MaxContextTokens: 4})
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	messages := []ChatMessage{{Role: RoleUser, Content: longContent(1000)}}
+	_, err := EnsureContextWindow(ctx, "openai", "test-tiny", messages, nil)
+	if err != ErrContextWindowExceeded {
+		t.Fatalf("err = %v, want ErrContextWindowExceeded", err)
+	}
+	span.End()
+
+	if span.metadata[metadataContextTrimStrategy] != "fail" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataContextTrimStrategy, span.metadata[metadataContextTrimStrategy], "fail")
+	}
+}
+
+func TestEnsureContextWindowDropOldestTrimsUntilItFits(t *testing.T) {
+	RegisterModelCapabilities("openai", "test-medium", ModelCapabilities{MaxContextTokens: 10})
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	messages := []ChatMessage{
+		{Role: RoleSystem, Content: strPtr("you are a helpful assistant")},
+		{Role: RoleUser, Content: longContent(40)},
+		{Role: RoleAssistant, Content: longContent(40)},
+		{Role: RoleUser, Content: strPtr("hi")},
+	}
+	trimmed, err := EnsureContextWindow(ctx, "openai", "test-medium", messages, DropOldestMessages())
+	if err != nil {
+		t.Fatalf("EnsureContextWindow: %v", err)
+	}
+	span.End()
+
+	if trimmed[0].Role != RoleSystem {
+		t.Fatalf("expected the leading system message to survive, got %+v", trimmed[0])
+	}
+	for _, m := range trimmed {
+		if m.Content != nil && *m.Content == *messages[1].Content {
+			t.Fatal("expected the oldest non-system message to have been dropped")
+		}
+	}
+	if span.metadata[metadataContextTrimStrategy] != "drop_oldest" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataContextTrimStrategy, span.metadata[metadataContextTrimStrategy], "drop_oldest")
+	}
+	if span.metadata[metadataContextTrimTokensRemoved] == "0" {
+		t.Fatal("expected a nonzero tokens-removed estimate")
+	}
+}
+
+func TestEnsureContextWindowSummarizeReplacesOlderMessages(t *testing.T) {
+	RegisterModelCapabilities("openai", "test-summarize", ModelCapabilities{MaxContextTokens: 10})
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	messages := []ChatMessage{
+		{Role: RoleUser, Content: longContent(100)},
+		{Role: RoleAssistant, Content: longContent(100)},
+		{Role: RoleUser, Content: strPtr("hi")},
+	}
+	strategy := SummarizeMessages(1, func(toSummarize []ChatMessage) string {
+		return "summary of earlier turns"
+	})
+
+	trimmed, err := EnsureContextWindow(ctx, "openai", "test-summarize", messages, strategy)
+	if err != nil {
+		t.Fatalf("EnsureContextWindow: %v", err)
+	}
+	span.End()
+
+	if len(trimmed) != 2 {
+		t.Fatalf("expected 1 summary message + 1 kept message, got %d: %+v", len(trimmed), trimmed)
+	}
+	if trimmed[0].Role != RoleSystem || *trimmed[0].Content != "summary of earlier turns" {
+		t.Fatalf("unexpected summary message: %+v", trimmed[0])
+	}
+	if span.metadata[metadataContextTrimStrategy] != "summarize" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataContextTrimStrategy, span.metadata[metadataContextTrimStrategy], "summarize")
+	}
+}
+
+func TestEnsureContextWindowUnregisteredModelPassesThrough(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	messages := []ChatMessage{{Role: RoleUser, Content: longContent(1_000_000)}}
+
+	trimmed, err := EnsureContextWindow(ctx, "openai", "some-unregistered-model", messages, nil)
+	if err != nil {
+		t.Fatalf("expected no error for an unregistered model, got %v", err)
+	}
+	if len(trimmed) != 1 {
+		t.Fatal("expected messages unchanged")
+	}
+}
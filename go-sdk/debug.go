@@ -0,0 +1,25 @@
+package langwatch
+
+import (
+	"log"
+	"os"
+)
+
+// EnvDebug, when set to "true" or "1", turns on the SDK's internal debug
+// logging (export failures, dropped spans, ...).
+const EnvDebug = "LANGWATCH_DEBUG"
+
+func debugEnabled() bool {
+	switch os.Getenv(EnvDebug) {
+	case "true", "1":
+		return true
+	}
+	return false
+}
+
+func debugLog(format string, args ...interface{}) {
+	if !debugEnabled() {
+		return
+	}
+	log.Printf("[langwatch] "+format, args...)
+}
@@ -0,0 +1,48 @@
+package langwatch
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+)
+
+// EnvDisabled, when set to "true" or "1", disables the SDK at startup - see
+// Disable.
+const EnvDisabled = "LANGWATCH_DISABLED"
+
+var disabled atomic.Bool
+
+func init() {
+	switch os.Getenv(EnvDisabled) {
+	case "true", "1":
+		disabled.Store(true)
+	}
+}
+
+// Disable turns the SDK into a guaranteed no-op for the rest of the
+// process: StartSpan returns a shared span whose Record* calls and End are
+// immediate no-ops (no allocation, no locking), and NewExporter returns an
+// Exporter that drops everything without touching the network. Intended for
+// builds shipped with instrumentation compiled in but inert, e.g. on-prem
+// customers without a LangWatch project.
+func Disable() {
+	disabled.Store(true)
+}
+
+// Disabled reports whether the SDK is running in no-op mode, via Disable or
+// the LANGWATCH_DISABLED environment variable.
+func Disabled() bool {
+	return disabled.Load()
+}
+
+// noopSpan is returned by StartSpan when the SDK is disabled. It's a single
+// shared instance rather than one per call: every Record*/End method checks
+// noop first and returns before touching any field, so no allocation or
+// locking ever happens on this path regardless of how many goroutines share
+// it.
+var noopSpan = &Span{noop: true}
+
+// noopExporter is returned by NewExporter when the SDK is disabled.
+type noopExporter struct{}
+
+func (noopExporter) Export(ctx context.Context, trace *Trace) error { return nil }
@@ -0,0 +1,45 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDisableMakesStartSpanReturnSharedNoopSpan(t *testing.T) {
+	Disable()
+	defer disabled.Store(false)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span1 := StartSpan(ctx, "one")
+	_, span2 := StartSpan(ctx, "two")
+
+	if span1 != noopSpan || span2 != noopSpan {
+		t.Fatal("expected StartSpan to return the shared noop span when disabled")
+	}
+
+	span1.RecordInput(NewTextValue("ignored"))
+	span1.RecordError(ErrorCapture{Message: "ignored"})
+	span1.End()
+	if span1.input != nil || span1.err != nil {
+		t.Fatal("expected noop span's Record*/End calls to be true no-ops")
+	}
+}
+
+func TestDisableMakesNewExporterReturnNoop(t *testing.T) {
+	Disable()
+	defer disabled.Store(false)
+
+	exporter, err := NewExporter(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := exporter.(noopExporter); !ok {
+		t.Fatalf("expected a noopExporter, got %T", exporter)
+	}
+}
+
+func TestDisabledDefaultsToFalse(t *testing.T) {
+	if Disabled() {
+		t.Fatal("expected the SDK to be enabled by default in this test")
+	}
+}
@@ -0,0 +1,15 @@
+// Package langwatch is the official Go SDK for LangWatch (https://langwatch.ai).
+//
+// It provides a lightweight tracer for capturing LLM calls, chains, tools and
+// RAG pipelines and shipping them to a LangWatch project as traces made up of
+// spans, mirroring the wire format accepted by the /api/collector endpoint.
+//
+// A minimal example:
+//
+//	exporter, err := langwatch.NewExporter(langwatch.Config{APIKey: os.Getenv("LANGWATCH_API_KEY")})
+//	ctx, trace := langwatch.NewTrace(context.Background())
+//	ctx, span := langwatch.StartSpan(ctx, "chat", langwatch.WithType(langwatch.SpanTypeLLM))
+//	span.RecordOutput(langwatch.NewTextValue("hello!"))
+//	span.End()
+//	exporter.Export(ctx, trace)
+package langwatch
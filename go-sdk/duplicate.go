@@ -0,0 +1,35 @@
+package langwatch
+
+// DuplicatePolicy controls how StartSpan reacts when it detects an LLM span
+// is already in flight on the same context - the symptom of accidentally
+// stacking the LangWatch middleware with another instrumentation library
+// (or registering it twice).
+type DuplicatePolicy int
+
+const (
+	// DuplicateMark records langwatch.duplicate=true on the new span but
+	// still creates and exports it. This is the default.
+	DuplicateMark DuplicatePolicy = iota
+	// DuplicateSuppress returns the already in-flight span instead of
+	// creating a new one, so only one LLM span is ever recorded for the
+	// request.
+	DuplicateSuppress
+	// DuplicateAllow disables detection entirely.
+	DuplicateAllow
+)
+
+// Metadata key set on a span StartSpan detected as a duplicate under
+// DuplicateMark.
+const metadataDuplicate = "langwatch.duplicate"
+
+// duplicatePolicy is a package-level setting rather than a per-call option
+// because double-instrumentation is a setup mistake, not something callers
+// choose per span.
+var duplicatePolicy = DuplicateMark
+
+// SetDuplicatePolicy changes how StartSpan handles a detected duplicate LLM
+// span for the remainder of the process. Intended to be called once at
+// startup.
+func SetDuplicatePolicy(p DuplicatePolicy) {
+	duplicatePolicy = p
+}
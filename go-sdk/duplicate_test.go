@@ -0,0 +1,59 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStartSpanMarksDuplicateLLMSpanByDefault(t *testing.T) {
+	SetDuplicatePolicy(DuplicateMark)
+	ctx, _ := NewTrace(context.Background())
+
+	ctx, first := StartSpan(ctx, "chat-1", WithType(SpanTypeLLM))
+	_, second := StartSpan(ctx, "chat-2", WithType(SpanTypeLLM))
+
+	if _, ok := first.metadata[metadataDuplicate]; ok {
+		t.Fatal("expected the first LLM span not to be marked a duplicate")
+	}
+	if second.metadata[metadataDuplicate] != "true" {
+		t.Fatalf("expected the second in-flight LLM span to be marked a duplicate, got %v", second.metadata)
+	}
+}
+
+func TestStartSpanSuppressesDuplicateLLMSpan(t *testing.T) {
+	SetDuplicatePolicy(DuplicateSuppress)
+	defer SetDuplicatePolicy(DuplicateMark)
+	ctx, _ := NewTrace(context.Background())
+
+	ctx, first := StartSpan(ctx, "chat-1", WithType(SpanTypeLLM))
+	_, second := StartSpan(ctx, "chat-2", WithType(SpanTypeLLM))
+
+	if second != first {
+		t.Fatal("expected the duplicate LLM span to be suppressed in favor of the in-flight one")
+	}
+}
+
+func TestStartSpanAllowsDuplicatesWhenPolicyDisabled(t *testing.T) {
+	SetDuplicatePolicy(DuplicateAllow)
+	defer SetDuplicatePolicy(DuplicateMark)
+	ctx, _ := NewTrace(context.Background())
+
+	ctx, _ = StartSpan(ctx, "chat-1", WithType(SpanTypeLLM))
+	_, second := StartSpan(ctx, "chat-2", WithType(SpanTypeLLM))
+
+	if _, ok := second.metadata[metadataDuplicate]; ok {
+		t.Fatal("expected no duplicate marking when DuplicateAllow is set")
+	}
+}
+
+func TestStartSpanDoesNotFlagUnrelatedNonLLMSpans(t *testing.T) {
+	SetDuplicatePolicy(DuplicateMark)
+	ctx, _ := NewTrace(context.Background())
+
+	ctx, _ = StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	_, tool := StartSpan(ctx, "tool-call", WithType(SpanTypeTool))
+
+	if _, ok := tool.metadata[metadataDuplicate]; ok {
+		t.Fatal("expected a non-LLM span not to be flagged as a duplicate")
+	}
+}
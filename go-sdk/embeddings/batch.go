@@ -0,0 +1,133 @@
+// Package embeddings provides an instrumented helper for batching embedding
+// requests, so callers don't reimplement chunking/retry/span bookkeeping in
+// every ad-hoc loop that embeds documents.
+package embeddings
+
+import (
+	"context"
+	"fmt"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("embeddings")
+}
+
+// Client embeds a batch of texts. Implementations wrap a specific provider
+// (OpenAI, Cohere, ...); BatchEmbed only depends on this interface so it
+// works with any of them.
+type Client interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error)
+}
+
+// BatchOptions configures BatchEmbed.
+type BatchOptions struct {
+	// MaxPerRequest caps how many texts are sent to Client.Embed at once.
+	// Defaults to 100.
+	MaxPerRequest int
+	// Concurrency caps how many chunk requests are in flight at once.
+	// Defaults to 1 (sequential).
+	Concurrency int
+	// MaxRetries is how many times a failed chunk is retried before
+	// BatchEmbed gives up on it. Defaults to 2; a negative value disables
+	// retries.
+	MaxRetries int
+}
+
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.MaxPerRequest <= 0 {
+		o.MaxPerRequest = 100
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 2
+	} else if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	return o
+}
+
+// BatchEmbed splits texts into chunks of at most opts.MaxPerRequest, embeds
+// each chunk through client (retrying failed chunks up to opts.MaxRetries
+// times, running up to opts.Concurrency chunks at once), and records one
+// parent "embeddings.batch" span with a child span per chunk plus aggregate
+// token counts - instead of hundreds of uninstrumented ad-hoc loops.
+//
+// The returned slice preserves the order of texts.
+func BatchEmbed(ctx context.Context, client Client, texts []string, opts BatchOptions) ([][]float32, error) {
+	opts = opts.withDefaults()
+
+	ctx, parent := langwatch.StartSpan(ctx, "embeddings.batch", langwatch.WithType(langwatch.SpanTypeChain))
+	defer parent.End()
+	parent.RecordInput(langwatch.NewJSONValue(map[string]interface{}{"count": len(texts)}))
+
+	chunks := chunk(texts, opts.MaxPerRequest)
+	results := make([][][]float32, len(chunks))
+	usages := make([]langwatch.Metrics, len(chunks))
+	errs := make([]error, len(chunks))
+
+	sem := make(chan struct{}, opts.Concurrency)
+	done := make(chan int, len(chunks))
+	for i, c := range chunks {
+		sem <- struct{}{}
+		go func(i int, c []string) {
+			defer func() { <-sem; done <- i }()
+			results[i], usages[i], errs[i] = embedChunkWithRetry(ctx, client, c, i, opts.MaxRetries)
+		}(i, c)
+	}
+	for range chunks {
+		<-done
+	}
+
+	out := make([][]float32, 0, len(texts))
+	var promptTokens, completionTokens int
+	for i, chunkResult := range results {
+		if errs[i] != nil {
+			parent.RecordError(langwatch.ErrorCapture{Message: errs[i].Error()})
+			return nil, fmt.Errorf("embeddings: chunk %d failed: %w", i, errs[i])
+		}
+		out = append(out, chunkResult...)
+		if usages[i].PromptTokens != nil {
+			promptTokens += *usages[i].PromptTokens
+		}
+		if usages[i].CompletionTokens != nil {
+			completionTokens += *usages[i].CompletionTokens
+		}
+	}
+	parent.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+
+	return out, nil
+}
+
+func embedChunkWithRetry(ctx context.Context, client Client, texts []string, index int, maxRetries int) ([][]float32, langwatch.Metrics, error) {
+	ctx, span := langwatch.StartSpan(ctx, fmt.Sprintf("embeddings.chunk[%d]", index), langwatch.WithType(langwatch.SpanTypeLLM))
+	defer span.End()
+	span.RecordInput(langwatch.NewJSONValue(map[string]interface{}{"count": len(texts)}))
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		vectors, usage, err := client.Embed(ctx, texts)
+		if err == nil {
+			span.RecordMetrics(usage)
+			return vectors, usage, nil
+		}
+		lastErr = err
+	}
+	span.RecordError(langwatch.ErrorCapture{Message: lastErr.Error()})
+	return nil, langwatch.Metrics{}, lastErr
+}
+
+func chunk(texts []string, size int) [][]string {
+	var chunks [][]string
+	for i := 0; i < len(texts); i += size {
+		end := i + size
+		if end > len(texts) {
+			end = len(texts)
+		}
+		chunks = append(chunks, texts[i:end])
+	}
+	return chunks
+}
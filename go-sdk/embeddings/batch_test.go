@@ -0,0 +1,63 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type fakeClient struct {
+	failuresPerChunk int32
+	calls            int32
+}
+
+func (c *fakeClient) Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error) {
+	atomic.AddInt32(&c.calls, 1)
+	if atomic.AddInt32(&c.failuresPerChunk, -1) >= 0 {
+		return nil, langwatch.Metrics{}, errors.New("transient failure")
+	}
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{1, 2, 3}
+	}
+	tokens := len(texts)
+	return vectors, langwatch.Metrics{PromptTokens: &tokens}, nil
+}
+
+func TestBatchEmbedChunksAndAggregates(t *testing.T) {
+	ctx, _ := langwatch.NewTrace(context.Background())
+	texts := []string{"a", "b", "c", "d", "e"}
+
+	vectors, err := BatchEmbed(ctx, &fakeClient{}, texts, BatchOptions{MaxPerRequest: 2, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("BatchEmbed returned error: %v", err)
+	}
+	if len(vectors) != len(texts) {
+		t.Fatalf("expected %d vectors, got %d", len(texts), len(vectors))
+	}
+}
+
+func TestBatchEmbedRetriesFailedChunks(t *testing.T) {
+	ctx, _ := langwatch.NewTrace(context.Background())
+	client := &fakeClient{failuresPerChunk: 1}
+
+	vectors, err := BatchEmbed(ctx, client, []string{"a", "b"}, BatchOptions{MaxPerRequest: 10, MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+	if len(vectors) != 2 {
+		t.Fatalf("expected 2 vectors, got %d", len(vectors))
+	}
+}
+
+func TestBatchEmbedGivesUpAfterMaxRetries(t *testing.T) {
+	ctx, _ := langwatch.NewTrace(context.Background())
+	client := &fakeClient{failuresPerChunk: 100}
+
+	if _, err := BatchEmbed(ctx, client, []string{"a"}, BatchOptions{MaxRetries: 1}); err == nil {
+		t.Fatal("expected BatchEmbed to return an error after exhausting retries")
+	}
+}
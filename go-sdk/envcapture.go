@@ -0,0 +1,80 @@
+package langwatch
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvCaptureMode controls whether WithEnvCapture records a matched
+// environment variable's raw value or a hash of it.
+type EnvCaptureMode int
+
+const (
+	// EnvCaptureRaw records the matched variable's value unmodified. This
+	// is the default.
+	EnvCaptureRaw EnvCaptureMode = iota
+	// EnvCaptureHashed records a salted hash of the matched variable's
+	// value instead, via the same salt as WithHashedAttributes.
+	EnvCaptureHashed
+)
+
+// WithEnvCapture makes the exporter attach the current process's
+// environment variables matching any of patterns as "env.<NAME>:<value>"
+// labels on every exported trace, so CI evaluation runs are traceable back
+// to the build that produced them (e.g. WithEnvCapture("GIT_SHA",
+// "CI_PIPELINE_ID")). A pattern ending in "*" matches by prefix, e.g.
+// "CI_*". Values are recorded raw unless WithEnvCaptureMode is set to
+// EnvCaptureHashed.
+func WithEnvCapture(patterns ...string) ExporterOption {
+	return func(e *httpExporter) { e.envCapturePatterns = patterns }
+}
+
+// WithEnvCaptureMode sets whether WithEnvCapture records matched values raw
+// or hashed. Defaults to EnvCaptureRaw.
+func WithEnvCaptureMode(mode EnvCaptureMode) ExporterOption {
+	return func(e *httpExporter) { e.envCaptureMode = mode }
+}
+
+// envCaptureLabels returns "env.<NAME>:<value>" labels, sorted by name, for
+// every current environment variable matching one of patterns.
+func envCaptureLabels(ctx context.Context, e *httpExporter) []string {
+	if len(e.envCapturePatterns) == 0 {
+		return nil
+	}
+	var salt string
+	if e.envCaptureMode == EnvCaptureHashed && e.hashSaltProvider != nil {
+		salt = e.hashSaltProvider(ctx)
+	}
+
+	var labels []string
+	for _, entry := range os.Environ() {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !matchesAnyEnvPattern(name, e.envCapturePatterns) {
+			continue
+		}
+		if e.envCaptureMode == EnvCaptureHashed {
+			value = hashAttributeValue(salt, value)
+		}
+		labels = append(labels, "env."+name+":"+value)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func matchesAnyEnvPattern(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if matchesEnvPattern(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesEnvPattern(name, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return name == pattern
+}
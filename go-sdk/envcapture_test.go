@@ -0,0 +1,56 @@
+package langwatch
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestEnvCaptureLabelsMatchesExactAndWildcardPatterns(t *testing.T) {
+	os.Setenv("LANGWATCH_TEST_GIT_SHA", "abc123")
+	os.Setenv("LANGWATCH_TEST_CI_PIPELINE_ID", "42")
+	os.Setenv("LANGWATCH_TEST_UNRELATED", "nope")
+	defer os.Unsetenv("LANGWATCH_TEST_GIT_SHA")
+	defer os.Unsetenv("LANGWATCH_TEST_CI_PIPELINE_ID")
+	defer os.Unsetenv("LANGWATCH_TEST_UNRELATED")
+
+	e := &httpExporter{envCapturePatterns: []string{"LANGWATCH_TEST_GIT_SHA", "LANGWATCH_TEST_CI_*"}}
+	labels := envCaptureLabels(context.Background(), e)
+
+	want := map[string]bool{
+		"env.LANGWATCH_TEST_GIT_SHA:abc123":    true,
+		"env.LANGWATCH_TEST_CI_PIPELINE_ID:42": true,
+	}
+	if len(labels) != len(want) {
+		t.Fatalf("labels = %v, want %d entries matching %v", labels, len(want), want)
+	}
+	for _, l := range labels {
+		if !want[l] {
+			t.Fatalf("unexpected label %q", l)
+		}
+	}
+}
+
+func TestEnvCaptureLabelsHashesValuesWhenConfigured(t *testing.T) {
+	os.Setenv("LANGWATCH_TEST_SECRET_TOKEN", "s3cr3t")
+	defer os.Unsetenv("LANGWATCH_TEST_SECRET_TOKEN")
+
+	e := &httpExporter{
+		envCapturePatterns: []string{"LANGWATCH_TEST_SECRET_TOKEN"},
+		envCaptureMode:     EnvCaptureHashed,
+		hashSaltProvider:   func(ctx context.Context) string { return "pepper" },
+	}
+	labels := envCaptureLabels(context.Background(), e)
+
+	want := "env.LANGWATCH_TEST_SECRET_TOKEN:" + hashAttributeValue("pepper", "s3cr3t")
+	if len(labels) != 1 || labels[0] != want {
+		t.Fatalf("labels = %v, want [%s]", labels, want)
+	}
+}
+
+func TestEnvCaptureLabelsEmptyWithoutPatterns(t *testing.T) {
+	e := &httpExporter{}
+	if labels := envCaptureLabels(context.Background(), e); labels != nil {
+		t.Fatalf("labels = %v, want nil", labels)
+	}
+}
@@ -0,0 +1,32 @@
+package langwatch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// metadataErrorFingerprint is the metadata key RecordError writes to when
+// the recorded ErrorCapture carries a Type or Code, so LangWatch (and
+// alerting built on top of it) can group and count recurring failure modes
+// instead of treating every message string as unique.
+const metadataErrorFingerprint = "langwatch.error.fingerprint"
+
+// errorFingerprint returns a stable, opaque identifier for a family of
+// errors sharing the same normalized type, code and model - e.g. every
+// "rate_limit_exceeded" error from "gpt-4o" fingerprints the same
+// regardless of the exact message text, which routinely embeds
+// request-specific details like a request ID.
+func errorFingerprint(errType, code, model string) string {
+	normalized := strings.Join([]string{
+		normalizeFingerprintPart(errType),
+		normalizeFingerprintPart(code),
+		normalizeFingerprintPart(model),
+	}, "|")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func normalizeFingerprintPart(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
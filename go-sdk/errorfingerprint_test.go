@@ -0,0 +1,50 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordErrorSetsFingerprintWhenTypeOrCodeGiven(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "call", WithType(SpanTypeLLM), WithModel("openai", "gpt-4o"))
+
+	span.RecordError(ErrorCapture{Message: "rate limit exceeded", Type: "rate_limit_error", Code: "rate_limited"})
+
+	span.mu.Lock()
+	fingerprint := span.metadata[metadataErrorFingerprint]
+	span.mu.Unlock()
+	if fingerprint == "" {
+		t.Fatal("expected a fingerprint to be recorded")
+	}
+}
+
+func TestRecordErrorOmitsFingerprintWithoutTypeOrCode(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "call", WithType(SpanTypeLLM), WithModel("openai", "gpt-4o"))
+
+	span.RecordError(ErrorCapture{Message: "something went wrong"})
+
+	span.mu.Lock()
+	_, ok := span.metadata[metadataErrorFingerprint]
+	span.mu.Unlock()
+	if ok {
+		t.Fatal("expected no fingerprint without a Type or Code")
+	}
+}
+
+func TestErrorFingerprintGroupsSameTypeCodeModel(t *testing.T) {
+	a := errorFingerprint("rate_limit_error", "rate_limited", "gpt-4o")
+	b := errorFingerprint("RATE_LIMIT_ERROR", "  rate_limited ", "GPT-4o")
+	if a != b {
+		t.Fatalf("expected normalized inputs to fingerprint the same, got %q and %q", a, b)
+	}
+}
+
+func TestErrorFingerprintDistinguishesDifferentModels(t *testing.T) {
+	a := errorFingerprint("rate_limit_error", "rate_limited", "gpt-4o")
+	b := errorFingerprint("rate_limit_error", "rate_limited", "claude-3")
+	if a == b {
+		t.Fatal("expected different models to fingerprint differently")
+	}
+}
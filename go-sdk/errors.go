@@ -0,0 +1,45 @@
+package langwatch
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Sentinel errors returned by the exporter, wrapped so callers can branch on
+// them with errors.Is even after they've been decorated with fmt.Errorf's
+// %w context.
+var (
+	// ErrUnauthorized is returned when the collector rejects the request
+	// with 401 or 403, meaning the API key is missing or invalid.
+	ErrUnauthorized = errors.New("langwatch: unauthorized")
+	// ErrRateLimited is returned when the collector rejects the request
+	// with 429.
+	ErrRateLimited = errors.New("langwatch: rate limited")
+)
+
+// ExportError reports that a trace export was rejected by the collector,
+// carrying how many spans were dropped as a result so callers can decide
+// whether to retry or just log and move on.
+type ExportError struct {
+	// Status is the HTTP status code returned by the collector.
+	Status int
+	// Dropped is the number of spans that were part of the failed export.
+	Dropped int
+}
+
+func (e *ExportError) Error() string {
+	return "langwatch: export rejected with status " + strconv.Itoa(e.Status)
+}
+
+// Unwrap lets errors.Is(err, ErrUnauthorized) and errors.Is(err,
+// ErrRateLimited) see through an ExportError to the underlying condition.
+func (e *ExportError) Unwrap() error {
+	switch e.Status {
+	case 401, 403:
+		return ErrUnauthorized
+	case 429:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
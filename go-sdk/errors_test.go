@@ -0,0 +1,28 @@
+package langwatch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExportErrorUnwrapsUnauthorized(t *testing.T) {
+	err := error(&ExportError{Status: 401, Dropped: 3})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatal("expected errors.Is(err, ErrUnauthorized) to be true for a 401 ExportError")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Fatal("expected a 401 ExportError not to match ErrRateLimited")
+	}
+
+	var exportErr *ExportError
+	if !errors.As(err, &exportErr) || exportErr.Dropped != 3 {
+		t.Fatalf("expected errors.As to recover the ExportError with Dropped=3, got %+v", exportErr)
+	}
+}
+
+func TestExportErrorUnwrapsRateLimited(t *testing.T) {
+	err := error(&ExportError{Status: 429})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatal("expected errors.Is(err, ErrRateLimited) to be true for a 429 ExportError")
+	}
+}
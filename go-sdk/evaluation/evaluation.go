@@ -0,0 +1,153 @@
+// Package evaluation calls LangWatch's remote evaluators (the same
+// evaluators configurable from the LangWatch UI - RAGAS faithfulness,
+// toxicity, custom prompts, ...) so a Go service can score a response
+// inline in the request path instead of only after the fact. Every call
+// also attaches its result to the current trace as a span, so a low score
+// or a failed evaluator shows up right alongside the generation it judged.
+package evaluation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("evaluation")
+}
+
+// evaluationRequestPath is the LangWatch endpoint for running a named
+// evaluator against a single piece of data.
+const evaluationRequestPath = "/api/evaluations/%s/evaluate"
+
+// defaultBaseURL is the LangWatch SaaS endpoint, matching config.go's
+// default for the exporter and other API clients.
+const defaultBaseURL = "https://app.langwatch.ai"
+
+// Metadata keys set on the span Evaluate attaches to the current trace.
+const (
+	metadataEvaluatorSlug   = "langwatch.evaluation.slug"
+	metadataEvaluatorStatus = "langwatch.evaluation.status"
+	metadataEvaluatorLabel  = "langwatch.evaluation.label"
+)
+
+// EvaluationRequest is the data a remote evaluator scores. Data is
+// evaluator-specific - a faithfulness evaluator expects
+// {"output", "contexts"}, a custom prompt evaluator expects whatever
+// variables its prompt references - so it's left as a free-form map rather
+// than a fixed struct. Settings overrides the evaluator's configured
+// settings for this call only, and may be nil.
+type EvaluationRequest struct {
+	Data     map[string]interface{} `json:"data"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// EvaluationResult is a remote evaluator's typed verdict. Not every
+// evaluator populates every field: a boolean guardrail sets Passed, a
+// scoring evaluator sets Score, both may set Label and Details.
+type EvaluationResult struct {
+	Status  string   `json:"status"`
+	Passed  *bool    `json:"passed,omitempty"`
+	Score   *float64 `json:"score,omitempty"`
+	Label   string   `json:"label,omitempty"`
+	Details string   `json:"details,omitempty"`
+}
+
+// ClientOption configures a Client built with NewClient.
+type ClientOption func(*Client)
+
+// WithBaseURL overrides the LangWatch endpoint. Defaults to the LangWatch
+// SaaS endpoint, matching langwatch.Config's default.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) { c.baseURL = baseURL }
+}
+
+// WithHTTPClient overrides the http.Client used for requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// Client runs LangWatch's remote evaluators via the LangWatch evaluations
+// API. Shaped like openaiusage.Client rather than langwatch.ReviewClient,
+// since langwatch.Config's environment-default resolution is internal to
+// the root package - a caller in this package passes its API key directly,
+// the same way it would to any other vendor client.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient builds a Client authenticated with apiKey against baseURL.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{apiKey: apiKey, baseURL: defaultBaseURL, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Evaluate runs the evaluator identified by slug against req, and attaches
+// the result to the trace found in ctx as a guardrail span named
+// "evaluation.<slug>" - a new trace is started if ctx carries none, mirroring
+// langwatch.StartSpan. A key attached to ctx with langwatch.ContextWithAPIKey
+// overrides c's configured API key, the same way it does for the exporter -
+// letting a multi-tenant caller share one Client across projects. Evaluate
+// returns an error if the collector rejects the request; a low score or a
+// failed evaluator is not itself an error, it is EvaluationResult's job to
+// carry that - though a guardrail that didn't pass does mark the span with
+// langwatch.RecordGuardrailFailed, so BatchProcessor retains its trace under
+// backpressure.
+func (c *Client) Evaluate(ctx context.Context, slug string, req EvaluationRequest) (EvaluationResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("langwatch: marshal evaluation request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+fmt.Sprintf(evaluationRequestPath, slug), bytes.NewReader(body))
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("langwatch: build evaluation request: %w", err)
+	}
+	apiKey := c.apiKey
+	if ctxKey, ok := langwatch.APIKeyFromContext(ctx); ok {
+		apiKey = ctxKey
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Auth-Token", apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("langwatch: evaluation request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return EvaluationResult{}, fmt.Errorf("langwatch: evaluation request rejected, check your API key: %w", langwatch.ErrUnauthorized)
+	}
+	if resp.StatusCode >= 300 {
+		return EvaluationResult{}, &langwatch.ExportError{Status: resp.StatusCode}
+	}
+
+	var result EvaluationResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return EvaluationResult{}, fmt.Errorf("langwatch: decode evaluation response: %w", err)
+	}
+
+	_, span := langwatch.StartSpan(ctx, "evaluation."+slug, langwatch.WithType(langwatch.SpanTypeGuardrail))
+	span.RecordInput(langwatch.NewJSONValue(req.Data))
+	span.SetMetadata(metadataEvaluatorSlug, slug)
+	span.SetMetadata(metadataEvaluatorStatus, result.Status)
+	if result.Label != "" {
+		span.SetMetadata(metadataEvaluatorLabel, result.Label)
+	}
+	if result.Passed != nil && !*result.Passed {
+		langwatch.RecordGuardrailFailed(span)
+	}
+	span.End()
+
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestClientEvaluateParsesResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/evaluations/faithfulness/evaluate" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("X-Auth-Token"); got != "test-key" {
+			t.Fatalf("X-Auth-Token = %q", got)
+		}
+		w.Write([]byte(`{"status": "processed", "passed": true, "score": 0.92, "label": "faithful"}`))
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	result, err := client.Evaluate(ctx, "faithfulness", EvaluationRequest{
+		Data: map[string]interface{}{"output": "the answer", "contexts": []string{"a", "b"}},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if result.Status != "processed" || result.Passed == nil || !*result.Passed || result.Score == nil || *result.Score != 0.92 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	spans := trace.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span attached to the trace, got %d", len(spans))
+	}
+}
+
+func TestClientEvaluateMarksSpanGuardrailFailedWhenNotPassed(t *testing.T) {
+	evalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "processed", "passed": false, "label": "toxic"}`))
+	}))
+	defer evalServer.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := NewClient("test-key", WithBaseURL(evalServer.URL))
+	result, err := client.Evaluate(ctx, "toxicity", EvaluationRequest{
+		Data: map[string]interface{}{"output": "the answer"},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if result.Passed == nil || *result.Passed {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var got langwatch.CollectorRequest
+	collectorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collectorServer.Close()
+
+	exporter, err := langwatch.NewExporter(langwatch.Config{APIKey: "key", Endpoint: collectorServer.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if len(got.Spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(got.Spans))
+	}
+	if got.Spans[0].Metadata["langwatch.guardrail.failed"] != "true" {
+		t.Fatalf("expected the failed guardrail span to be flagged, got metadata %+v", got.Spans[0].Metadata)
+	}
+}
+
+func TestClientEvaluateReturnsExportErrorOnRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.Evaluate(context.Background(), "faithfulness", EvaluationRequest{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	exportErr, ok := err.(*langwatch.ExportError)
+	if !ok || exportErr.Status != http.StatusInternalServerError {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClientEvaluateUsesAPIKeyFromContext(t *testing.T) {
+	var gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("X-Auth-Token")
+		w.Write([]byte(`{"status": "processed"}`))
+	}))
+	defer server.Close()
+
+	ctx := langwatch.ContextWithAPIKey(context.Background(), "tenant-key")
+	client := NewClient("configured-key", WithBaseURL(server.URL))
+	if _, err := client.Evaluate(ctx, "faithfulness", EvaluationRequest{}); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if gotToken != "tenant-key" {
+		t.Fatalf("X-Auth-Token = %q, want %q", gotToken, "tenant-key")
+	}
+}
+
+func TestClientEvaluateReturnsUnauthorizedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient("bad-key", WithBaseURL(server.URL))
+	_, err := client.Evaluate(context.Background(), "faithfulness", EvaluationRequest{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
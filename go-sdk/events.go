@@ -0,0 +1,62 @@
+package langwatch
+
+import "context"
+
+// EventType identifies the kind of lifecycle event published by the SDK.
+type EventType string
+
+const (
+	// EventCaptureSkipped is published whenever a Record* call or the HTTP
+	// middleware skips capturing content because CaptureEnabled(ctx) was
+	// false.
+	EventCaptureSkipped EventType = "capture_skipped"
+	// EventSpanStarted is published whenever a span is started.
+	EventSpanStarted EventType = "span_started"
+	// EventSpanEnded is published whenever a span is ended.
+	EventSpanEnded EventType = "span_ended"
+	// EventPolicyReloaded is published whenever a PolicyWatcher successfully
+	// applies a changed policy file.
+	EventPolicyReloaded EventType = "policy_reloaded"
+	// EventModelFingerprintChanged is published by RecordSystemFingerprint
+	// whenever a model's system_fingerprint differs from the last one seen
+	// for that model in this process.
+	EventModelFingerprintChanged EventType = "model_fingerprint_changed"
+)
+
+// Event is passed to subscribers registered with Subscribe.
+type Event struct {
+	Type   EventType
+	Reason string
+}
+
+// EventHandler receives events published by the SDK.
+type EventHandler func(ctx context.Context, event Event)
+
+var (
+	eventHandlers []EventHandler
+)
+
+// Subscribe registers a handler invoked for every SDK event. It is intended
+// for lightweight observability hooks (metrics, audit logs); handlers run
+// synchronously on the goroutine that triggered the event and should not
+// block.
+func Subscribe(handler EventHandler) {
+	eventHandlers = append(eventHandlers, handler)
+}
+
+func publish(ctx context.Context, event Event) {
+	for _, h := range eventHandlers {
+		h(ctx, event)
+	}
+}
+
+// publishIfCaptureDisabled is a small helper shared by the middleware and
+// Record* call sites that want to surface the kill-switch as an event
+// instead of silently dropping content.
+func publishIfCaptureDisabled(ctx context.Context) bool {
+	if CaptureEnabled(ctx) {
+		return false
+	}
+	publish(ctx, Event{Type: EventCaptureSkipped, Reason: "capture disabled"})
+	return true
+}
@@ -0,0 +1,28 @@
+package langwatch
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRecordExpectedOutputSetsMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "eval")
+	span.RecordExpectedOutput(NewTextValue("42"))
+
+	if !strings.Contains(span.metadata[metadataExpectedOutput], "42") {
+		t.Fatalf("expected metadata to contain the expected output, got %v", span.metadata)
+	}
+}
+
+func TestRecordExpectedOutputSkippedWhenCaptureDisabled(t *testing.T) {
+	ctx := WithCaptureDisabled(context.Background())
+	ctx, _ = NewTrace(ctx)
+	_, span := StartSpan(ctx, "eval")
+	span.RecordExpectedOutput(NewTextValue("42"))
+
+	if _, ok := span.metadata[metadataExpectedOutput]; ok {
+		t.Fatal("expected RecordExpectedOutput to be skipped when capture is disabled")
+	}
+}
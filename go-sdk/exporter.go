@@ -0,0 +1,391 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+)
+
+// Exporter ships completed traces to a LangWatch project.
+type Exporter interface {
+	Export(ctx context.Context, trace *Trace) error
+}
+
+// prober is implemented by Exporters that support a connectivity probe.
+// httpExporter is the only implementation today.
+type prober interface {
+	Probe(ctx context.Context) error
+}
+
+// Probe runs exporter's one-shot connectivity/auth check, if it supports
+// one. Exporters that don't implement probing (e.g. a custom test double)
+// are treated as trivially healthy.
+func Probe(ctx context.Context, exporter Exporter) error {
+	if p, ok := exporter.(prober); ok {
+		return p.Probe(ctx)
+	}
+	return nil
+}
+
+// APIKeyProvider returns the API key to use for the next export call,
+// letting callers fetch it from a secrets manager (e.g. Vault) and rotate
+// it without restarting the process.
+type APIKeyProvider func(ctx context.Context) string
+
+// ExporterOption configures an Exporter built with NewExporter.
+type ExporterOption func(*httpExporter)
+
+// WithAPIKeyProvider overrides the exporter's API key resolution, calling
+// provider before every export instead of using a fixed Config.APIKey.
+func WithAPIKeyProvider(provider APIKeyProvider) ExporterOption {
+	return func(e *httpExporter) { e.apiKeyProvider = provider }
+}
+
+// WithFailoverAPIKeyProvider adds a secondary key resolved by provider,
+// retried once whenever the collector rejects the primary key with 401 or
+// 403. Rotation to the secondary key is logged via the debug facility.
+func WithFailoverAPIKeyProvider(provider APIKeyProvider) ExporterOption {
+	return func(e *httpExporter) { e.failoverAPIKeyProvider = provider }
+}
+
+// WithStrictValidation makes NewExporter validate cfg via Config.Validate
+// and return a descriptive error instead of happily running with, say, an
+// empty API key and having spans vanish server-side with no client-visible
+// error.
+func WithStrictValidation() ExporterOption {
+	return func(e *httpExporter) { e.strictValidation = true }
+}
+
+// WithMaxBatchBytes splits a trace's spans into multiple collector requests
+// so that no single request's serialized span payload exceeds maxBytes,
+// letting large traces succeed (and retry) in parts instead of all-or-nothing.
+// Defaults to 0 (a single request per Export call).
+func WithMaxBatchBytes(maxBytes int) ExporterOption {
+	return func(e *httpExporter) { e.maxBatchBytes = maxBytes }
+}
+
+// WithBatchRetries sets how many times a batch is retried after a retriable
+// collector response (429 or 5xx) before it's reported as failed. Defaults
+// to 0 (no retries).
+func WithBatchRetries(n int) ExporterOption {
+	return func(e *httpExporter) { e.batchRetries = n }
+}
+
+// WithDeniedAttributes strips the named metadata keys from every span
+// before it's sent to the collector, e.g. to redact a key an instrumented
+// library set that shouldn't leave the process.
+func WithDeniedAttributes(keys ...string) ExporterOption {
+	return func(e *httpExporter) { e.deniedAttributes = keys }
+}
+
+// WithAuditLog makes the exporter emit an AuditRecord for every span it
+// exports, recording whether capture was enabled and which metadata keys
+// were redacted or attributes truncated, for compliance purposes. Records
+// go to the sink registered with SetAuditSink, or - if none is registered -
+// are stamped onto the span's own metadata.
+func WithAuditLog() ExporterOption {
+	return func(e *httpExporter) { e.auditEnabled = true }
+}
+
+// RequestSigner mutates req (e.g. adding an HMAC signature header computed
+// over its body) before it's sent. It runs after every other header is set,
+// including WithHeader's, so a signer that covers headers in its signature
+// sees the final request.
+type RequestSigner func(req *http.Request) error
+
+// WithHeader adds a static header sent with every export request, e.g. an
+// API gateway's own auth header in front of the LangWatch collector.
+// Repeated calls accumulate; use http.Header.Set semantics (last value
+// wins) by calling WithHeader once per key if a header should have exactly
+// one value.
+func WithHeader(key, value string) ExporterOption {
+	return func(e *httpExporter) { e.headers = append(e.headers, [2]string{key, value}) }
+}
+
+// WithRequestSigner installs a RequestSigner run against every outgoing
+// export request, e.g. to HMAC-sign the payload for a gateway sitting in
+// front of the LangWatch collector. A signer error aborts that request the
+// same way a network error would - the batch is retried (subject to
+// WithBatchRetries) or reported failed.
+func WithRequestSigner(signer RequestSigner) ExporterOption {
+	return func(e *httpExporter) { e.requestSigner = signer }
+}
+
+// WithLanguageDetection makes the exporter tag every span's input/output
+// text with a best-effort detected language (see DetectLanguage), recorded
+// as langwatch.input.language / langwatch.output.language metadata, so
+// multilingual traces can be sliced by language in LangWatch.
+func WithLanguageDetection() ExporterOption {
+	return func(e *httpExporter) { e.languageDetection = true }
+}
+
+// WithUnixSocket routes every export request over the Unix domain socket at
+// path instead of a TCP connection, for setups where a local collector
+// sidecar listens on a UDS for performance or to avoid exposing a TCP port.
+// Config.Endpoint's scheme and host are still sent as the request's URL
+// (and any TLS/Host-based routing the sidecar does still applies) - only
+// the underlying connection changes.
+func WithUnixSocket(path string) ExporterOption {
+	return func(e *httpExporter) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		}
+		e.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// httpExporter is the default Exporter, posting to the LangWatch REST
+// collector at POST {endpoint}/api/collector.
+type httpExporter struct {
+	config     Config
+	httpClient *http.Client
+
+	apiKeyProvider         APIKeyProvider
+	failoverAPIKeyProvider APIKeyProvider
+	strictValidation       bool
+
+	maxSpanSize           int
+	autoTruncateOversized bool
+
+	maxBatchBytes int
+	batchRetries  int
+
+	hashedAttributes []string
+	hashSaltProvider HashSaltProvider
+
+	deniedAttributes []string
+	auditEnabled     bool
+
+	languageDetection bool
+	compressSpans     bool
+
+	headers       [][2]string
+	requestSigner RequestSigner
+
+	contentStore          ContentStore
+	contentStoreThreshold int
+
+	topicClassifier TopicClassifier
+	traceUpdater    TraceUpdater
+
+	envCapturePatterns []string
+	envCaptureMode     EnvCaptureMode
+}
+
+// NewExporter builds the default HTTP Exporter from cfg, applying
+// environment defaults for any zero-valued fields. Without
+// WithStrictValidation, an empty API key or malformed endpoint will only
+// surface as failed exports at runtime rather than at construction time.
+func NewExporter(cfg Config, opts ...ExporterOption) (Exporter, error) {
+	if Disabled() {
+		return noopExporter{}, nil
+	}
+
+	cfg = cfg.withDefaults()
+	e := &httpExporter{
+		config:      cfg,
+		httpClient:  http.DefaultClient,
+		maxSpanSize: defaultMaxSpanSize,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.strictValidation {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Probe performs a one-shot connectivity and authentication check against
+// the collector, without needing a real trace to send. It distinguishes
+// network/DNS failures from an auth rejection so startup code can surface a
+// helpful error instead of silently dropping every export later.
+func (e *httpExporter) Probe(ctx context.Context) error {
+	status, err := e.post(ctx, []byte(`{"spans":[]}`), e.apiKey(ctx))
+	if err != nil {
+		return fmt.Errorf("langwatch: probe failed to reach %s: %w", e.config.Endpoint, err)
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return fmt.Errorf("langwatch: probe rejected, check your API key: %w", ErrUnauthorized)
+	}
+	return nil
+}
+
+// apiKey resolves the key to authenticate an export with, preferring (in
+// order) an explicit APIKeyProvider, a key attached to ctx with
+// ContextWithAPIKey, and finally the exporter's configured Config.APIKey.
+func (e *httpExporter) apiKey(ctx context.Context) string {
+	if e.apiKeyProvider != nil {
+		return e.apiKeyProvider(ctx)
+	}
+	return resolveAPIKey(ctx, e.config.APIKey)
+}
+
+// sampledIn deterministically decides whether traceID falls within the
+// fraction rate of traces kept for export. Sampling by a hash of the trace
+// ID, rather than a fresh coin flip per call, means the same trace samples
+// the same way on every retry instead of being kept on one attempt and
+// dropped on the next.
+func sampledIn(traceID string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(traceID))
+	bucket := binary.BigEndian.Uint32(sum[:4])
+	return float64(bucket) < rate*float64(math.MaxUint32)
+}
+
+// Export serializes every span recorded on trace and POSTs it to the
+// collector, tagging it with the thread/user/customer IDs and labels found
+// in ctx. If WithMaxBatchBytes is set, spans are split across multiple
+// requests; a batch that fails after WithBatchRetries attempts is recorded
+// in the returned BatchExportError instead of aborting the remaining
+// batches, so a single oversized or rejected batch doesn't drop the rest of
+// an otherwise-healthy trace.
+//
+// Export also enforces the Policy from whatever source was registered with
+// SetActivePolicy, if any: a trace outside SampleRate's kept fraction is
+// dropped before any request is built, and DeniedAttributes is stripped
+// from every span's metadata in addition to any keys configured with
+// WithDeniedAttributes.
+func (e *httpExporter) Export(ctx context.Context, trace *Trace) error {
+	policy := activePolicyOrDefault()
+	if !sampledIn(trace.ID(), policy.SampleRate) {
+		return nil
+	}
+
+	deniedAttributes := e.deniedAttributes
+	if len(policy.DeniedAttributes) > 0 {
+		deniedAttributes = append(append([]string{}, deniedAttributes...), policy.DeniedAttributes...)
+	}
+
+	spans := trace.Spans()
+	records := make([]SpanRecord, len(spans))
+	for i, s := range spans {
+		records[i] = s.toRecord()
+		truncated := checkSpanSize(&records[i], e.maxSpanSize, e.autoTruncateOversized)
+		redacted := redactMetadata(&records[i], deniedAttributes)
+		if e.auditEnabled {
+			emitAudit(ctx, &records[i], trace.ID(), s.captureEnabled, redacted, truncated)
+		}
+		if e.languageDetection {
+			tagLanguages(&records[i])
+		}
+	}
+	dedupContent(ctx, e.contentStore, e.contentStoreThreshold, records)
+	if e.compressSpans {
+		records = compressSpans(records)
+	}
+
+	labels := append(resourceLabels(), Labels(ctx)...)
+	labels = append(labels, trace.Labels()...)
+	labels = append(labels, envCaptureLabels(ctx, e)...)
+	if linked := trace.LinkedFromTraceID(); linked != "" {
+		labels = append(labels, "langwatch.linked_from_trace_id:"+linked)
+	}
+
+	var failures []*SpanExportError
+	for _, batch := range splitIntoBatches(records, e.maxBatchBytes) {
+		if err := e.exportBatch(ctx, trace, labels, batch); err != nil {
+			debugLog("batch of %d span(s) failed: %v", len(batch), err)
+			failures = append(failures, &SpanExportError{SpanIDs: spanIDs(batch), Status: err.Status})
+		}
+	}
+
+	e.classifyTopics(ctx, trace)
+
+	if len(failures) > 0 {
+		return &BatchExportError{Failures: failures}
+	}
+	return nil
+}
+
+// exportBatch posts one batch of records, retrying up to e.batchRetries
+// times when the collector responds with a retriable status (429 or 5xx).
+func (e *httpExporter) exportBatch(ctx context.Context, trace *Trace, labels []string, records []SpanRecord) *ExportError {
+	userID := trace.UserID()
+	if userID == "" {
+		userID = UserID(ctx)
+	}
+	customerID := trace.CustomerID()
+	if customerID == "" {
+		customerID = CustomerID(ctx)
+	}
+
+	req := CollectorRequest{
+		TraceID:    trace.ID(),
+		ThreadID:   ThreadID(ctx),
+		UserID:     userID,
+		CustomerID: customerID,
+		Labels:     labels,
+		Spans:      records,
+		Metadata:   trace.Metadata(),
+		Input:      trace.Input(),
+		Output:     trace.Output(),
+	}
+	e.hashAttributes(ctx, &req)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return &ExportError{Status: 0, Dropped: len(records)}
+	}
+
+	var status int
+	for attempt := 0; attempt <= e.batchRetries; attempt++ {
+		status, err = e.post(ctx, body, e.apiKey(ctx))
+		if err != nil {
+			continue
+		}
+		if (status == http.StatusUnauthorized || status == http.StatusForbidden) && e.failoverAPIKeyProvider != nil {
+			debugLog("primary API key rejected with status %d, rotating to failover key", status)
+			status, err = e.post(ctx, body, e.failoverAPIKeyProvider(ctx))
+		}
+		if err == nil && status < 300 {
+			return nil
+		}
+		if err == nil && !isRetryableStatus(status) {
+			break
+		}
+	}
+	return &ExportError{Status: status, Dropped: len(records)}
+}
+
+// post sends body to the collector authenticated with apiKey, returning the
+// response status code.
+func (e *httpExporter) post(ctx context.Context, body []byte, apiKey string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint+"/api/collector", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("langwatch: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", apiKey)
+	for _, header := range e.headers {
+		req.Header.Add(header[0], header[1])
+	}
+	if e.requestSigner != nil {
+		if err := e.requestSigner(req); err != nil {
+			return 0, fmt.Errorf("langwatch: sign request: %w", err)
+		}
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("langwatch: export trace: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,146 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestExporterFailsOverToSecondaryKey(t *testing.T) {
+	var seenKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Auth-Token")
+		seenKeys = append(seenKeys, key)
+		if key != "good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "bad-key", Endpoint: server.URL},
+		WithFailoverAPIKeyProvider(func(ctx context.Context) string { return "good-key" }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("expected failover export to succeed, got %v", err)
+	}
+
+	if len(seenKeys) != 2 || seenKeys[0] != "bad-key" || seenKeys[1] != "good-key" {
+		t.Fatalf("expected [bad-key good-key], got %v", seenKeys)
+	}
+}
+
+func TestExporterSendsCustomHeaders(t *testing.T) {
+	var seenGateway string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenGateway = r.Header.Get("X-Gateway-Auth")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL},
+		WithHeader("X-Gateway-Auth", "gateway-secret"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if seenGateway != "gateway-secret" {
+		t.Fatalf("X-Gateway-Auth = %q, want %q", seenGateway, "gateway-secret")
+	}
+}
+
+func TestExporterAppliesRequestSigner(t *testing.T) {
+	var seenSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenSignature = r.Header.Get("X-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL},
+		WithRequestSigner(func(req *http.Request) error {
+			req.Header.Set("X-Signature", "deadbeef")
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if seenSignature != "deadbeef" {
+		t.Fatalf("X-Signature = %q, want %q", seenSignature, "deadbeef")
+	}
+}
+
+func TestExporterRequestSignerErrorFailsExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	boom := errors.New("boom")
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL},
+		WithRequestSigner(func(req *http.Request) error { return boom }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err == nil {
+		t.Fatal("expected export to fail when the request signer errors")
+	}
+}
+
+func TestExporterSendsOverUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "collector.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+
+	var hit bool
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: "http://sidecar-collector"},
+		WithUnixSocket(socketPath),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	if !hit {
+		t.Fatal("expected the request to reach the server over the Unix socket")
+	}
+}
@@ -0,0 +1,141 @@
+// Package finetune converts recorded LLM conversations into the
+// chat-format JSONL OpenAI's fine-tuning API expects, closing the loop
+// from observability back to training data.
+//
+// Export works from a slice of langwatch.SpanRecord - e.g. pulled from
+// langwatch.LocalStore.Query, or already in memory - rather than a
+// dedicated read client: this SDK has no client for fetching traces back
+// from LangWatch yet, only write-side ones (TracesClient, PrivacyClient).
+package finetune
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Redactor rewrites a message's content before it's written out, e.g. to
+// scrub PII. A nil Redactor leaves content untouched.
+type Redactor func(content string) string
+
+// Options configures Export.
+type Options struct {
+	// Redact, if set, is applied to every message's content.
+	Redact Redactor
+	// Dedupe drops conversations whose exact message sequence (after
+	// redaction) has already been written earlier in the same Export call.
+	Dedupe bool
+}
+
+// message mirrors the role/content shape OpenAI's chat fine-tuning JSONL
+// schema expects.
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type conversation struct {
+	Messages []message `json:"messages"`
+}
+
+// Export converts every LLM span in records that has chat-message input
+// and a text output into one JSONL line, and returns the concatenated
+// result. Spans that aren't LLM spans, or whose input/output isn't in
+// chat-message form, are skipped.
+func Export(records []langwatch.SpanRecord, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	seen := map[string]bool{}
+
+	for _, record := range records {
+		conv, ok := buildConversation(record, opts.Redact)
+		if !ok {
+			continue
+		}
+
+		line, err := json.Marshal(conv)
+		if err != nil {
+			return nil, fmt.Errorf("finetune: marshal conversation: %w", err)
+		}
+
+		if opts.Dedupe {
+			key := string(line)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+func buildConversation(record langwatch.SpanRecord, redact Redactor) (conversation, bool) {
+	if record.Type != langwatch.SpanTypeLLM || record.Input == nil {
+		return conversation{}, false
+	}
+	inputMessages, ok := record.Input.Value.([]langwatch.ChatMessage)
+	if !ok {
+		return conversation{}, false
+	}
+
+	var conv conversation
+	for _, m := range inputMessages {
+		if m.Content == nil {
+			continue
+		}
+		conv.Messages = append(conv.Messages, message{
+			Role:    string(m.Role),
+			Content: redactContent(*m.Content, redact),
+		})
+	}
+
+	outputText, ok := outputChatText(record.Outputs)
+	if !ok {
+		return conversation{}, false
+	}
+	conv.Messages = append(conv.Messages, message{
+		Role:    string(langwatch.RoleAssistant),
+		Content: redactContent(outputText, redact),
+	})
+
+	if len(conv.Messages) < 2 {
+		return conversation{}, false
+	}
+	return conv, true
+}
+
+// outputChatText finds the first non-empty text in outputs, whether it's a
+// plain text value or a chat message.
+func outputChatText(outputs []langwatch.TypedValue) (string, bool) {
+	for _, out := range outputs {
+		switch v := out.Value.(type) {
+		case string:
+			if v != "" {
+				return v, true
+			}
+		case langwatch.ChatMessage:
+			if v.Content != nil && *v.Content != "" {
+				return *v.Content, true
+			}
+		case []langwatch.ChatMessage:
+			for _, m := range v {
+				if m.Content != nil && *m.Content != "" {
+					return *m.Content, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func redactContent(content string, redact Redactor) string {
+	if redact == nil {
+		return content
+	}
+	return redact(content)
+}
@@ -0,0 +1,74 @@
+package finetune
+
+import (
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func chatSpan(user, assistant string) langwatch.SpanRecord {
+	userContent := user
+	return langwatch.SpanRecord{
+		Type: langwatch.SpanTypeLLM,
+		Input: &langwatch.TypedValue{
+			Type:  "chat_messages",
+			Value: []langwatch.ChatMessage{{Role: langwatch.RoleUser, Content: &userContent}},
+		},
+		Outputs: []langwatch.TypedValue{langwatch.NewTextValue(assistant)},
+	}
+}
+
+func TestExportProducesOneJSONLLinePerConversation(t *testing.T) {
+	records := []langwatch.SpanRecord{
+		chatSpan("hi", "hello!"),
+		chatSpan("bye", "goodbye!"),
+		{Type: langwatch.SpanTypeTool},
+	}
+
+	out, err := Export(records, Options{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSONL lines, got %d:\n%s", len(lines), out)
+	}
+	if !strings.Contains(lines[0], `"role":"user"`) || !strings.Contains(lines[0], "hello!") {
+		t.Fatalf("unexpected first line: %s", lines[0])
+	}
+}
+
+func TestExportAppliesRedactor(t *testing.T) {
+	records := []langwatch.SpanRecord{chatSpan("my email is a@b.com", "got it")}
+
+	out, err := Export(records, Options{Redact: func(content string) string {
+		return strings.ReplaceAll(content, "a@b.com", "[redacted]")
+	}})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if strings.Contains(string(out), "a@b.com") {
+		t.Fatalf("expected email to be redacted, got: %s", out)
+	}
+	if !strings.Contains(string(out), "[redacted]") {
+		t.Fatalf("expected redacted placeholder, got: %s", out)
+	}
+}
+
+func TestExportDedupesIdenticalConversations(t *testing.T) {
+	records := []langwatch.SpanRecord{
+		chatSpan("hi", "hello!"),
+		chatSpan("hi", "hello!"),
+	}
+
+	out, err := Export(records, Options{Dedupe: true})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected deduped output to have 1 line, got %d:\n%s", len(lines), out)
+	}
+}
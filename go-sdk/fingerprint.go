@@ -0,0 +1,59 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// metadataFingerprintChanged marks a span whose model's system_fingerprint
+// differed from the last one RecordSystemFingerprint saw for that model.
+const metadataFingerprintChanged = "langwatch.model.fingerprint_changed"
+
+// fingerprintWindow tracks the most recently observed system_fingerprint
+// per model, in-process. A single last-seen value per model is enough to
+// detect a change; it deliberately doesn't keep a longer history, since the
+// only question that matters is "did the provider just change something
+// under us".
+type fingerprintWindow struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+var fingerprints = &fingerprintWindow{last: make(map[string]string)}
+
+// observe records fingerprint as the latest value seen for model, returning
+// the previous value and whether it differs. The first fingerprint seen for
+// a model is never reported as changed - there's nothing to compare it to.
+func (w *fingerprintWindow) observe(model, fingerprint string) (previous string, changed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	previous, seen := w.last[model]
+	w.last[model] = fingerprint
+	return previous, seen && previous != fingerprint
+}
+
+// RecordSystemFingerprint reports the system_fingerprint value a provider
+// returned for model on the current call. If it differs from the last
+// fingerprint seen for that model in this process, it's a signal the
+// provider silently updated the model version: an
+// EventModelFingerprintChanged is published, and the span found in ctx (if
+// any) is stamped with langwatch.model.fingerprint_changed, so quality
+// regressions can be correlated with provider-side changes rather than
+// mistaken for a bug in the caller's own prompt or code.
+func RecordSystemFingerprint(ctx context.Context, model, fingerprint string) {
+	if model == "" || fingerprint == "" {
+		return
+	}
+	previous, changed := fingerprints.observe(model, fingerprint)
+	if !changed {
+		return
+	}
+	if span, ok := SpanFromContext(ctx); ok {
+		span.SetMetadata(metadataFingerprintChanged, fingerprint)
+	}
+	publish(ctx, Event{
+		Type:   EventModelFingerprintChanged,
+		Reason: fmt.Sprintf("model=%s previous_fingerprint=%s current_fingerprint=%s", model, previous, fingerprint),
+	})
+}
@@ -0,0 +1,65 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordSystemFingerprintNoOpOnFirstObservation(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordSystemFingerprint(ctx, "fp-test-model-1", "fp_abc123")
+	span.End()
+
+	if _, ok := span.metadata[metadataFingerprintChanged]; ok {
+		t.Fatalf("expected no fingerprint_changed metadata on the first observation, got %+v", span.metadata)
+	}
+}
+
+func TestRecordSystemFingerprintDetectsChange(t *testing.T) {
+	model := "fp-test-model-2"
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	RecordSystemFingerprint(ctx, model, "fp_abc123")
+	span.End()
+
+	var events []Event
+	Subscribe(func(_ context.Context, e Event) { events = append(events, e) })
+
+	ctx, span = StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	RecordSystemFingerprint(ctx, model, "fp_def456")
+	span.End()
+
+	if span.metadata[metadataFingerprintChanged] != "fp_def456" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataFingerprintChanged, span.metadata[metadataFingerprintChanged], "fp_def456")
+	}
+
+	found := false
+	for _, e := range events {
+		if e.Type == EventModelFingerprintChanged {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected an EventModelFingerprintChanged to be published")
+	}
+}
+
+func TestRecordSystemFingerprintNoOpWhenUnchanged(t *testing.T) {
+	model := "fp-test-model-3"
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	RecordSystemFingerprint(ctx, model, "fp_stable")
+	span.End()
+
+	ctx, span = StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	RecordSystemFingerprint(ctx, model, "fp_stable")
+	span.End()
+
+	if _, ok := span.metadata[metadataFingerprintChanged]; ok {
+		t.Fatalf("expected no fingerprint_changed metadata when the fingerprint is unchanged, got %+v", span.metadata)
+	}
+}
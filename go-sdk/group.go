@@ -0,0 +1,70 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// TraceGroup runs a fixed set of goroutine tasks under one parent span,
+// giving each task its own named child span (and duration) instead of the
+// bare sync.WaitGroup our agent fan-out code otherwise falls back to,
+// which loses that structure entirely.
+type TraceGroup struct {
+	ctx    context.Context
+	parent *Span
+
+	mu   sync.Mutex
+	wg   sync.WaitGroup
+	errs []error
+}
+
+// Group starts a parent span named name and returns a *TraceGroup that
+// runs tasks under it via Go. The returned context carries the parent
+// span, so a task's own StartSpan calls (and Group.Go's per-task spans)
+// nest under it.
+func Group(ctx context.Context, name string) (context.Context, *TraceGroup) {
+	ctx, parent := StartSpan(ctx, name, WithType(SpanTypeChain))
+	return ctx, &TraceGroup{ctx: ctx, parent: parent}
+}
+
+// Go runs fn in its own goroutine, under a child span named taskName. A
+// non-nil error is recorded on the child span and aggregated onto the
+// group's parent span, returned (joined, if more than one task failed)
+// from Wait.
+func (g *TraceGroup) Go(taskName string, fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ctx, span := StartSpan(g.ctx, taskName)
+		err := fn(ctx)
+		if err != nil {
+			span.RecordError(ErrorCapture{Message: err.Error()})
+		}
+		span.End()
+
+		if err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every task started with Go has returned, ends the
+// group's parent span, and returns the aggregated task errors (nil if none
+// failed).
+func (g *TraceGroup) Wait() error {
+	g.wg.Wait()
+	defer g.parent.End()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	err := errors.Join(g.errs...)
+	g.parent.RecordError(ErrorCapture{Message: err.Error()})
+	return err
+}
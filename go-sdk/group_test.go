@@ -0,0 +1,73 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroupWaitReturnsNilWhenAllTasksSucceed(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, group := Group(ctx, "fanout")
+
+	var ran [3]bool
+	for i := 0; i < 3; i++ {
+		i := i
+		group.Go("task", func(ctx context.Context) error {
+			ran[i] = true
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	for i, r := range ran {
+		if !r {
+			t.Fatalf("task %d did not run", i)
+		}
+	}
+}
+
+func TestGroupWaitAggregatesTaskErrors(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, group := Group(ctx, "fanout")
+
+	errA := errors.New("task a failed")
+	errB := errors.New("task b failed")
+	group.Go("task_a", func(ctx context.Context) error { return errA })
+	group.Go("task_b", func(ctx context.Context) error { return errB })
+	group.Go("task_c", func(ctx context.Context) error { return nil })
+
+	err := group.Wait()
+	if err == nil {
+		t.Fatal("expected Wait to return an aggregated error")
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected the aggregated error to wrap both task errors, got %v", err)
+	}
+}
+
+func TestGroupRecordsParentSpanOnFailure(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	ctx, group := Group(ctx, "fanout")
+
+	group.Go("task_a", func(ctx context.Context) error { return errors.New("boom") })
+	if err := group.Wait(); err == nil {
+		t.Fatal("expected Wait to return an error")
+	}
+
+	spans := trace.Spans()
+	var parent *Span
+	for _, s := range spans {
+		if s.name == "fanout" {
+			parent = s
+		}
+	}
+	if parent == nil {
+		t.Fatal("expected a parent span named \"fanout\"")
+	}
+	if parent.err == nil {
+		t.Fatal("expected the parent span to record the aggregated error")
+	}
+}
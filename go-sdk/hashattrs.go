@@ -0,0 +1,85 @@
+package langwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Well-known attribute names accepted by WithHashedAttributes that map to a
+// CollectorRequest field rather than span metadata.
+const (
+	AttributeUserID     = "langwatch.user_id"
+	AttributeThreadID   = "langwatch.thread_id"
+	AttributeCustomerID = "langwatch.customer_id"
+)
+
+// HashSaltProvider returns the salt used to hash attributes configured via
+// WithHashedAttributes. It's called on every export, so the salt can be
+// rotated (e.g. read from a secrets manager) without restarting the
+// process.
+type HashSaltProvider func(ctx context.Context) string
+
+// WithHashedAttributes makes the exporter replace the named attributes with
+// a salted SHA-256 hash before the request leaves the process. Recognized
+// names are AttributeUserID, AttributeThreadID and AttributeCustomerID;
+// anything else is looked up in each span's metadata (e.g. "enduser.id").
+// Hashing preserves grouping in analytics - the same input always hashes to
+// the same value for a given salt - without the raw identifier ever
+// reaching the collector.
+func WithHashedAttributes(names ...string) ExporterOption {
+	return func(e *httpExporter) { e.hashedAttributes = names }
+}
+
+// WithHashSalt sets a fixed salt for WithHashedAttributes. Use
+// WithHashSaltProvider instead if the salt needs to be rotatable without a
+// redeploy.
+func WithHashSalt(salt string) ExporterOption {
+	return func(e *httpExporter) { e.hashSaltProvider = func(ctx context.Context) string { return salt } }
+}
+
+// WithHashSaltProvider sets the salt resolution used by
+// WithHashedAttributes, called fresh on every export so the salt can be
+// rotated at runtime.
+func WithHashSaltProvider(provider HashSaltProvider) ExporterOption {
+	return func(e *httpExporter) { e.hashSaltProvider = provider }
+}
+
+// hashAttributes rewrites the attributes named in e.hashedAttributes on req
+// in place with their salted hash.
+func (e *httpExporter) hashAttributes(ctx context.Context, req *CollectorRequest) {
+	if len(e.hashedAttributes) == 0 {
+		return
+	}
+	var salt string
+	if e.hashSaltProvider != nil {
+		salt = e.hashSaltProvider(ctx)
+	}
+	for _, name := range e.hashedAttributes {
+		switch name {
+		case AttributeUserID:
+			if req.UserID != "" {
+				req.UserID = hashAttributeValue(salt, req.UserID)
+			}
+		case AttributeThreadID:
+			if req.ThreadID != "" {
+				req.ThreadID = hashAttributeValue(salt, req.ThreadID)
+			}
+		case AttributeCustomerID:
+			if req.CustomerID != "" {
+				req.CustomerID = hashAttributeValue(salt, req.CustomerID)
+			}
+		default:
+			for i := range req.Spans {
+				if v, ok := req.Spans[i].Metadata[name]; ok {
+					req.Spans[i].Metadata[name] = hashAttributeValue(salt, v)
+				}
+			}
+		}
+	}
+}
+
+func hashAttributeValue(salt, value string) string {
+	sum := sha256.Sum256([]byte(salt + value))
+	return hex.EncodeToString(sum[:])
+}
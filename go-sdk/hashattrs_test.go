@@ -0,0 +1,47 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHashAttributesHashesKnownAndCustomKeys(t *testing.T) {
+	e := &httpExporter{
+		hashedAttributes: []string{AttributeUserID, "enduser.id"},
+		hashSaltProvider: func(ctx context.Context) string { return "pepper" },
+	}
+
+	req := CollectorRequest{
+		UserID: "user-42",
+		Spans: []SpanRecord{
+			{ID: "s1", Metadata: map[string]string{"enduser.id": "user-42", "other": "keep-me"}},
+		},
+	}
+	e.hashAttributes(context.Background(), &req)
+
+	want := hashAttributeValue("pepper", "user-42")
+	if req.UserID != want {
+		t.Fatalf("UserID = %q, want %q", req.UserID, want)
+	}
+	if req.Spans[0].Metadata["enduser.id"] != want {
+		t.Fatalf("Metadata[enduser.id] = %q, want %q", req.Spans[0].Metadata["enduser.id"], want)
+	}
+	if req.Spans[0].Metadata["other"] != "keep-me" {
+		t.Fatal("expected untouched metadata keys to be left alone")
+	}
+}
+
+func TestHashAttributesNoopWhenUnconfigured(t *testing.T) {
+	e := &httpExporter{}
+	req := CollectorRequest{UserID: "user-42"}
+	e.hashAttributes(context.Background(), &req)
+	if req.UserID != "user-42" {
+		t.Fatal("expected UserID to be left untouched when no attributes are configured for hashing")
+	}
+}
+
+func TestHashAttributeValueIsSaltSensitive(t *testing.T) {
+	if hashAttributeValue("a", "value") == hashAttributeValue("b", "value") {
+		t.Fatal("expected different salts to produce different hashes")
+	}
+}
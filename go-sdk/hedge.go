@@ -0,0 +1,31 @@
+package langwatch
+
+import (
+	"context"
+	"strconv"
+)
+
+// Metadata keys set on hedged attempt spans.
+const (
+	metadataHedgeAttempt = "langwatch.hedge.attempt"
+	metadataHedgeWon     = "langwatch.hedge.won"
+)
+
+// StartHedgedAttempt starts a sibling span for the attempt'th issuance of a
+// hedged call (attempt 0 is the original request, 1+ are the duplicates
+// fired after the hedging deadline). All attempts are started from the same
+// ctx, so they share a parent and show up together in the trace, tagged
+// with which attempt number they are.
+func StartHedgedAttempt(ctx context.Context, name string, attempt int, opts ...SpanOption) (context.Context, *Span) {
+	ctx, span := StartSpan(ctx, name, opts...)
+	span.SetMetadata(metadataHedgeAttempt, strconv.Itoa(attempt))
+	return ctx, span
+}
+
+// RecordHedgeWinner marks span as the attempt whose result was actually
+// used, so hedging effectiveness (how often the duplicate wins, at what
+// latency cost) is analyzable after the fact. span accepts a SpanRecorder
+// so callers can pass a test fake in unit tests.
+func RecordHedgeWinner(span SpanRecorder) {
+	span.SetMetadata(metadataHedgeWon, "true")
+}
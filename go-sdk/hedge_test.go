@@ -0,0 +1,27 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStartHedgedAttemptAndWinner(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, original := StartHedgedAttempt(ctx, "chat", 0)
+	_, duplicate := StartHedgedAttempt(ctx, "chat", 1)
+
+	RecordHedgeWinner(duplicate)
+
+	if original.metadata[metadataHedgeAttempt] != "0" {
+		t.Fatalf("expected attempt 0, got %v", original.metadata)
+	}
+	if duplicate.metadata[metadataHedgeAttempt] != "1" {
+		t.Fatalf("expected attempt 1, got %v", duplicate.metadata)
+	}
+	if duplicate.metadata[metadataHedgeWon] != "true" {
+		t.Fatal("expected the duplicate to be marked as the winner")
+	}
+	if _, won := original.metadata[metadataHedgeWon]; won {
+		t.Fatal("expected the original not to be marked as the winner")
+	}
+}
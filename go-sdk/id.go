@@ -0,0 +1,22 @@
+package langwatch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID generates a random, URL-safe identifier used for trace and span IDs.
+// It intentionally avoids pulling in a UUID dependency for such a small need.
+func newID(prefix string) string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand.Read on a supported platform does not fail; if it
+		// somehow does, fall back to a fixed-but-unique-enough value rather
+		// than panicking from a tracing helper.
+		return prefix + "0000000000000000"
+	}
+	return prefix + hex.EncodeToString(buf)
+}
+
+func newTraceID() string { return newID("trace_") }
+func newSpanID() string  { return newID("span_") }
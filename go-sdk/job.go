@@ -0,0 +1,120 @@
+package langwatch
+
+import "context"
+
+// jobCarrierKey is the field name used inside job payloads to carry
+// propagated LangWatch context. It is nested under a single key so it can't
+// collide with the caller's own payload fields.
+const jobCarrierKey = "_langwatch"
+
+// jobCarrier is the serializable snapshot of a context's LangWatch state,
+// linking a worker-side trace back to the trace that enqueued the job.
+type jobCarrier struct {
+	TraceID    string            `json:"trace_id,omitempty"`
+	ThreadID   string            `json:"thread_id,omitempty"`
+	UserID     string            `json:"user_id,omitempty"`
+	CustomerID string            `json:"customer_id,omitempty"`
+	Labels     []string          `json:"labels,omitempty"`
+	Baggage    map[string]string `json:"baggage,omitempty"`
+}
+
+// InjectJob serializes ctx's trace link, thread/user/customer IDs and
+// baggage (including the capture kill-switch) into payload, so it can be
+// carried across a queue such as asynq or River where the HTTP context is
+// unavailable to the worker. payload must be a JSON object
+// (map[string]interface{}); InjectJob adds a single "_langwatch" key to it.
+func InjectJob(ctx context.Context, payload map[string]interface{}) map[string]interface{} {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+
+	carrier := jobCarrier{
+		ThreadID:   ThreadID(ctx),
+		UserID:     UserID(ctx),
+		CustomerID: CustomerID(ctx),
+		Labels:     Labels(ctx),
+		Baggage:    baggageFromContext(ctx),
+	}
+	if trace, ok := TraceFromContext(ctx); ok {
+		carrier.TraceID = trace.ID()
+	}
+
+	payload[jobCarrierKey] = carrier
+	return payload
+}
+
+// ExtractJob restores the LangWatch context previously attached by
+// InjectJob. The returned context carries the original thread/user/customer
+// IDs and baggage (so the capture kill-switch still applies), and a new
+// Trace linked to the enqueuing trace via the
+// "langwatch.linked_from_trace_id:<id>" label,
+// LangWatch traces having no native cross-trace links.
+func ExtractJob(ctx context.Context, payload map[string]interface{}) context.Context {
+	raw, ok := payload[jobCarrierKey]
+	if !ok {
+		return ctx
+	}
+
+	// payload typically arrives already round-tripped through JSON (e.g.
+	// unmarshaled from the queue), so the carrier comes back as a generic
+	// map rather than the concrete struct InjectJob wrote.
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		if carrier, ok := raw.(jobCarrier); ok {
+			fields = carrierToMap(carrier)
+		} else {
+			return ctx
+		}
+	}
+
+	if threadID, ok := fields["thread_id"].(string); ok && threadID != "" {
+		ctx = WithThreadID(ctx, threadID)
+	}
+	if userID, ok := fields["user_id"].(string); ok && userID != "" {
+		ctx = WithUserID(ctx, userID)
+	}
+	if customerID, ok := fields["customer_id"].(string); ok && customerID != "" {
+		ctx = WithCustomerID(ctx, customerID)
+	}
+	if labels, ok := fields["labels"].([]interface{}); ok {
+		strs := make([]string, 0, len(labels))
+		for _, l := range labels {
+			if s, ok := l.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		ctx = WithLabels(ctx, strs...)
+	}
+	if baggage, ok := fields["baggage"].(map[string]interface{}); ok {
+		for k, v := range baggage {
+			if s, ok := v.(string); ok {
+				ctx = WithBaggage(ctx, k, s)
+			}
+		}
+	}
+
+	ctx, trace := NewTrace(ctx)
+	if traceID, ok := fields["trace_id"].(string); ok && traceID != "" {
+		trace.linkedFromTraceID = traceID
+	}
+	return ctx
+}
+
+func carrierToMap(c jobCarrier) map[string]interface{} {
+	baggage := make(map[string]interface{}, len(c.Baggage))
+	for k, v := range c.Baggage {
+		baggage[k] = v
+	}
+	labels := make([]interface{}, len(c.Labels))
+	for i, l := range c.Labels {
+		labels[i] = l
+	}
+	return map[string]interface{}{
+		"trace_id":    c.TraceID,
+		"thread_id":   c.ThreadID,
+		"user_id":     c.UserID,
+		"customer_id": c.CustomerID,
+		"labels":      labels,
+		"baggage":     baggage,
+	}
+}
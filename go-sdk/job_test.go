@@ -0,0 +1,47 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestInjectExtractJobRoundTrip(t *testing.T) {
+	ctx := WithThreadID(context.Background(), "thread-1")
+	ctx = WithUserID(ctx, "user-1")
+	ctx = WithCaptureDisabled(ctx)
+	ctx, trace := NewTrace(ctx)
+
+	payload := InjectJob(ctx, map[string]interface{}{"task": "summarize"})
+
+	// Round-trip through JSON, as it would when actually going through a
+	// queue.
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+
+	workerCtx := ExtractJob(context.Background(), decoded)
+
+	if got := ThreadID(workerCtx); got != "thread-1" {
+		t.Fatalf("expected thread-1, got %q", got)
+	}
+	if got := UserID(workerCtx); got != "user-1" {
+		t.Fatalf("expected user-1, got %q", got)
+	}
+	if CaptureEnabled(workerCtx) {
+		t.Fatal("expected capture kill-switch to survive InjectJob/ExtractJob")
+	}
+
+	workerTrace, ok := TraceFromContext(workerCtx)
+	if !ok {
+		t.Fatal("expected ExtractJob to start a new trace")
+	}
+	if workerTrace.LinkedFromTraceID() != trace.ID() {
+		t.Fatalf("expected linked trace id %q, got %q", trace.ID(), workerTrace.LinkedFromTraceID())
+	}
+}
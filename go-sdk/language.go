@@ -0,0 +1,190 @@
+package langwatch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Metadata keys tagLanguages sets when WithLanguageDetection is enabled.
+const (
+	metadataInputLanguage  = "langwatch.input.language"
+	metadataOutputLanguage = "langwatch.output.language"
+)
+
+// languageDetectionConfidenceThreshold is the minimum DetectLanguage
+// confidence required before tagLanguages stamps a language onto a span,
+// so short or ambiguous text is left untagged rather than mistagged.
+const languageDetectionConfidenceThreshold = 0.1
+
+// ngramProfileSize is how many of a text's most frequent trigrams are
+// compared against each language profile.
+const ngramProfileSize = 15
+
+// languageProfiles maps an ISO 639-1 code to its most frequent character
+// trigrams, ordered most frequent first, following the Cavnar & Trenkle
+// n-gram text categorization approach. This is intentionally a small,
+// fast heuristic - good enough to bucket traces by language for dashboard
+// slicing, not a substitute for a full language ID model.
+var languageProfiles = map[string][]string{
+	"en": {" th", "the", "he ", " to", "ing", "nd ", " an", "of ", "ion", " in", "ent", " a ", "and", "ati", "for"},
+	"es": {" de", "de ", "que", " qu", "ent", "ció", "os ", " la", "ar ", "el ", " co", " el", "ien", "aci", "ada"},
+	"fr": {" de", "de ", "ent", "les", "es ", "le ", "ion", " le", "que", " qu", " la", "tio", "our", "eur", "ait"},
+	"de": {"en ", "der", " de", "die", " un", "che", "ich", "sch", "und", " ge", "ein", "gen", "cht", "nde", " ei"},
+	"pt": {" de", "de ", "os ", "ent", " qu", "que", " co", " a ", "ada", "ar ", " pa", "oes", "com", "nao", " es"},
+}
+
+// DetectLanguage returns a best-effort guess of text's language as an ISO
+// 639-1 code, plus a confidence in [0, 1], by comparing its character
+// trigram frequencies against languageProfiles. It returns ("", 0) if text
+// is too short to classify reliably.
+func DetectLanguage(text string) (code string, confidence float64) {
+	normalized := normalizeForDetection(text)
+	if len(normalized) < 12 {
+		return "", 0
+	}
+	sample := trigramFrequencies(normalized)
+
+	bestLang, bestDistance := "", -1
+	for lang, profile := range languageProfiles {
+		distance := profileDistance(sample, profile)
+		if bestLang == "" || distance < bestDistance || (distance == bestDistance && lang < bestLang) {
+			bestLang, bestDistance = lang, distance
+		}
+	}
+	if bestLang == "" {
+		return "", 0
+	}
+
+	maxDistance := len(languageProfiles[bestLang]) * ngramProfileSize
+	confidence = 1 - float64(bestDistance)/float64(maxDistance)
+	if confidence < 0 {
+		confidence = 0
+	}
+	return bestLang, confidence
+}
+
+// normalizeForDetection lowercases text, drops everything but letters and
+// spaces, and collapses runs of whitespace, so punctuation and formatting
+// don't skew the trigram counts.
+func normalizeForDetection(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// trigramFrequencies returns text's ngramProfileSize most frequent
+// character trigrams, most frequent first, ties broken lexically for
+// determinism.
+func trigramFrequencies(text string) []string {
+	runes := []rune(" " + text + " ")
+	counts := map[string]int{}
+	for i := 0; i+3 <= len(runes); i++ {
+		counts[string(runes[i:i+3])]++
+	}
+
+	trigrams := make([]string, 0, len(counts))
+	for tri := range counts {
+		trigrams = append(trigrams, tri)
+	}
+	sort.Slice(trigrams, func(i, j int) bool {
+		if counts[trigrams[i]] != counts[trigrams[j]] {
+			return counts[trigrams[i]] > counts[trigrams[j]]
+		}
+		return trigrams[i] < trigrams[j]
+	})
+	if len(trigrams) > ngramProfileSize {
+		trigrams = trigrams[:ngramProfileSize]
+	}
+	return trigrams
+}
+
+// profileDistance is the Cavnar & Trenkle "out-of-place" distance between a
+// text's trigram ranking and a language profile: for each trigram in
+// profile, the absolute difference in rank if sample also ranks it highly,
+// or ngramProfileSize (the maximum penalty) if sample doesn't rank it at
+// all. Lower is a better match.
+func profileDistance(sample []string, profile []string) int {
+	rank := make(map[string]int, len(sample))
+	for i, tri := range sample {
+		rank[tri] = i
+	}
+
+	distance := 0
+	for i, tri := range profile {
+		r, ok := rank[tri]
+		if !ok {
+			distance += ngramProfileSize
+			continue
+		}
+		if d := r - i; d < 0 {
+			distance -= d
+		} else {
+			distance += d
+		}
+	}
+	return distance
+}
+
+// tagLanguages runs DetectLanguage over record's input and output text and,
+// for any result confident enough, stamps the language onto record's
+// metadata under metadataInputLanguage / metadataOutputLanguage.
+func tagLanguages(record *SpanRecord) {
+	if text := textFromValue(record.Input); text != "" {
+		tagLanguage(record, metadataInputLanguage, text)
+	}
+	if text := textFromOutputs(record.Outputs); text != "" {
+		tagLanguage(record, metadataOutputLanguage, text)
+	}
+}
+
+func tagLanguage(record *SpanRecord, key, text string) {
+	lang, confidence := DetectLanguage(text)
+	if confidence < languageDetectionConfidenceThreshold {
+		return
+	}
+	if record.Metadata == nil {
+		record.Metadata = map[string]string{}
+	}
+	record.Metadata[key] = lang
+}
+
+func textFromOutputs(values []TypedValue) string {
+	var parts []string
+	for _, v := range values {
+		if t := textFromValue(&v); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func textFromValue(value *TypedValue) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.Value.(type) {
+	case string:
+		return v
+	case ChatMessage:
+		if v.Content != nil {
+			return *v.Content
+		}
+	case []ChatMessage:
+		var parts []string
+		for _, m := range v {
+			if m.Content != nil {
+				parts = append(parts, *m.Content)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package langwatch
+
+import "testing"
+
+func TestDetectLanguageDistinguishesEnglishAndSpanish(t *testing.T) {
+	en, enConfidence := DetectLanguage("The quick brown fox jumps over the lazy dog and runs into the forest")
+	if en != "en" {
+		t.Fatalf("English text detected as %q (confidence %.2f)", en, enConfidence)
+	}
+
+	es, esConfidence := DetectLanguage("El rápido zorro marrón salta sobre el perro perezoso y corre hacia el bosque")
+	if es != "es" {
+		t.Fatalf("Spanish text detected as %q (confidence %.2f)", es, esConfidence)
+	}
+}
+
+func TestDetectLanguageReturnsNoResultForShortText(t *testing.T) {
+	lang, confidence := DetectLanguage("hi there")
+	if lang != "" || confidence != 0 {
+		t.Fatalf("DetectLanguage(short) = (%q, %.2f), want (\"\", 0)", lang, confidence)
+	}
+}
+
+func TestTagLanguagesStampsInputAndOutputMetadata(t *testing.T) {
+	record := &SpanRecord{
+		Input:   &TypedValue{Type: "text", Value: "The quick brown fox jumps over the lazy dog and runs into the forest"},
+		Outputs: []TypedValue{{Type: "text", Value: "El rápido zorro marrón salta sobre el perro perezoso y corre hacia el bosque"}},
+	}
+
+	tagLanguages(record)
+
+	if record.Metadata[metadataInputLanguage] != "en" {
+		t.Fatalf("input language = %q, want en", record.Metadata[metadataInputLanguage])
+	}
+	if record.Metadata[metadataOutputLanguage] != "es" {
+		t.Fatalf("output language = %q, want es", record.Metadata[metadataOutputLanguage])
+	}
+}
+
+func TestTagLanguagesLeavesShortTextUntagged(t *testing.T) {
+	record := &SpanRecord{Input: &TypedValue{Type: "text", Value: "ok"}}
+
+	tagLanguages(record)
+
+	if _, ok := record.Metadata[metadataInputLanguage]; ok {
+		t.Fatal("expected no language tag for text too short to classify")
+	}
+}
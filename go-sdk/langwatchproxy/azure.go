@@ -0,0 +1,51 @@
+package langwatchproxy
+
+import (
+	"net/url"
+	"regexp"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Metadata keys set on a span whose request matched the Azure OpenAI URL
+// shape. metadataGenAISystem uses the OTel GenAI semantic convention's own
+// attribute name (see providerregion.go for the same kind of deliberate
+// deviation from this SDK's usual langwatch.* prefix), since it's a value
+// other tooling built against that convention already expects.
+const (
+	metadataGenAISystem     = "gen_ai.system"
+	metadataAzureDeployment = "langwatch.azure.deployment"
+	metadataAzureAPIVersion = "langwatch.azure.api_version"
+)
+
+var azureDeploymentPath = regexp.MustCompile(`^/openai/deployments/([^/]+)/chat/completions$`)
+
+// azureOpenAIInfo reports whether reqURL matches Azure OpenAI's chat
+// completions URL shape (/openai/deployments/{deployment}/chat/completions
+// with an api-version query parameter), and if so extracts the deployment
+// name and api-version. The request body underneath is the same OpenAI
+// chat completions shape either way, so no separate response parsing is
+// needed - only these two attributes are Azure-specific.
+func azureOpenAIInfo(reqURL *url.URL) (deployment, apiVersion string, ok bool) {
+	match := azureDeploymentPath.FindStringSubmatch(reqURL.Path)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], reqURL.Query().Get("api-version"), true
+}
+
+// recordAzureOpenAI stamps span with gen_ai.system=azure.openai plus the
+// deployment/api-version attributes if reqURL matches the Azure OpenAI URL
+// shape. It's a no-op for any other URL, so the same Director path handles
+// plain OpenAI and OpenAI-compatible upstreams unchanged.
+func recordAzureOpenAI(span *langwatch.Span, reqURL *url.URL) {
+	deployment, apiVersion, ok := azureOpenAIInfo(reqURL)
+	if !ok {
+		return
+	}
+	span.SetMetadata(metadataGenAISystem, "azure.openai")
+	span.SetMetadata(metadataAzureDeployment, deployment)
+	if apiVersion != "" {
+		span.SetMetadata(metadataAzureAPIVersion, apiVersion)
+	}
+}
@@ -0,0 +1,27 @@
+package langwatchproxy
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestAzureOpenAIInfoMatchesDeploymentPath(t *testing.T) {
+	u, _ := url.Parse("/openai/deployments/gpt-4o-prod/chat/completions?api-version=2024-02-15-preview")
+	deployment, apiVersion, ok := azureOpenAIInfo(u)
+	if !ok {
+		t.Fatal("expected a match for an Azure OpenAI deployment path")
+	}
+	if deployment != "gpt-4o-prod" {
+		t.Fatalf("deployment = %q, want %q", deployment, "gpt-4o-prod")
+	}
+	if apiVersion != "2024-02-15-preview" {
+		t.Fatalf("apiVersion = %q, want %q", apiVersion, "2024-02-15-preview")
+	}
+}
+
+func TestAzureOpenAIInfoNoMatchForPlainOpenAIPath(t *testing.T) {
+	u, _ := url.Parse("/v1/chat/completions")
+	if _, _, ok := azureOpenAIInfo(u); ok {
+		t.Fatal("expected no match for a plain OpenAI path")
+	}
+}
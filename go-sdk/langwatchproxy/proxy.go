@@ -0,0 +1,196 @@
+// Package langwatchproxy instruments OpenAI-compatible HTTP traffic passing
+// through a reverse proxy, letting services that can't (or shouldn't) be
+// changed to import the SDK directly still show up in LangWatch.
+package langwatchproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// StatusMapper decides whether an upstream HTTP response should mark its
+// span as failed, given the response's status code and, when the response
+// body parsed as a provider-style error ({"error": {"type": "..."}}), the
+// provider's own error type string (e.g. OpenAI's "invalid_request_error").
+// providerErrType is "" when the body didn't parse as one.
+//
+// The default mapper treats any status >= 400 as an error. Teams that
+// expect (and don't want flagged) routine 4xx responses, e.g. validation
+// failures from their own request construction, can install a narrower
+// mapper via Options.StatusMapper.
+type StatusMapper func(httpStatus int, providerErrType string) bool
+
+func defaultStatusMapper(httpStatus int, providerErrType string) bool {
+	return httpStatus >= 400
+}
+
+// Options configures the proxy handler.
+type Options struct {
+	// Exporter ships completed traces to LangWatch. Required.
+	Exporter langwatch.Exporter
+	// Vendor is recorded on every span, e.g. "openai".
+	Vendor string
+	// StatusMapper overrides which upstream responses mark a span as
+	// failed. Defaults to treating any status >= 400 as an error.
+	StatusMapper StatusMapper
+	// GenAISystem, when set, is recorded as gen_ai.system on every span
+	// this handler creates. Use it for OpenAI-compatible gateways (vLLM,
+	// Groq, Together, OpenRouter, ...) where the upstream isn't OpenAI
+	// itself, so spans still carry the right provider attribute instead
+	// of being left untagged.
+	GenAISystem string
+	// BasePathPrefix, when set, is stripped from the request path before
+	// it's used as the span name. Some OpenAI-compatible gateways mount
+	// their chat completions endpoint under a nonstandard prefix (e.g.
+	// "/api/openai/v1/chat/completions" instead of "/v1/chat/completions");
+	// the request/response parsing in Director and ModifyResponse already
+	// works regardless of path shape, so this only affects naming.
+	BasePathPrefix string
+}
+
+type providerErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type chatRequest struct {
+	Model    string                  `json:"model"`
+	Messages []langwatch.ChatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      langwatch.ChatMessage `json:"message"`
+		FinishReason string                `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type spanContextKey struct{}
+
+func init() {
+	langwatch.RegisterInstrumentation("langwatchproxy")
+}
+
+// NewHandler returns a reverse proxy handler that forwards every request to
+// upstream unmodified, while recording a LangWatch LLM span per request/
+// response pair. It reads the request body in Director and the response
+// body in ModifyResponse, so it works for any client - Go or otherwise -
+// speaking an OpenAI-compatible chat completions API, without requiring
+// code changes on the caller's side.
+func NewHandler(upstream *url.URL, opts Options) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	baseDirector := proxy.Director
+
+	proxy.Director = func(r *http.Request) {
+		path := r.URL.Path
+		if opts.BasePathPrefix != "" {
+			path = strings.TrimPrefix(path, opts.BasePathPrefix)
+		}
+		spanOpts := []langwatch.SpanOption{
+			langwatch.WithType(langwatch.SpanTypeLLM),
+			langwatch.WithKind(langwatch.SpanKindClient),
+			langwatch.WithServerAddress(upstream.Host),
+		}
+		if opts.Vendor != "" {
+			spanOpts = append(spanOpts, langwatch.WithModel(opts.Vendor, ""))
+		}
+		ctx, span := langwatch.StartSpan(r.Context(), "proxy:"+path, spanOpts...)
+		if opts.GenAISystem != "" {
+			span.SetMetadata(metadataGenAISystem, opts.GenAISystem)
+		}
+		recordAzureOpenAI(span, r.URL)
+
+		if langwatch.CaptureEnabled(ctx) && r.Body != nil {
+			if body, err := io.ReadAll(r.Body); err == nil {
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				var parsed chatRequest
+				if json.Unmarshal(body, &parsed) == nil {
+					span.RecordInput(langwatch.NewChatMessagesValue(parsed.Messages))
+				}
+			}
+		}
+
+		*r = *r.WithContext(context.WithValue(ctx, spanContextKey{}, span))
+		baseDirector(r)
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		span, _ := resp.Request.Context().Value(spanContextKey{}).(*langwatch.Span)
+		if span == nil {
+			return nil
+		}
+		defer span.End()
+
+		langwatch.RecordProviderRegion(resp.Request.Context(), resp.Request.URL.String())
+
+		if !langwatch.CaptureEnabled(resp.Request.Context()) {
+			return nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		mapper := opts.StatusMapper
+		if mapper == nil {
+			mapper = defaultStatusMapper
+		}
+		var providerErr providerErrorResponse
+		_ = json.Unmarshal(body, &providerErr)
+		if mapper(resp.StatusCode, providerErr.Error.Type) {
+			message := providerErr.Error.Message
+			if message == "" {
+				message = fmt.Sprintf("upstream returned status %d", resp.StatusCode)
+			}
+			span.RecordError(langwatch.ErrorCapture{Message: message})
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if retryAfter := langwatch.ParseRetryAfter(resp.Header); retryAfter != nil {
+				langwatch.RecordRetryAfter(resp.Request.Context(), retryAfter)
+			}
+		}
+
+		var parsed chatResponse
+		if json.Unmarshal(body, &parsed) == nil && len(parsed.Choices) > 0 {
+			messages := make([]langwatch.ChatMessage, 0, len(parsed.Choices))
+			for _, choice := range parsed.Choices {
+				messages = append(messages, choice.Message)
+			}
+			span.RecordOutput(langwatch.NewChatMessagesValue(messages))
+
+			langwatch.RecordUsage(resp.Request.Context(), langwatch.Usage{
+				Input:  parsed.Usage.PromptTokens,
+				Output: parsed.Usage.CompletionTokens,
+			})
+		}
+		return nil
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, trace := langwatch.NewTrace(r.Context())
+		proxy.ServeHTTP(w, r.WithContext(ctx))
+		if err := opts.Exporter.Export(ctx, trace); err != nil {
+			// best-effort: a broken exporter should not break the proxied
+			// request itself.
+			_ = err
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package langwatchproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type fakeExporter struct {
+	mu     sync.Mutex
+	traces []*langwatch.Trace
+}
+
+func (f *fakeExporter) Export(ctx context.Context, trace *langwatch.Trace) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.traces = append(f.traces, trace)
+	return nil
+}
+
+func TestNewHandlerRecordsSpan(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1}}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, Vendor: "openai"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{"model":"gpt-4o","messages":[{"role":"user","content":"hello"}]}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	if len(exporter.traces) != 1 {
+		t.Fatalf("expected 1 exported trace, got %d", len(exporter.traces))
+	}
+	spans := exporter.traces[0].Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+}
+
+func recordedError(t *testing.T, trace *langwatch.Trace) *langwatch.ErrorCapture {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	return matches[0].Span.Error
+}
+
+func recordedSpan(t *testing.T, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	return matches[0].Span
+}
+
+func TestNewHandlerRecordsRetryAfterOn429(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"type":"rate_limit_exceeded","message":"too many requests"}}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, Vendor: "openai"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	record := recordedSpan(t, trace)
+	if record.Metadata["langwatch.retry_after.seconds"] != "5" {
+		t.Fatalf("expected retry-after metadata to be recorded, got %+v", record.Metadata)
+	}
+}
+
+func TestNewHandlerLeavesProviderRegionUnsetForUnrecognizedHost(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, Vendor: "openai"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	record := recordedSpan(t, trace)
+	if _, ok := record.Metadata["gen_ai.provider.region"]; ok {
+		t.Fatalf("expected no region metadata for a test upstream host, got %+v", record.Metadata)
+	}
+}
+
+func TestNewHandlerMarksErrorOnDefault4xx(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"missing field"}}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, Vendor: "openai"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	capture := recordedError(t, trace)
+	if capture == nil || capture.Message != "missing field" {
+		t.Fatalf("expected the 4xx response to be recorded as a span error, got %+v", capture)
+	}
+}
+
+func TestNewHandlerWithStatusMapperIgnoresExpected4xx(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"missing field"}}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{
+		Exporter: exporter,
+		Vendor:   "openai",
+		StatusMapper: func(httpStatus int, providerErrType string) bool {
+			return httpStatus >= 500 || providerErrType == "rate_limit_error"
+		},
+	})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	if capture := recordedError(t, trace); capture != nil {
+		t.Fatalf("expected a custom StatusMapper to suppress the error, got %+v", capture)
+	}
+}
+
+func TestNewHandlerRecordsGenAISystemForCompatibleGateway(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"llama3-70b","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, GenAISystem: "groq"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/openai/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	record := recordedSpan(t, trace)
+	if record.Metadata["gen_ai.system"] != "groq" {
+		t.Fatalf("expected gen_ai.system=groq, got %+v", record.Metadata)
+	}
+}
+
+func TestNewHandlerStripsBasePathPrefixFromSpanName(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"llama3-70b","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter, BasePathPrefix: "/api/openai-gateway"})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/openai-gateway/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	record := recordedSpan(t, trace)
+	if record.Name != "proxy:/v1/chat/completions" {
+		t.Fatalf("expected span name with prefix stripped, got %q", record.Name)
+	}
+}
+
+func TestNewHandlerRecordsClientKindAndServerAddress(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	exporter := &fakeExporter{}
+	proxy := NewHandler(upstreamURL, Options{Exporter: exporter})
+
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	exporter.mu.Lock()
+	trace := exporter.traces[0]
+	exporter.mu.Unlock()
+
+	record := recordedSpan(t, trace)
+	if record.Metadata["langwatch.span.kind"] != "client" {
+		t.Fatalf("expected span kind client, got %+v", record.Metadata)
+	}
+	if record.Metadata["server.address"] != upstreamURL.Host {
+		t.Fatalf("expected server.address %q, got %+v", upstreamURL.Host, record.Metadata)
+	}
+}
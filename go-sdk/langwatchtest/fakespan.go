@@ -0,0 +1,96 @@
+// Package langwatchtest provides test doubles for code that depends on the
+// LangWatch SDK, so unit tests don't need a real trace, exporter, or network
+// access just to assert what a function recorded.
+package langwatchtest
+
+import (
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// FakeSpan implements langwatch.SpanRecorder, recording every call made to
+// it so tests can assert on what a function under test recorded without
+// needing a real *langwatch.Span (which requires a trace and an exporter).
+type FakeSpan struct {
+	SpanID string
+
+	Inputs          []langwatch.TypedValue
+	Outputs         []langwatch.TypedValue
+	ExpectedOutputs []langwatch.TypedValue
+	Errors          []langwatch.ErrorCapture
+	Metrics         []langwatch.Metrics
+	Params          []langwatch.Params
+	Metadata        map[string]string
+	FirstTokenCalls int
+	Marks           []string
+	TimelineEvents  []langwatch.TimelineEvent
+	Ended           bool
+}
+
+// NewFakeSpan returns a FakeSpan identified by id, for tests that assert on
+// the span ID (e.g. parent/child linking).
+func NewFakeSpan(id string) *FakeSpan {
+	return &FakeSpan{SpanID: id}
+}
+
+var _ langwatch.SpanRecorder = (*FakeSpan)(nil)
+
+// ID returns the span's ID, as given to NewFakeSpan.
+func (s *FakeSpan) ID() string { return s.SpanID }
+
+// RecordInput appends value to Inputs.
+func (s *FakeSpan) RecordInput(value langwatch.TypedValue) {
+	s.Inputs = append(s.Inputs, value)
+}
+
+// RecordOutput appends value to Outputs.
+func (s *FakeSpan) RecordOutput(value langwatch.TypedValue) {
+	s.Outputs = append(s.Outputs, value)
+}
+
+// RecordExpectedOutput appends value to ExpectedOutputs.
+func (s *FakeSpan) RecordExpectedOutput(value langwatch.TypedValue) {
+	s.ExpectedOutputs = append(s.ExpectedOutputs, value)
+}
+
+// RecordError appends err to Errors.
+func (s *FakeSpan) RecordError(err langwatch.ErrorCapture) {
+	s.Errors = append(s.Errors, err)
+}
+
+// RecordMetrics appends m to Metrics.
+func (s *FakeSpan) RecordMetrics(m langwatch.Metrics) {
+	s.Metrics = append(s.Metrics, m)
+}
+
+// RecordParams appends p to Params.
+func (s *FakeSpan) RecordParams(p langwatch.Params) {
+	s.Params = append(s.Params, p)
+}
+
+// SetMetadata records key/value in Metadata.
+func (s *FakeSpan) SetMetadata(key, value string) {
+	if s.Metadata == nil {
+		s.Metadata = map[string]string{}
+	}
+	s.Metadata[key] = value
+}
+
+// RecordFirstToken increments FirstTokenCalls.
+func (s *FakeSpan) RecordFirstToken() {
+	s.FirstTokenCalls++
+}
+
+// Mark appends name to Marks.
+func (s *FakeSpan) Mark(name string) {
+	s.Marks = append(s.Marks, name)
+}
+
+// AddTimelineEvent appends a TimelineEvent to TimelineEvents.
+func (s *FakeSpan) AddTimelineEvent(name string, attrs map[string]string) {
+	s.TimelineEvents = append(s.TimelineEvents, langwatch.TimelineEvent{Name: name, Attributes: attrs})
+}
+
+// End sets Ended to true.
+func (s *FakeSpan) End() {
+	s.Ended = true
+}
@@ -0,0 +1,59 @@
+package langwatchtest
+
+import (
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestFakeSpanRecordsCalls(t *testing.T) {
+	span := NewFakeSpan("span-1")
+
+	span.RecordInput(langwatch.NewTextValue("hi"))
+	span.RecordOutput(langwatch.NewTextValue("hello"))
+	span.RecordExpectedOutput(langwatch.NewTextValue("hello"))
+	span.RecordError(langwatch.ErrorCapture{Message: "boom"})
+	span.RecordMetrics(langwatch.Metrics{})
+	span.RecordParams(langwatch.Params{})
+	span.SetMetadata("k", "v")
+	span.RecordFirstToken()
+	span.Mark("retrieval_done")
+	span.AddTimelineEvent("cache_hit", map[string]string{"key": "abc"})
+	span.End()
+
+	if span.ID() != "span-1" {
+		t.Fatalf("ID() = %q, want span-1", span.ID())
+	}
+	if len(span.Inputs) != 1 || len(span.Outputs) != 1 || len(span.ExpectedOutputs) != 1 {
+		t.Fatal("expected one recorded input, output and expected output")
+	}
+	if len(span.Errors) != 1 || span.Errors[0].Message != "boom" {
+		t.Fatal("expected the recorded error to be kept")
+	}
+	if len(span.Metrics) != 1 || len(span.Params) != 1 {
+		t.Fatal("expected one recorded metrics and params entry")
+	}
+	if span.Metadata["k"] != "v" {
+		t.Fatal("expected metadata to be recorded")
+	}
+	if span.FirstTokenCalls != 1 {
+		t.Fatalf("FirstTokenCalls = %d, want 1", span.FirstTokenCalls)
+	}
+	if len(span.Marks) != 1 || span.Marks[0] != "retrieval_done" {
+		t.Fatalf("Marks = %v, want [retrieval_done]", span.Marks)
+	}
+	if len(span.TimelineEvents) != 1 || span.TimelineEvents[0].Name != "cache_hit" {
+		t.Fatalf("TimelineEvents = %v, want one cache_hit event", span.TimelineEvents)
+	}
+	if !span.Ended {
+		t.Fatal("expected End to mark the span as ended")
+	}
+}
+
+func TestRecordHedgeWinnerAcceptsFakeSpan(t *testing.T) {
+	span := NewFakeSpan("span-1")
+	langwatch.RecordHedgeWinner(span)
+	if span.Metadata["langwatch.hedge.won"] != "true" {
+		t.Fatal("expected RecordHedgeWinner to set metadata on the fake span")
+	}
+}
@@ -0,0 +1,194 @@
+package langwatchtest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// metadataSnapshotMismatch marks a span recorded when a Snapshot comparison
+// fails, so it can be found in LangWatch alongside the other spans from the
+// same test run.
+const metadataSnapshotMismatch = "langwatch.snapshot.mismatch"
+
+var updateSnapshots = flag.Bool("langwatchtest.update", false, "write Snapshot's committed golden files instead of comparing against them")
+
+// ToleranceMode selects how Snapshot compares a model output against its
+// committed golden file.
+type ToleranceMode string
+
+const (
+	// ToleranceExact requires a byte-for-byte match. The default.
+	ToleranceExact ToleranceMode = "exact"
+	// ToleranceNormalizedWhitespace collapses runs of whitespace to a
+	// single space on both sides before comparing, for outputs whose
+	// formatting is allowed to drift.
+	ToleranceNormalizedWhitespace ToleranceMode = "normalized_whitespace"
+	// ToleranceEmbeddingSimilarity accepts outputs whose embedding cosine
+	// similarity to the golden file meets a configured threshold, for
+	// outputs whose wording is allowed to drift as long as the meaning
+	// doesn't. Set via WithEmbeddingSimilarity.
+	ToleranceEmbeddingSimilarity ToleranceMode = "embedding_similarity"
+)
+
+// Embedder computes embedding vectors for a batch of texts. The
+// embeddings package's Client interface already satisfies this.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error)
+}
+
+// SnapshotOption configures a Snapshot call.
+type SnapshotOption func(*snapshotConfig)
+
+type snapshotConfig struct {
+	ctx                 context.Context
+	dir                 string
+	tolerance           ToleranceMode
+	embedder            Embedder
+	similarityThreshold float64
+}
+
+// WithContext records the mismatch span (if any) on the trace found in ctx,
+// instead of doing nothing. Defaults to context.Background(), under which
+// mismatches are reported via t.Errorf only.
+func WithContext(ctx context.Context) SnapshotOption {
+	return func(c *snapshotConfig) { c.ctx = ctx }
+}
+
+// WithSnapshotDir overrides where golden files are read from and written
+// to. Defaults to "testdata/snapshots".
+func WithSnapshotDir(dir string) SnapshotOption {
+	return func(c *snapshotConfig) { c.dir = dir }
+}
+
+// WithNormalizedWhitespace compares with ToleranceNormalizedWhitespace.
+func WithNormalizedWhitespace() SnapshotOption {
+	return func(c *snapshotConfig) { c.tolerance = ToleranceNormalizedWhitespace }
+}
+
+// WithEmbeddingSimilarity compares with ToleranceEmbeddingSimilarity,
+// using embedder to embed both sides and accepting the match if their
+// cosine similarity is at least threshold.
+func WithEmbeddingSimilarity(embedder Embedder, threshold float64) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.tolerance = ToleranceEmbeddingSimilarity
+		c.embedder = embedder
+		c.similarityThreshold = threshold
+	}
+}
+
+// Snapshot compares output against the committed golden file
+// testdata/snapshots/<name>.snap, failing the test via t.Errorf on
+// mismatch. If the golden file doesn't exist yet, or the package's tests
+// are run with -langwatchtest.update, it's (re)written from output instead
+// of compared against.
+//
+// This SDK has no dedicated experiment/evaluation result type, so a
+// mismatch is recorded the same way Span.RecordExpectedOutput already
+// represents a supervised expectation: a span named "snapshot:<name>"
+// carrying the golden file as its expected output, output as its actual
+// output, and langwatch.snapshot.mismatch metadata - exported like any
+// other span if WithContext points at a real trace.
+func Snapshot(t *testing.T, name, output string, opts ...SnapshotOption) {
+	t.Helper()
+
+	cfg := &snapshotConfig{
+		ctx:                 context.Background(),
+		dir:                 filepath.Join("testdata", "snapshots"),
+		tolerance:           ToleranceExact,
+		similarityThreshold: 0.9,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	path := filepath.Join(cfg.dir, name+".snap")
+
+	if *updateSnapshots {
+		writeSnapshot(t, path, output)
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		writeSnapshot(t, path, output)
+		t.Logf("langwatchtest: wrote new snapshot %s (re-run to compare)", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("langwatchtest: read snapshot %s: %v", path, err)
+	}
+
+	if ok, detail := compareSnapshot(cfg, string(expected), output); !ok {
+		recordSnapshotMismatch(cfg.ctx, name, string(expected), output)
+		t.Errorf("langwatchtest: snapshot %q mismatch (%s)\n--- want ---\n%s\n--- got ---\n%s", name, detail, expected, output)
+	}
+}
+
+func writeSnapshot(t *testing.T, path, output string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("langwatchtest: create snapshot dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+		t.Fatalf("langwatchtest: write snapshot %s: %v", path, err)
+	}
+}
+
+func compareSnapshot(cfg *snapshotConfig, expected, actual string) (bool, string) {
+	switch cfg.tolerance {
+	case ToleranceNormalizedWhitespace:
+		return normalizeWhitespace(expected) == normalizeWhitespace(actual), "normalized whitespace"
+	case ToleranceEmbeddingSimilarity:
+		if cfg.embedder == nil {
+			return expected == actual, "exact (no embedder configured)"
+		}
+		vectors, _, err := cfg.embedder.Embed(cfg.ctx, []string{expected, actual})
+		if err != nil || len(vectors) != 2 {
+			return expected == actual, "exact (embedder failed)"
+		}
+		similarity := cosineSimilarity(vectors[0], vectors[1])
+		detail := fmt.Sprintf("embedding similarity %.4f, threshold %.4f", similarity, cfg.similarityThreshold)
+		return similarity >= cfg.similarityThreshold, detail
+	default:
+		return expected == actual, "exact"
+	}
+}
+
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func recordSnapshotMismatch(ctx context.Context, name, expected, actual string) {
+	if _, ok := langwatch.TraceFromContext(ctx); !ok {
+		return
+	}
+	_, span := langwatch.StartSpan(ctx, "snapshot:"+name)
+	span.RecordExpectedOutput(langwatch.NewTextValue(expected))
+	span.RecordOutput(langwatch.NewTextValue(actual))
+	span.SetMetadata(metadataSnapshotMismatch, "true")
+	span.End()
+}
@@ -0,0 +1,102 @@
+package langwatchtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestSnapshotWritesAndMatchesGoldenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// First call: no golden file yet, so it's written and the test passes.
+	inner := &testing.T{}
+	Snapshot(inner, "greeting", "hello world", WithSnapshotDir(dir))
+	if inner.Failed() {
+		t.Fatal("expected first Snapshot call to pass and create the golden file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "greeting.snap")); err != nil {
+		t.Fatalf("expected golden file to be created: %v", err)
+	}
+
+	// Second call: golden file exists and matches, so it passes too.
+	inner2 := &testing.T{}
+	Snapshot(inner2, "greeting", "hello world", WithSnapshotDir(dir))
+	if inner2.Failed() {
+		t.Fatal("expected matching Snapshot call to pass")
+	}
+}
+
+func TestSnapshotFailsOnMismatchAndRecordsSpan(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "greeting.snap"), []byte("hello world"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	inner := &testing.T{}
+	Snapshot(inner, "greeting", "goodbye world", WithSnapshotDir(dir), WithContext(ctx))
+	if !inner.Failed() {
+		t.Fatal("expected mismatched Snapshot call to fail")
+	}
+
+	spans := trace.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 mismatch span to be recorded, got %d", len(spans))
+	}
+}
+
+func TestSnapshotNormalizedWhitespaceTolerance(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "greeting.snap"), []byte("hello   world"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	inner := &testing.T{}
+	Snapshot(inner, "greeting", "hello world", WithSnapshotDir(dir), WithNormalizedWhitespace())
+	if inner.Failed() {
+		t.Fatal("expected normalized-whitespace comparison to pass")
+	}
+}
+
+type fakeEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = e.vectors[text]
+	}
+	return out, langwatch.Metrics{}, nil
+}
+
+func TestSnapshotEmbeddingSimilarityTolerance(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "greeting.snap"), []byte("hi there"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"hi there":   {1, 0},
+		"hey there!": {1, 0},
+	}}
+
+	inner := &testing.T{}
+	Snapshot(inner, "greeting", "hey there!", WithSnapshotDir(dir), WithEmbeddingSimilarity(embedder, 0.9))
+	if inner.Failed() {
+		t.Fatal("expected embedding-similarity comparison to pass")
+	}
+}
@@ -0,0 +1,179 @@
+// Package loadtest replays a fixed dataset of prompts against an
+// instrumented client at a configurable rate, so capacity planning has
+// real latency/error/token distributions - and real traces attached to
+// them in LangWatch - instead of a synthetic number pulled from a
+// spreadsheet.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("loadtest")
+}
+
+// metadataExperiment tags every span a Run produces with the experiment
+// name, so its traces group separately from regular production traffic
+// sharing the same client code.
+const metadataExperiment = "langwatch.experiment.name"
+
+// Case is a single dataset entry replayed during a Run.
+type Case struct {
+	// Name identifies the case in per-call spans; defaults to "case" if empty.
+	Name  string
+	Input string
+}
+
+// Client is the instrumented call under test. It receives a span-bearing
+// ctx and the case's input, and returns the output text plus the metrics
+// recorded for the call, or an error.
+type Client func(ctx context.Context, input string) (output string, metrics langwatch.Metrics, err error)
+
+// Config configures a Run.
+type Config struct {
+	// Experiment names this load test run. Required.
+	Experiment string
+	// Exporter ships the run's trace to LangWatch. Required.
+	Exporter langwatch.Exporter
+	// RPS is the target request rate. Required, must be > 0.
+	RPS float64
+	// Duration bounds how long Run replays the dataset for, looping over
+	// it as many times as needed to fill the duration. Required, must be
+	// > 0.
+	Duration time.Duration
+}
+
+// Result is a single case's outcome.
+type Result struct {
+	Case    Case
+	Latency time.Duration
+	Err     error
+	Metrics langwatch.Metrics
+}
+
+// Report summarizes a Run's Results.
+type Report struct {
+	Total                          int
+	Errors                         int
+	P50, P95, P99                  time.Duration
+	PromptTokens, CompletionTokens int
+}
+
+// Run replays dataset against client at cfg's target rate for cfg.Duration,
+// recording one LLM span per call - tagged with cfg.Experiment - onto a
+// single trace, then exports that trace via cfg.Exporter so the run's
+// calls are visible in LangWatch alongside the Report Run returns.
+func Run(ctx context.Context, cfg Config, dataset []Case, client Client) (Report, error) {
+	if cfg.RPS <= 0 {
+		return Report{}, fmt.Errorf("loadtest: RPS must be > 0")
+	}
+	if cfg.Duration <= 0 {
+		return Report{}, fmt.Errorf("loadtest: Duration must be > 0")
+	}
+	if len(dataset) == 0 {
+		return Report{}, fmt.Errorf("loadtest: dataset must not be empty")
+	}
+
+	ctx, trace := langwatch.NewTrace(ctx)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RPS))
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(cfg.Duration)
+	var mu sync.Mutex
+	var results []Result
+	var wg sync.WaitGroup
+
+loop:
+	for i := 0; time.Now().Before(deadline); i++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+		}
+		c := dataset[i%len(dataset)]
+		wg.Add(1)
+		go func(c Case) {
+			defer wg.Done()
+			result := runCase(ctx, cfg, c, client)
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	report := summarize(results)
+	if err := cfg.Exporter.Export(ctx, trace); err != nil {
+		return report, fmt.Errorf("loadtest: export: %w", err)
+	}
+	return report, nil
+}
+
+func runCase(ctx context.Context, cfg Config, c Case, client Client) Result {
+	start := time.Now()
+	ctx, span := langwatch.StartSpan(ctx, "loadtest:"+caseName(c), langwatch.WithType(langwatch.SpanTypeLLM))
+	defer span.End()
+	span.SetMetadata(metadataExperiment, cfg.Experiment)
+	span.RecordInput(langwatch.NewTextValue(c.Input))
+
+	output, metrics, err := client(ctx, c.Input)
+	latency := time.Since(start)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return Result{Case: c, Latency: latency, Err: err}
+	}
+	span.RecordOutput(langwatch.NewTextValue(output))
+	span.RecordMetrics(metrics)
+	return Result{Case: c, Latency: latency, Metrics: metrics}
+}
+
+func caseName(c Case) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return "case"
+}
+
+func summarize(results []Result) Report {
+	report := Report{Total: len(results)}
+	latencies := make([]time.Duration, 0, len(results))
+	for _, res := range results {
+		if res.Err != nil {
+			report.Errors++
+		}
+		latencies = append(latencies, res.Latency)
+		if res.Metrics.PromptTokens != nil {
+			report.PromptTokens += *res.Metrics.PromptTokens
+		}
+		if res.Metrics.CompletionTokens != nil {
+			report.CompletionTokens += *res.Metrics.CompletionTokens
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.P50 = percentile(latencies, 0.50)
+	report.P95 = percentile(latencies, 0.95)
+	report.P99 = percentile(latencies, 0.99)
+	return report
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,96 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type fakeExporter struct {
+	mu    sync.Mutex
+	trace *langwatch.Trace
+}
+
+func (f *fakeExporter) Export(ctx context.Context, trace *langwatch.Trace) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.trace = trace
+	return nil
+}
+
+func TestRunReportsLatencyAndTokenTotals(t *testing.T) {
+	dataset := []Case{{Name: "greeting", Input: "hi"}, {Name: "farewell", Input: "bye"}}
+	client := func(ctx context.Context, input string) (string, langwatch.Metrics, error) {
+		promptTokens, completionTokens := 3, 1
+		return "ok: " + input, langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens}, nil
+	}
+
+	exporter := &fakeExporter{}
+	report, err := Run(context.Background(), Config{
+		Experiment: "capacity-check",
+		Exporter:   exporter,
+		RPS:        50,
+		Duration:   100 * time.Millisecond,
+	}, dataset, client)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Total == 0 {
+		t.Fatal("expected at least one call to have run")
+	}
+	if report.Errors != 0 {
+		t.Fatalf("expected no errors, got %d", report.Errors)
+	}
+	if report.PromptTokens == 0 || report.CompletionTokens == 0 {
+		t.Fatalf("expected token totals to accumulate, got %+v", report)
+	}
+
+	exporter.mu.Lock()
+	trace := exporter.trace
+	exporter.mu.Unlock()
+	if trace == nil {
+		t.Fatal("expected the run's trace to be exported")
+	}
+	if len(trace.Spans()) != report.Total {
+		t.Fatalf("expected %d spans, got %d", report.Total, len(trace.Spans()))
+	}
+}
+
+func TestRunCountsClientErrors(t *testing.T) {
+	dataset := []Case{{Input: "fail"}}
+	client := func(ctx context.Context, input string) (string, langwatch.Metrics, error) {
+		return "", langwatch.Metrics{}, fmt.Errorf("boom")
+	}
+
+	exporter := &fakeExporter{}
+	report, err := Run(context.Background(), Config{
+		Experiment: "error-check",
+		Exporter:   exporter,
+		RPS:        50,
+		Duration:   50 * time.Millisecond,
+	}, dataset, client)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Errors != report.Total {
+		t.Fatalf("expected every call to error, got %+v", report)
+	}
+}
+
+func TestRunRejectsInvalidConfig(t *testing.T) {
+	exporter := &fakeExporter{}
+	client := func(ctx context.Context, input string) (string, langwatch.Metrics, error) {
+		return "", langwatch.Metrics{}, nil
+	}
+
+	if _, err := Run(context.Background(), Config{Experiment: "x", Exporter: exporter, RPS: 0, Duration: time.Second}, []Case{{Input: "a"}}, client); err == nil {
+		t.Fatal("expected an error for RPS <= 0")
+	}
+	if _, err := Run(context.Background(), Config{Experiment: "x", Exporter: exporter, RPS: 1, Duration: time.Second}, nil, client); err == nil {
+		t.Fatal("expected an error for an empty dataset")
+	}
+}
@@ -0,0 +1,129 @@
+// Package localinference instruments on-device LLM inference - go-llama.cpp
+// cgo bindings, a llamafile HTTP server, or any other local runtime - under
+// the same gen_ai span shape used for cloud providers, so local and cloud
+// inference are comparable in LangWatch.
+//
+// This SDK otherwise has a single external dependency (fsnotify) and no
+// native/cgo code; go-llama.cpp requires cgo and a compiled copy of
+// llama.cpp, which is far heavier than anything else this module pulls in.
+// Rather than import it directly, Client is a small interface any binding -
+// go-llama.cpp, a llamafile HTTP client, or a hand-rolled wrapper - can
+// satisfy, so this package never compiles native code itself.
+package localinference
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("localinference")
+}
+
+// Metadata keys for fields with no dedicated wire field on langwatch.Params
+// or langwatch.Metrics, which mirror cloud-provider request/response shapes.
+const (
+	metadataModelHash       = "langwatch.local_inference.model_hash"
+	metadataContextSize     = "langwatch.local_inference.context_size"
+	metadataTopP            = "langwatch.local_inference.top_p"
+	metadataTopK            = "langwatch.local_inference.top_k"
+	metadataTokensPerSecond = "langwatch.local_inference.tokens_per_second"
+)
+
+// Sampling carries the sampling parameters used for a generation call.
+type Sampling struct {
+	Temperature float64
+	TopP        float64
+	TopK        int
+}
+
+// Params describes a local generation call.
+type Params struct {
+	// ModelPath is the local model file's path. It's hashed to identify
+	// the exact weights in use, since a model name alone doesn't
+	// distinguish quantizations or fine-tunes the way a cloud model
+	// identifier does.
+	ModelPath   string
+	ContextSize int
+	Sampling    Sampling
+}
+
+// Result is what a Client call produced.
+type Result struct {
+	Output          string
+	TokensGenerated int
+	TokensPerSecond float64
+}
+
+// Client runs a single local generation call. go-llama.cpp's binding and an
+// OpenAI-compatible llamafile HTTP client both satisfy this shape already,
+// or can be adapted to it with a one-line wrapper.
+type Client interface {
+	Generate(ctx context.Context, prompt string, params Params) (Result, error)
+}
+
+// Generate runs prompt through client, recording an LLM span with the same
+// input/output/vendor fields cloud-provider instrumentation uses, plus the
+// model file hash, context size, sampling params and tokens/sec throughput
+// as metadata, so local and cloud inference show up comparably in
+// LangWatch. vendor identifies the local backend, e.g. "llama.cpp" or
+// "llamafile".
+func Generate(ctx context.Context, vendor string, client Client, prompt string, params Params) (Result, error) {
+	ctx, span := langwatch.StartSpan(ctx, "localinference.generate",
+		langwatch.WithType(langwatch.SpanTypeLLM),
+		langwatch.WithModel(vendor, filepath.Base(params.ModelPath)),
+	)
+	defer span.End()
+
+	span.RecordInput(langwatch.NewTextValue(prompt))
+	span.SetMetadata(metadataContextSize, strconv.Itoa(params.ContextSize))
+	if hash, err := hashFile(params.ModelPath); err == nil {
+		span.SetMetadata(metadataModelHash, hash)
+	}
+	if params.Sampling.TopP != 0 {
+		span.SetMetadata(metadataTopP, strconv.FormatFloat(params.Sampling.TopP, 'f', -1, 64))
+	}
+	if params.Sampling.TopK != 0 {
+		span.SetMetadata(metadataTopK, strconv.Itoa(params.Sampling.TopK))
+	}
+	temperature := params.Sampling.Temperature
+	span.RecordParams(langwatch.Params{Temperature: &temperature})
+
+	result, err := client.Generate(ctx, prompt, params)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return Result{}, err
+	}
+
+	span.RecordOutput(langwatch.NewTextValue(result.Output))
+	completionTokens := result.TokensGenerated
+	span.RecordMetrics(langwatch.Metrics{CompletionTokens: &completionTokens})
+	span.SetMetadata(metadataTokensPerSecond, strconv.FormatFloat(result.TokensPerSecond, 'f', 2, 64))
+
+	return result, nil
+}
+
+func hashFile(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("localinference: no model path")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
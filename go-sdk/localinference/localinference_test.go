@@ -0,0 +1,95 @@
+package localinference
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type fakeClient struct {
+	result Result
+	err    error
+}
+
+func (f *fakeClient) Generate(ctx context.Context, prompt string, params Params) (Result, error) {
+	return f.result, f.err
+}
+
+func spanRecord(t *testing.T, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	return matches[0].Span
+}
+
+func TestGenerateRecordsModelHashAndThroughput(t *testing.T) {
+	modelFile := filepath.Join(t.TempDir(), "model.gguf")
+	if err := os.WriteFile(modelFile, []byte("fake gguf weights"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &fakeClient{result: Result{Output: "hi there", TokensGenerated: 3, TokensPerSecond: 42.5}}
+
+	result, err := Generate(ctx, "llama.cpp", client, "hello", Params{
+		ModelPath:   modelFile,
+		ContextSize: 4096,
+		Sampling:    Sampling{Temperature: 0.7, TopP: 0.9, TopK: 40},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if result.Output != "hi there" {
+		t.Fatalf("Output = %q, want %q", result.Output, "hi there")
+	}
+
+	record := spanRecord(t, trace)
+	if record.Vendor != "llama.cpp" || record.Model != "model.gguf" {
+		t.Fatalf("expected vendor/model to be recorded, got %q/%q", record.Vendor, record.Model)
+	}
+	if record.Metadata[metadataModelHash] == "" {
+		t.Fatal("expected the model file hash to be recorded")
+	}
+	if record.Metadata[metadataContextSize] != "4096" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataContextSize, record.Metadata[metadataContextSize], "4096")
+	}
+	if record.Metadata[metadataTopP] != "0.9" || record.Metadata[metadataTopK] != "40" {
+		t.Fatalf("expected sampling params to be recorded, got %+v", record.Metadata)
+	}
+	if record.Metadata[metadataTokensPerSecond] != "42.50" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataTokensPerSecond, record.Metadata[metadataTokensPerSecond], "42.50")
+	}
+	if record.Outputs[0].Value != "hi there" {
+		t.Fatalf("expected output to be recorded, got %+v", record.Outputs)
+	}
+}
+
+func TestGenerateRecordsClientError(t *testing.T) {
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &fakeClient{err: errors.New("model failed to load")}
+
+	if _, err := Generate(ctx, "llama.cpp", client, "hello", Params{}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	record := spanRecord(t, trace)
+	if record.Error == nil || record.Error.Message != "model failed to load" {
+		t.Fatalf("expected the client error to be recorded, got %+v", record.Error)
+	}
+}
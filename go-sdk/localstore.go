@@ -0,0 +1,155 @@
+package langwatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// LocalStoreRecord is one span persisted by LocalStore, together with the
+// trace-level attributes it was exported with, so Query can filter without
+// reassembling whole traces.
+type LocalStoreRecord struct {
+	TraceID    string     `json:"trace_id"`
+	ThreadID   string     `json:"thread_id,omitempty"`
+	UserID     string     `json:"user_id,omitempty"`
+	CustomerID string     `json:"customer_id,omitempty"`
+	Labels     []string   `json:"labels,omitempty"`
+	Span       SpanRecord `json:"span"`
+}
+
+// LocalStoreFilters narrows a LocalStore.Query call. A zero-valued field
+// matches anything.
+type LocalStoreFilters struct {
+	TraceID  string
+	ThreadID string
+	UserID   string
+	Type     SpanType
+}
+
+// LocalStore is an Exporter that appends every span it receives to a local
+// append-only NDJSON file, for air-gapped development: traces recorded
+// offline can be inspected with Query and later bulk-uploaded to a real
+// LangWatch project with Backfill.
+//
+// This uses a JSON-lines file rather than an embedded SQL engine: a real
+// SQLite driver (cgo or pure Go) would add a heavyweight dependency to
+// every consumer of this module just for an offline-development
+// convenience, which this SDK avoids elsewhere (see the temporal
+// submodule split). Query does a linear scan of the file, which is fine at
+// local-development volumes but isn't meant to replace LangWatch itself as
+// a query backend.
+type LocalStore struct {
+	mu           sync.Mutex
+	path         string
+	contentStore ContentStore
+}
+
+// LocalStoreOption configures a LocalStore built with OpenLocalStore.
+type LocalStoreOption func(*LocalStore)
+
+// WithLocalStoreContentResolution makes Query transparently resolve any
+// content_ref span content (see WithContentStore) against store before
+// returning results, so callers see the original text instead of a bare
+// hash.
+func WithLocalStoreContentResolution(store ContentStore) LocalStoreOption {
+	return func(s *LocalStore) { s.contentStore = store }
+}
+
+// OpenLocalStore opens (creating if necessary) the trace store file at path.
+func OpenLocalStore(path string, opts ...LocalStoreOption) (*LocalStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("langwatch: open local store %s: %w", path, err)
+	}
+	f.Close()
+	s := &LocalStore{path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+var _ Exporter = (*LocalStore)(nil)
+
+// Export appends every span in trace to the store file, one JSON object per
+// line.
+func (s *LocalStore) Export(ctx context.Context, trace *Trace) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("langwatch: open local store %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, span := range trace.Spans() {
+		rec := LocalStoreRecord{
+			TraceID:    trace.ID(),
+			ThreadID:   ThreadID(ctx),
+			UserID:     UserID(ctx),
+			CustomerID: CustomerID(ctx),
+			Labels:     Labels(ctx),
+			Span:       span.toRecord(),
+		}
+		body, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("langwatch: marshal local store record: %w", err)
+		}
+		if _, err := w.Write(append(body, '\n')); err != nil {
+			return fmt.Errorf("langwatch: write local store record: %w", err)
+		}
+	}
+	return w.Flush()
+}
+
+// Query scans the store file and returns every record matching filters.
+func (s *LocalStore) Query(filters LocalStoreFilters) ([]LocalStoreRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("langwatch: open local store %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	var matches []LocalStoreRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var rec LocalStoreRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("langwatch: parse local store record: %w", err)
+		}
+		if matchesLocalStoreFilters(rec, filters) {
+			ResolveContentRefs(context.Background(), s.contentStore, &rec.Span)
+			matches = append(matches, rec)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("langwatch: read local store %s: %w", s.path, err)
+	}
+	return matches, nil
+}
+
+func matchesLocalStoreFilters(rec LocalStoreRecord, f LocalStoreFilters) bool {
+	if f.TraceID != "" && rec.TraceID != f.TraceID {
+		return false
+	}
+	if f.ThreadID != "" && rec.ThreadID != f.ThreadID {
+		return false
+	}
+	if f.UserID != "" && rec.UserID != f.UserID {
+		return false
+	}
+	if f.Type != "" && rec.Span.Type != f.Type {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package langwatch
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStoreExportAndQueryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.ndjson")
+	store, err := OpenLocalStore(path)
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+
+	ctx := WithUserID(context.Background(), "user-1")
+	ctx, trace := NewTrace(ctx)
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	span.RecordInput(NewTextValue("hi"))
+	span.End()
+
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	matches, err := store.Query(LocalStoreFilters{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("matches = %d, want 1", len(matches))
+	}
+	if matches[0].TraceID != trace.ID() {
+		t.Fatalf("TraceID = %q, want %q", matches[0].TraceID, trace.ID())
+	}
+	if matches[0].Span.Type != SpanTypeLLM {
+		t.Fatalf("Span.Type = %q, want %q", matches[0].Span.Type, SpanTypeLLM)
+	}
+}
+
+func TestLocalStoreQueryFiltersByTraceID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.ndjson")
+	store, err := OpenLocalStore(path)
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx, trace := NewTrace(context.Background())
+		_, span := StartSpan(ctx, "chat")
+		span.End()
+		if err := store.Export(ctx, trace); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+	}
+
+	all, err := store.Query(LocalStoreFilters{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("len(all) = %d, want 2", len(all))
+	}
+
+	filtered, err := store.Query(LocalStoreFilters{TraceID: all[0].TraceID})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].TraceID != all[0].TraceID {
+		t.Fatalf("filtered = %v, want exactly the record for %q", filtered, all[0].TraceID)
+	}
+}
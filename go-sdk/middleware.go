@@ -0,0 +1,43 @@
+package langwatch
+
+import "net/http"
+
+// Middleware starts a new trace for every incoming HTTP request, wraps the
+// handler chain in a SpanKindServer span tagged with the caller's address,
+// and exports the trace once the chain returns. Thread/user IDs are picked
+// up from the X-LangWatch-Thread-Id / X-LangWatch-User-Id headers when
+// present.
+//
+// opts are applied to the request span after its defaults, so a service
+// that isn't acting as a plain inbound handler - e.g. one gateway hop in a
+// chain of them - can override the kind with WithKind or add its own peer
+// attributes.
+func Middleware(exporter Exporter, next http.Handler, opts ...SpanOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Disabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, trace := NewTrace(r.Context())
+
+		if !CaptureEnabled(ctx) {
+			publish(ctx, Event{Type: EventCaptureSkipped, Reason: "capture disabled"})
+		}
+		if threadID := r.Header.Get("X-LangWatch-Thread-Id"); threadID != "" {
+			ctx = WithThreadID(ctx, threadID)
+		}
+		if userID := r.Header.Get("X-LangWatch-User-Id"); userID != "" {
+			ctx = WithUserID(ctx, userID)
+		}
+
+		spanOpts := append([]SpanOption{WithKind(SpanKindServer), WithClientAddress(r.RemoteAddr)}, opts...)
+		ctx, span := StartSpan(ctx, r.Method+" "+r.URL.Path, spanOpts...)
+		next.ServeHTTP(w, r.WithContext(ctx))
+		span.End()
+
+		if err := exporter.Export(ctx, trace); err != nil {
+			debugLog("middleware: export failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package langwatch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type capturingExporter struct {
+	mu    sync.Mutex
+	trace *Trace
+}
+
+func (c *capturingExporter) Export(ctx context.Context, trace *Trace) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trace = trace
+	return nil
+}
+
+func TestMiddlewareRecordsServerSpanWithClientAddress(t *testing.T) {
+	exporter := &capturingExporter{}
+	handler := Middleware(exporter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/chat", nil)
+	req.RemoteAddr = "203.0.113.5:54321"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	exporter.mu.Lock()
+	trace := exporter.trace
+	exporter.mu.Unlock()
+	if trace == nil {
+		t.Fatal("expected a trace to be exported")
+	}
+
+	spans := trace.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	record := spans[0].toRecord()
+	if record.Metadata[metadataSpanKind] != string(SpanKindServer) {
+		t.Fatalf("expected span kind %q, got %+v", SpanKindServer, record.Metadata)
+	}
+	if record.Metadata[metadataClientAddress] != "203.0.113.5:54321" {
+		t.Fatalf("expected client.address metadata, got %+v", record.Metadata)
+	}
+}
+
+func TestMiddlewareOptionsOverrideDefaultKind(t *testing.T) {
+	exporter := &capturingExporter{}
+	handler := Middleware(exporter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), WithKind(SpanKindClient))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	exporter.mu.Lock()
+	trace := exporter.trace
+	exporter.mu.Unlock()
+
+	record := trace.Spans()[0].toRecord()
+	if record.Metadata[metadataSpanKind] != string(SpanKindClient) {
+		t.Fatalf("expected an overridden span kind %q, got %+v", SpanKindClient, record.Metadata)
+	}
+}
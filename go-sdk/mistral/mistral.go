@@ -0,0 +1,264 @@
+// Package mistral instruments outbound calls to Mistral's La Plateforme
+// HTTP API - chat completions, FIM (fill-in-the-middle) completions, and
+// embeddings - including streamed chat/FIM responses, so Mistral usage
+// shows up in LangWatch next to other providers.
+//
+// Like ollama and cohere, it wraps an http.RoundTripper instead of
+// providing its own client: this repo doesn't vendor a Mistral Go client
+// (GOPROXY is disabled in this environment), and a RoundTripper works
+// with whichever HTTP client a caller already has, generated or
+// hand-rolled.
+//
+// Content capture respects langwatch.CaptureEnabled the same way
+// langwatchproxy does: message content is only recorded as span input/
+// output when capture is enabled, so the SDK's existing capture-skipped
+// event (published internally by the core package's own Record* helpers)
+// stays the single source of truth for "was this call's content logged".
+package mistral
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("mistral")
+}
+
+type endpoint string
+
+const (
+	endpointChat       endpoint = "chat"
+	endpointFIM        endpoint = "fim"
+	endpointEmbeddings endpoint = "embeddings"
+)
+
+func endpointKind(path string) (endpoint, bool) {
+	switch path {
+	case "/v1/chat/completions":
+		return endpointChat, true
+	case "/v1/fim/completions":
+		return endpointFIM, true
+	case "/v1/embeddings":
+		return endpointEmbeddings, true
+	default:
+		return "", false
+	}
+}
+
+// RoundTripper instruments requests to the Mistral API paths this package
+// understands with a LangWatch span, forwarding every other request to
+// next untouched. Build one with Wrap.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Wrap returns a RoundTripper instrumenting Mistral API calls and
+// forwarding to next. A nil next forwards to http.DefaultTransport.
+func Wrap(next http.RoundTripper) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RoundTripper{next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind, ok := endpointKind(req.URL.Path)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	reqBody, err := drain(&req.Body)
+	if err != nil {
+		return rt.next.RoundTrip(req)
+	}
+	model, streaming, input := describeRequest(kind, reqBody)
+
+	ctx, span := langwatch.StartSpan(req.Context(), "mistral."+string(kind), langwatch.WithType(langwatch.SpanTypeLLM), langwatch.WithModel("mistral", model))
+	defer span.End()
+	if input != nil && langwatch.CaptureEnabled(ctx) {
+		span.RecordInput(*input)
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, err
+	}
+
+	respBody, err := drain(&resp.Body)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, nil
+	}
+	if resp.StatusCode >= 400 {
+		span.RecordError(langwatch.ErrorCapture{Message: string(respBody)})
+		return resp, nil
+	}
+	recordResponse(span, kind, streaming, langwatch.CaptureEnabled(ctx), respBody)
+
+	return resp, nil
+}
+
+// drain reads *body fully and replaces it with a fresh reader over the same
+// bytes, so the caller (RoundTrip's caller, or the real transport) still
+// sees a complete, unread body after this package has inspected it.
+func drain(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+type chatOrFIMRequest struct {
+	Model    string                  `json:"model"`
+	Stream   bool                    `json:"stream"`
+	Messages []langwatch.ChatMessage `json:"messages"`
+	Prompt   string                  `json:"prompt"`
+	Suffix   string                  `json:"suffix"`
+}
+
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// describeRequest extracts the model name, whether streaming was
+// requested, and a TypedValue worth recording as the span's input from
+// body, whose shape depends on kind.
+func describeRequest(kind endpoint, body []byte) (model string, streaming bool, input *langwatch.TypedValue) {
+	switch kind {
+	case endpointChat:
+		var req chatOrFIMRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewChatMessagesValue(req.Messages)
+			return req.Model, req.Stream, &value
+		}
+	case endpointFIM:
+		var req chatOrFIMRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewJSONValue(map[string]interface{}{"prompt": req.Prompt, "suffix": req.Suffix})
+			return req.Model, req.Stream, &value
+		}
+	case endpointEmbeddings:
+		var req embeddingsRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewJSONValue(req.Input)
+			return req.Model, false, &value
+		}
+	}
+	return "", false, nil
+}
+
+// chatOrFIMResponse covers both the non-streaming chat/FIM response shape
+// and, since Mistral's streamed chunks carry the same "choices[].delta"
+// shape as a non-streamed response carries "choices[].message", a single
+// streamed chunk.
+type chatOrFIMResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+	} `json:"usage"`
+}
+
+// recordResponse extracts attributes from body - either a plain JSON
+// response, or (when streaming is true) a stream of Server-Sent Events
+// terminated by a "data: [DONE]" line - and records them on span. Usage
+// metrics are always recorded; output text is only recorded when
+// captureEnabled, matching RoundTrip's treatment of request input.
+func recordResponse(span *langwatch.Span, kind endpoint, streaming, captureEnabled bool, body []byte) {
+	if kind == endpointEmbeddings {
+		var resp embeddingsResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return
+		}
+		if captureEnabled {
+			dimensions := 0
+			if len(resp.Data) > 0 {
+				dimensions = len(resp.Data[0].Embedding)
+			}
+			span.RecordOutput(langwatch.NewJSONValue(map[string]interface{}{
+				"count":      len(resp.Data),
+				"dimensions": dimensions,
+			}))
+		}
+		promptTokens := resp.Usage.PromptTokens
+		span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens})
+		return
+	}
+
+	if !streaming {
+		var resp chatOrFIMResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return
+		}
+		if captureEnabled {
+			var output string
+			if len(resp.Choices) > 0 {
+				output = resp.Choices[0].Message.Content
+			}
+			span.RecordOutput(langwatch.NewTextValue(output))
+		}
+		promptTokens, completionTokens := resp.Usage.PromptTokens, resp.Usage.CompletionTokens
+		span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+		return
+	}
+
+	var text strings.Builder
+	var promptTokens, completionTokens int
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data := strings.TrimPrefix(line, "data:")
+		data = strings.TrimSpace(data)
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+		var chunk chatOrFIMResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if len(chunk.Choices) > 0 {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 {
+			promptTokens, completionTokens = chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens
+		}
+	}
+	if captureEnabled {
+		span.RecordOutput(langwatch.NewTextValue(text.String()))
+	}
+	span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+}
@@ -0,0 +1,159 @@
+package mistral
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func recordedSpan(t *testing.T, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(t.TempDir() + "/traces.jsonl")
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected exactly 1 recorded span, got %d", len(matches))
+	}
+	return matches[0].Span
+}
+
+func TestRoundTripRecordsNonStreamingChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"hello"}}],"usage":{"prompt_tokens":4,"completion_tokens":1}}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/chat/completions", strings.NewReader(`{"model":"mistral-large-latest","messages":[{"role":"user","content":"hi"}]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "mistral-large-latest" {
+		t.Fatalf("Model = %q, want %q", record.Model, "mistral-large-latest")
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "hello" {
+		t.Fatalf("unexpected outputs: %+v", record.Outputs)
+	}
+	if record.Metrics == nil || record.Metrics.PromptTokens == nil || *record.Metrics.PromptTokens != 4 {
+		t.Fatalf("unexpected metrics: %+v", record.Metrics)
+	}
+}
+
+func TestRoundTripAccumulatesStreamingChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "data: "+`{"choices":[{"delta":{"content":"Hel"}}]}`+"\n\n")
+		io.WriteString(w, "data: "+`{"choices":[{"delta":{"content":"lo"}}]}`+"\n\n")
+		io.WriteString(w, "data: "+`{"choices":[{"delta":{}}],"usage":{"prompt_tokens":10,"completion_tokens":4}}`+"\n\n")
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/chat/completions", strings.NewReader(`{"model":"mistral-small-latest","stream":true,"messages":[{"role":"user","content":"hi"}]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "Hello" {
+		t.Fatalf("expected accumulated output %q, got %+v", "Hello", record.Outputs)
+	}
+	if record.Metrics == nil || record.Metrics.CompletionTokens == nil || *record.Metrics.CompletionTokens != 4 {
+		t.Fatalf("unexpected metrics: %+v", record.Metrics)
+	}
+}
+
+func TestRoundTripRecordsFIMCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/fim/completions" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"def foo():"}}],"usage":{"prompt_tokens":6,"completion_tokens":3}}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/fim/completions", strings.NewReader(`{"model":"codestral-latest","prompt":"def ","suffix":""}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "codestral-latest" {
+		t.Fatalf("Model = %q, want %q", record.Model, "codestral-latest")
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "def foo():" {
+		t.Fatalf("unexpected outputs: %+v", record.Outputs)
+	}
+}
+
+func TestRoundTripRecordsEmbeddingsDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":[{"embedding":[0.1,0.2,0.3]}],"usage":{"prompt_tokens":2}}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/embeddings", strings.NewReader(`{"model":"mistral-embed","input":["hi"]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "mistral-embed" {
+		t.Fatalf("Model = %q, want %q", record.Model, "mistral-embed")
+	}
+	if len(record.Outputs) != 1 {
+		t.Fatalf("expected 1 output, got %+v", record.Outputs)
+	}
+}
+
+func TestRoundTripPassesThroughUnrelatedPaths(t *testing.T) {
+	var hit bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	resp, err := client.Get(server.URL + "/v1/models")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if !hit {
+		t.Fatal("expected the request to reach the server")
+	}
+}
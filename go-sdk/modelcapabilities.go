@@ -0,0 +1,128 @@
+package langwatch
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// metadataContextUtilization mirrors the OpenTelemetry GenAI semantic
+// conventions' attribute name (gen_ai.request.context_utilization) rather
+// than this SDK's usual langwatch.* prefix, so it lines up with other
+// OTel-based GenAI instrumentation a LangWatch user might already have.
+const metadataContextUtilization = "gen_ai.request.context_utilization"
+
+// ModelCapabilities describes what a model supports, for validating a
+// request against it before ever sending it upstream.
+type ModelCapabilities struct {
+	// MaxContextTokens is the model's context window. Zero means unknown
+	// and disables context-size validation for the model.
+	MaxContextTokens int
+	SupportsTools    bool
+	SupportsVision   bool
+	SupportsJSONMode bool
+}
+
+func capabilityKey(vendor, model string) string { return vendor + "/" + model }
+
+// capabilityRegistry is the process-wide set of known model capabilities,
+// seeded with a small built-in set and extensible via
+// RegisterModelCapabilities for fine-tunes, new releases, or self-hosted
+// models this SDK doesn't ship a default for.
+var capabilityRegistry = struct {
+	mu   sync.RWMutex
+	byID map[string]ModelCapabilities
+}{byID: builtinModelCapabilities()}
+
+func builtinModelCapabilities() map[string]ModelCapabilities {
+	return map[string]ModelCapabilities{
+		capabilityKey("openai", "gpt-4o"):                        {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsJSONMode: true},
+		capabilityKey("openai", "gpt-4o-mini"):                   {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsJSONMode: true},
+		capabilityKey("openai", "gpt-3.5-turbo"):                 {MaxContextTokens: 16385, SupportsTools: true, SupportsJSONMode: true},
+		capabilityKey("anthropic", "claude-3-5-sonnet-20241022"): {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true},
+		capabilityKey("anthropic", "claude-3-haiku-20240307"):    {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true},
+	}
+}
+
+// RegisterModelCapabilities registers or overrides the capabilities for
+// vendor/model, so ValidateRequest and Span.RecordContextUtilization can be
+// used with models not in the built-in registry.
+func RegisterModelCapabilities(vendor, model string, caps ModelCapabilities) {
+	capabilityRegistry.mu.Lock()
+	defer capabilityRegistry.mu.Unlock()
+	capabilityRegistry.byID[capabilityKey(vendor, model)] = caps
+}
+
+// LookupModelCapabilities returns the registered capabilities for
+// vendor/model, if any.
+func LookupModelCapabilities(vendor, model string) (ModelCapabilities, bool) {
+	capabilityRegistry.mu.RLock()
+	defer capabilityRegistry.mu.RUnlock()
+	caps, ok := capabilityRegistry.byID[capabilityKey(vendor, model)]
+	return caps, ok
+}
+
+// ValidationError explains why ValidateRequest rejected a request.
+type ValidationError struct {
+	Reason string
+}
+
+func (e *ValidationError) Error() string { return "langwatch: " + e.Reason }
+
+// RequestCheck describes what a caller is about to ask a model to do, for
+// ValidateRequest to check against the model's registered capabilities.
+type RequestCheck struct {
+	PromptTokens int
+	UseTools     bool
+	UseVision    bool
+	UseJSONMode  bool
+}
+
+// ValidateRequest checks check against vendor/model's registered
+// capabilities, returning a *ValidationError describing the first mismatch
+// found. Returns nil - nothing to validate against - when vendor/model
+// isn't registered, so an unrecognized model never blocks a request; it
+// only catches capability mismatches this SDK actually knows about.
+func ValidateRequest(vendor, model string, check RequestCheck) error {
+	caps, ok := LookupModelCapabilities(vendor, model)
+	if !ok {
+		return nil
+	}
+
+	if caps.MaxContextTokens > 0 && check.PromptTokens > caps.MaxContextTokens {
+		return &ValidationError{Reason: fmt.Sprintf("prompt of %d tokens exceeds %s/%s's max context of %d tokens", check.PromptTokens, vendor, model, caps.MaxContextTokens)}
+	}
+	if check.UseTools && !caps.SupportsTools {
+		return &ValidationError{Reason: fmt.Sprintf("%s/%s does not support tool calls", vendor, model)}
+	}
+	if check.UseVision && !caps.SupportsVision {
+		return &ValidationError{Reason: fmt.Sprintf("%s/%s does not support vision input", vendor, model)}
+	}
+	if check.UseJSONMode && !caps.SupportsJSONMode {
+		return &ValidationError{Reason: fmt.Sprintf("%s/%s does not support JSON mode", vendor, model)}
+	}
+	return nil
+}
+
+// RecordContextUtilization records what fraction of vendor/model's max
+// context window promptTokens consumes, as gen_ai.request.context_utilization
+// metadata, so context-overflow failures can be diagnosed by how close a
+// request was running to the limit rather than just the raw token count.
+// A no-op when vendor/model isn't registered or has no MaxContextTokens.
+func (s *Span) RecordContextUtilization(vendor, model string, promptTokens int) {
+	if s.noop {
+		return
+	}
+	caps, ok := LookupModelCapabilities(vendor, model)
+	if !ok || caps.MaxContextTokens <= 0 {
+		return
+	}
+
+	utilization := float64(promptTokens) / float64(caps.MaxContextTokens)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[metadataContextUtilization] = strconv.FormatFloat(utilization, 'f', 4, 64)
+}
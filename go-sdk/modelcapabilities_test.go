@@ -0,0 +1,71 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateRequestRejectsOversizedPrompt(t *testing.T) {
+	err := ValidateRequest("openai", "gpt-3.5-turbo", RequestCheck{PromptTokens: 20000})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+}
+
+func TestValidateRequestRejectsUnsupportedCapability(t *testing.T) {
+	if err := ValidateRequest("anthropic", "claude-3-5-sonnet-20241022", RequestCheck{UseJSONMode: true}); err == nil {
+		t.Fatal("expected an error for JSON mode on a model that doesn't support it")
+	}
+	if err := ValidateRequest("openai", "gpt-3.5-turbo", RequestCheck{UseVision: true}); err == nil {
+		t.Fatal("expected an error for vision input on a model that doesn't support it")
+	}
+}
+
+func TestValidateRequestAllowsFittingRequest(t *testing.T) {
+	err := ValidateRequest("openai", "gpt-4o", RequestCheck{PromptTokens: 1000, UseTools: true, UseVision: true, UseJSONMode: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestValidateRequestAllowsUnregisteredModel(t *testing.T) {
+	err := ValidateRequest("openai", "some-future-model", RequestCheck{PromptTokens: 1_000_000})
+	if err != nil {
+		t.Fatalf("expected unregistered models to pass validation, got %v", err)
+	}
+}
+
+func TestRegisterModelCapabilitiesOverridesLookup(t *testing.T) {
+	RegisterModelCapabilities("selfhosted", "llama-70b", ModelCapabilities{MaxContextTokens: 8192, SupportsTools: true})
+
+	caps, ok := LookupModelCapabilities("selfhosted", "llama-70b")
+	if !ok || caps.MaxContextTokens != 8192 || !caps.SupportsTools {
+		t.Fatalf("unexpected capabilities: %+v, ok=%v", caps, ok)
+	}
+}
+
+func TestRecordContextUtilizationComputesFraction(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM), WithModel("openai", "gpt-4o"))
+
+	span.RecordContextUtilization("openai", "gpt-4o", 64000)
+	span.End()
+
+	if span.metadata[metadataContextUtilization] != "0.5000" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataContextUtilization, span.metadata[metadataContextUtilization], "0.5000")
+	}
+}
+
+func TestRecordContextUtilizationNoOpForUnregisteredModel(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	span.RecordContextUtilization("openai", "some-future-model", 1000)
+	span.End()
+
+	if _, ok := span.metadata[metadataContextUtilization]; ok {
+		t.Fatal("expected no context utilization metadata for an unregistered model")
+	}
+}
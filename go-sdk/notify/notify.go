@@ -0,0 +1,166 @@
+// Package notify posts formatted alerts (trace link, model, error, scores)
+// to Slack, Teams, or any other generic incoming-webhook endpoint whenever
+// a span fails a guardrail or records an error, so a Go service can wire
+// up on-call alerting without every team reimplementing formatting and
+// rate limiting.
+//
+// Notifier builds its message from a langwatch.SpanRecord rather than the
+// SDK's Subscribe/EventHandler bus: Event only carries a type and a short
+// reason string, not the trace ID, model, or metadata a useful alert
+// needs. Call Notify from wherever a guardrail failure or span error is
+// already observed (an Exporter wrapper, a batch processor hook, or an
+// EventHandler that looks the span back up) instead.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Notification is the alert-worthy summary of a span, ready to format and
+// send.
+type Notification struct {
+	TraceLink string
+	SpanName  string
+	Vendor    string
+	Model     string
+	Error     string
+	Metadata  map[string]string
+}
+
+// FromSpanRecord builds a Notification from a span, deriving traceLink from
+// the caller (this SDK has no dashboard URL builder, since Config carries
+// no project identifier to route with).
+func FromSpanRecord(record langwatch.SpanRecord, traceLink string) Notification {
+	n := Notification{
+		TraceLink: traceLink,
+		SpanName:  record.Name,
+		Vendor:    record.Vendor,
+		Model:     record.Model,
+		Metadata:  record.Metadata,
+	}
+	if record.Error != nil {
+		n.Error = record.Error.Message
+	}
+	return n
+}
+
+// Format renders the notification as plain text suitable for a Slack or
+// Teams incoming webhook's "text" field.
+func (n Notification) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "LangWatch alert: %s", n.SpanName)
+	if n.Model != "" {
+		fmt.Fprintf(&b, " (%s/%s)", n.Vendor, n.Model)
+	}
+	b.WriteString("\n")
+	if n.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", n.Error)
+	}
+	if len(n.Metadata) > 0 {
+		keys := make([]string, 0, len(n.Metadata))
+		for k := range n.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s: %s\n", k, n.Metadata[k])
+		}
+	}
+	if n.TraceLink != "" {
+		fmt.Fprintf(&b, "Trace: %s\n", n.TraceLink)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// NotifierOption configures a Notifier built with NewNotifier.
+type NotifierOption func(*Notifier)
+
+// WithHTTPClient overrides the http.Client used to post webhook requests.
+func WithHTTPClient(client *http.Client) NotifierOption {
+	return func(n *Notifier) { n.httpClient = client }
+}
+
+// WithMinInterval sets the minimum time between two notifications sent by
+// the same Notifier; anything closer together is silently dropped instead
+// of paging on-call for every failure in a burst. Defaults to 30 seconds.
+func WithMinInterval(d time.Duration) NotifierOption {
+	return func(n *Notifier) { n.minInterval = d }
+}
+
+// Notifier posts Notifications to a Slack/Teams/generic incoming webhook
+// URL, rate limited so a burst of failing spans results in one alert
+// instead of a flood.
+type Notifier struct {
+	webhookURL  string
+	httpClient  *http.Client
+	minInterval time.Duration
+
+	mu       sync.Mutex
+	lastSent time.Time
+}
+
+// NewNotifier creates a Notifier that posts to webhookURL.
+func NewNotifier(webhookURL string, opts ...NotifierOption) *Notifier {
+	n := &Notifier{
+		webhookURL:  webhookURL,
+		httpClient:  http.DefaultClient,
+		minInterval: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Notify posts note to the webhook, unless one was already sent within the
+// configured minimum interval, in which case it is silently dropped and
+// Notify returns nil.
+func (n *Notifier) Notify(ctx context.Context, note Notification) error {
+	if !n.allow() {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"text": note.Format()})
+	if err != nil {
+		return fmt.Errorf("notify: marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (n *Notifier) allow() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	if !n.lastSent.IsZero() && now.Sub(n.lastSent) < n.minInterval {
+		return false
+	}
+	n.lastSent = now
+	return true
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestNotifyPostsFormattedMessage(t *testing.T) {
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		received.Store(payload["text"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(server.URL)
+	note := FromSpanRecord(langwatch.SpanRecord{
+		Name:   "chat",
+		Vendor: "openai",
+		Model:  "gpt-4o",
+		Error:  &langwatch.ErrorCapture{Message: "guardrail tripped"},
+	}, "https://app.langwatch.ai/traces/trace-1")
+
+	if err := n.Notify(context.Background(), note); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	text, _ := received.Load().(string)
+	for _, want := range []string{"chat", "openai/gpt-4o", "guardrail tripped", "trace-1"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected message to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestNotifyRateLimitsBursts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(server.URL, WithMinInterval(time.Hour))
+	note := Notification{SpanName: "chat"}
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), note); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected 1 webhook call, got %d", got)
+	}
+}
+
+func TestNotifyReturnsErrorOnNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(server.URL)
+	if err := n.Notify(context.Background(), Notification{SpanName: "chat"}); err == nil {
+		t.Fatal("expected error for non-OK webhook response")
+	}
+}
@@ -0,0 +1,221 @@
+// Package ollama instruments outbound calls to a local or self-hosted
+// Ollama server's HTTP API (/api/chat, /api/generate, /api/embeddings), so
+// self-hosted model usage shows up in LangWatch next to hosted providers.
+//
+// Ollama's response bodies are newline-delimited JSON regardless of
+// whether the caller asked to stream: a non-streaming call is simply one
+// line with done=true, a streaming call is many lines ending the same way.
+// RoundTrip exploits that by always scanning line-by-line and accumulating
+// content and eval counts as they arrive, rather than branching on the
+// request's stream flag.
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("ollama")
+}
+
+// Metadata keys set on the span for a chat/generate call.
+const (
+	metadataPromptEvalCount = "langwatch.ollama.prompt_eval_count"
+	metadataEvalCount       = "langwatch.ollama.eval_count"
+)
+
+// RoundTripper instruments requests to the Ollama API paths this package
+// understands with a LangWatch span, forwarding every other request to
+// next untouched. Build one with Wrap.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Wrap returns a RoundTripper instrumenting Ollama API calls and forwarding
+// to next. A nil next forwards to http.DefaultTransport.
+func Wrap(next http.RoundTripper) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RoundTripper{next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind, ok := endpointKind(req.URL.Path)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	reqBody, err := drain(&req.Body)
+	if err != nil {
+		return rt.next.RoundTrip(req)
+	}
+	model, input := describeRequest(kind, reqBody)
+
+	ctx, span := langwatch.StartSpan(req.Context(), "ollama."+string(kind), langwatch.WithType(langwatch.SpanTypeLLM), langwatch.WithModel("ollama", model))
+	defer span.End()
+	if input != nil {
+		span.RecordInput(*input)
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, err
+	}
+
+	respBody, err := drain(&resp.Body)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return resp, nil
+	}
+	recordResponse(span, kind, respBody)
+
+	return resp, nil
+}
+
+type endpoint string
+
+const (
+	endpointChat       endpoint = "chat"
+	endpointGenerate   endpoint = "generate"
+	endpointEmbeddings endpoint = "embeddings"
+)
+
+func endpointKind(path string) (endpoint, bool) {
+	switch path {
+	case "/api/chat":
+		return endpointChat, true
+	case "/api/generate":
+		return endpointGenerate, true
+	case "/api/embeddings":
+		return endpointEmbeddings, true
+	default:
+		return "", false
+	}
+}
+
+// drain reads *body fully and replaces it with a fresh reader over the same
+// bytes, so the caller (RoundTrip's caller, or the real transport) still
+// sees a complete, unread body after this package has inspected it.
+func drain(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+type chatRequest struct {
+	Model    string                  `json:"model"`
+	Messages []langwatch.ChatMessage `json:"messages"`
+}
+
+type generateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type embeddingsRequest struct {
+	Model string `json:"model"`
+	Input string `json:"prompt"`
+}
+
+// describeRequest extracts the model name and a TypedValue worth recording
+// as the span's input from body, whose shape depends on kind.
+func describeRequest(kind endpoint, body []byte) (model string, input *langwatch.TypedValue) {
+	switch kind {
+	case endpointChat:
+		var req chatRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewChatMessagesValue(req.Messages)
+			return req.Model, &value
+		}
+	case endpointGenerate:
+		var req generateRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewTextValue(req.Prompt)
+			return req.Model, &value
+		}
+	case endpointEmbeddings:
+		var req embeddingsRequest
+		if err := json.Unmarshal(body, &req); err == nil {
+			value := langwatch.NewTextValue(req.Input)
+			return req.Model, &value
+		}
+	}
+	return "", nil
+}
+
+// chatOrGenerateChunk covers both /api/chat and /api/generate response
+// lines: a chat chunk carries message.content, a generate chunk carries
+// response directly. Both share the same done/eval-count trailer.
+type chatOrGenerateChunk struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+}
+
+type embeddingsResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// recordResponse accumulates body - one or more NDJSON lines - onto span as
+// output and usage metrics.
+func recordResponse(span *langwatch.Span, kind endpoint, body []byte) {
+	if kind == endpointEmbeddings {
+		var resp embeddingsResponse
+		if err := json.Unmarshal(body, &resp); err == nil {
+			span.RecordOutput(langwatch.NewJSONValue(map[string]interface{}{"dimensions": len(resp.Embedding)}))
+		}
+		return
+	}
+
+	var output strings.Builder
+	var promptEvalCount, evalCount int
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk chatOrGenerateChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			continue
+		}
+		if kind == endpointChat {
+			output.WriteString(chunk.Message.Content)
+		} else {
+			output.WriteString(chunk.Response)
+		}
+		if chunk.Done {
+			promptEvalCount = chunk.PromptEvalCount
+			evalCount = chunk.EvalCount
+		}
+	}
+
+	span.RecordOutput(langwatch.NewTextValue(output.String()))
+	span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptEvalCount, CompletionTokens: &evalCount})
+	span.SetMetadata(metadataPromptEvalCount, strconv.Itoa(promptEvalCount))
+	span.SetMetadata(metadataEvalCount, strconv.Itoa(evalCount))
+}
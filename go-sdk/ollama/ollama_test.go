@@ -0,0 +1,134 @@
+package ollama
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func recordedSpan(t *testing.T, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(t.TempDir() + "/traces.jsonl")
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected exactly 1 recorded span, got %d", len(matches))
+	}
+	return matches[0].Span
+}
+
+func TestRoundTripAccumulatesStreamingChatResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, `{"message":{"role":"assistant","content":"Hel"},"done":false}`+"\n")
+		io.WriteString(w, `{"message":{"role":"assistant","content":"lo"},"done":false}`+"\n")
+		io.WriteString(w, `{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":10,"eval_count":4}`+"\n")
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/chat", strings.NewReader(`{"model":"llama3","messages":[{"role":"user","content":"hi"}]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "llama3" {
+		t.Fatalf("Model = %q, want %q", record.Model, "llama3")
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "Hello" {
+		t.Fatalf("expected accumulated output %q, got %+v", "Hello", record.Outputs)
+	}
+	if record.Metrics == nil || record.Metrics.PromptTokens == nil || *record.Metrics.PromptTokens != 10 {
+		t.Fatalf("unexpected metrics: %+v", record.Metrics)
+	}
+	if record.Metrics.CompletionTokens == nil || *record.Metrics.CompletionTokens != 4 {
+		t.Fatalf("unexpected metrics: %+v", record.Metrics)
+	}
+}
+
+func TestRoundTripHandlesNonStreamingGenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"response":"the answer","done":true,"prompt_eval_count":3,"eval_count":2}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/generate", strings.NewReader(`{"model":"mistral","prompt":"what?"}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "mistral" {
+		t.Fatalf("Model = %q, want %q", record.Model, "mistral")
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "the answer" {
+		t.Fatalf("unexpected outputs: %+v", record.Outputs)
+	}
+}
+
+func TestRoundTripRecordsEmbeddingsDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"embedding":[0.1,0.2,0.3]}`)
+	}))
+	defer server.Close()
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/embeddings", strings.NewReader(`{"model":"nomic-embed-text","prompt":"hello"}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	record := recordedSpan(t, trace)
+	if record.Model != "nomic-embed-text" {
+		t.Fatalf("Model = %q, want %q", record.Model, "nomic-embed-text")
+	}
+	if len(record.Outputs) != 1 {
+		t.Fatalf("expected 1 output, got %+v", record.Outputs)
+	}
+}
+
+func TestRoundTripPassesThroughUnrelatedPaths(t *testing.T) {
+	var hit bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	resp, err := client.Get(server.URL + "/api/tags")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if !hit {
+		t.Fatal("expected the request to reach the server")
+	}
+}
@@ -0,0 +1,224 @@
+// Package openaiusage instruments OpenAI's organization-level usage and
+// cost admin endpoints (/v1/organization/usage, /v1/organization/costs), so
+// a Go cron job can reconcile what LangWatch computed for a period against
+// what OpenAI actually billed, without a platform team hand-rolling the
+// HTTP calls and JSON shapes themselves.
+//
+// Reconcile and Report work from Buckets already fetched with Client,
+// rather than fetching internally: callers running this from a scheduled
+// job typically want to log or alert on the raw buckets too, not just the
+// reconciled total.
+package openaiusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+const defaultBaseURL = "https://api.openai.com"
+
+// Metadata keys Report sets on the reconciliation span.
+const (
+	metadataProviderCostUSD    = "langwatch.usage_reconciliation.provider_cost_usd"
+	metadataLangWatchCostUSD   = "langwatch.usage_reconciliation.langwatch_cost_usd"
+	metadataDiscrepancyUSD     = "langwatch.usage_reconciliation.discrepancy_usd"
+	metadataDiscrepancyPercent = "langwatch.usage_reconciliation.discrepancy_percent"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("openaiusage")
+}
+
+// ClientOption configures a Client built with NewClient.
+type ClientOption func(*Client)
+
+// WithBaseURL overrides the API base URL, e.g. to point at a proxy in
+// tests. Defaults to https://api.openai.com.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) { c.baseURL = baseURL }
+}
+
+// WithHTTPClient overrides the http.Client used for requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// Client calls OpenAI's organization usage and cost admin endpoints. These
+// require an admin API key (sk-admin-...), not a regular project API key.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient builds a Client authenticated with apiKey, an OpenAI admin key.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{apiKey: apiKey, baseURL: defaultBaseURL, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Params bounds a usage/cost query to a time range, in unix seconds,
+// following the admin API's start_time/end_time query parameters.
+type Params struct {
+	StartTime int64
+	EndTime   int64
+}
+
+// UsageBucket is one time bucket of /v1/organization/usage results.
+type UsageBucket struct {
+	StartTime        int64  `json:"start_time"`
+	EndTime          int64  `json:"end_time"`
+	InputTokens      int64  `json:"input_tokens"`
+	OutputTokens     int64  `json:"output_tokens"`
+	NumModelRequests int64  `json:"num_model_requests"`
+	Model            string `json:"model,omitempty"`
+}
+
+// CostBucket is one time bucket of /v1/organization/costs results.
+type CostBucket struct {
+	StartTime int64   `json:"start_time"`
+	EndTime   int64   `json:"end_time"`
+	AmountUSD float64 `json:"amount_usd"`
+}
+
+type usageResponse struct {
+	Data []UsageBucket `json:"data"`
+}
+
+type costsResponseItem struct {
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+	Results   []struct {
+		Amount struct {
+			Value float64 `json:"value"`
+		} `json:"amount"`
+	} `json:"results"`
+}
+
+type costsResponse struct {
+	Data []costsResponseItem `json:"data"`
+}
+
+// Usage fetches token usage buckets for the given time range from
+// /v1/organization/usage.
+func (c *Client) Usage(ctx context.Context, params Params) ([]UsageBucket, error) {
+	var resp usageResponse
+	if err := c.get(ctx, "/v1/organization/usage", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Costs fetches billed cost buckets for the given time range from
+// /v1/organization/costs, summing the amount across every result line
+// within a bucket (the admin API can break a bucket down further, e.g. by
+// line item, which callers reconciling a total don't need).
+func (c *Client) Costs(ctx context.Context, params Params) ([]CostBucket, error) {
+	var resp costsResponse
+	if err := c.get(ctx, "/v1/organization/costs", params, &resp); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]CostBucket, 0, len(resp.Data))
+	for _, item := range resp.Data {
+		var amount float64
+		for _, result := range item.Results {
+			amount += result.Amount.Value
+		}
+		buckets = append(buckets, CostBucket{StartTime: item.StartTime, EndTime: item.EndTime, AmountUSD: amount})
+	}
+	return buckets, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, params Params, out interface{}) error {
+	u := c.baseURL + path
+	if params.StartTime != 0 || params.EndTime != 0 {
+		q := url.Values{}
+		if params.StartTime != 0 {
+			q.Set("start_time", fmt.Sprintf("%d", params.StartTime))
+		}
+		if params.EndTime != 0 {
+			q.Set("end_time", fmt.Sprintf("%d", params.EndTime))
+		}
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("openaiusage: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("openaiusage: request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("openaiusage: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("openaiusage: decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// TotalCostUSD sums the amount across every cost bucket.
+func TotalCostUSD(buckets []CostBucket) float64 {
+	var total float64
+	for _, b := range buckets {
+		total += b.AmountUSD
+	}
+	return total
+}
+
+// Reconciliation compares OpenAI's billed cost for a period against what
+// LangWatch computed from the same calls' recorded Metrics.Cost.
+type Reconciliation struct {
+	ProviderCostUSD    float64
+	LangWatchCostUSD   float64
+	DiscrepancyUSD     float64
+	DiscrepancyPercent float64
+}
+
+// Reconcile compares providerCostUSD (from Client.Costs, via TotalCostUSD)
+// against langWatchCostUSD (summed from LangWatch's own recorded spans, e.g.
+// via a LocalStore query). DiscrepancyPercent is relative to
+// providerCostUSD, and is 0 when providerCostUSD is 0.
+func Reconcile(providerCostUSD, langWatchCostUSD float64) Reconciliation {
+	r := Reconciliation{
+		ProviderCostUSD:  providerCostUSD,
+		LangWatchCostUSD: langWatchCostUSD,
+		DiscrepancyUSD:   langWatchCostUSD - providerCostUSD,
+	}
+	if providerCostUSD != 0 {
+		r.DiscrepancyPercent = r.DiscrepancyUSD / providerCostUSD * 100
+	}
+	return r
+}
+
+// Report exports r as a span, so a cron job's reconciliation run shows up
+// in LangWatch alongside the traces it's reconciling, instead of only in
+// the job's own logs. This SDK has no dedicated metrics-push API outside of
+// spans, so the reconciliation is recorded as a plain span with the
+// comparison as metadata.
+func Report(ctx context.Context, exporter langwatch.Exporter, r Reconciliation) error {
+	ctx, trace := langwatch.NewTrace(ctx)
+	_, span := langwatch.StartSpan(ctx, "openaiusage.reconciliation")
+	span.SetMetadata(metadataProviderCostUSD, fmt.Sprintf("%.6f", r.ProviderCostUSD))
+	span.SetMetadata(metadataLangWatchCostUSD, fmt.Sprintf("%.6f", r.LangWatchCostUSD))
+	span.SetMetadata(metadataDiscrepancyUSD, fmt.Sprintf("%.6f", r.DiscrepancyUSD))
+	span.SetMetadata(metadataDiscrepancyPercent, fmt.Sprintf("%.4f", r.DiscrepancyPercent))
+	span.End()
+
+	return exporter.Export(ctx, trace)
+}
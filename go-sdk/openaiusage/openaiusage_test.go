@@ -0,0 +1,128 @@
+package openaiusage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestClientUsageParsesBuckets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/organization/usage" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("start_time"); got != "100" {
+			t.Fatalf("start_time = %q, want %q", got, "100")
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-admin-test" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{"data": [{"start_time": 100, "end_time": 200, "input_tokens": 10, "output_tokens": 5, "num_model_requests": 2, "model": "gpt-4o"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-admin-test", WithBaseURL(server.URL))
+	buckets, err := client.Usage(context.Background(), Params{StartTime: 100, EndTime: 200})
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].InputTokens != 10 || buckets[0].Model != "gpt-4o" {
+		t.Fatalf("unexpected buckets: %+v", buckets)
+	}
+}
+
+func TestClientCostsSumsResultLines(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"start_time": 100, "end_time": 200, "results": [{"amount": {"value": 1.25}}, {"amount": {"value": 0.75}}]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-admin-test", WithBaseURL(server.URL))
+	buckets, err := client.Costs(context.Background(), Params{})
+	if err != nil {
+		t.Fatalf("Costs: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].AmountUSD != 2.0 {
+		t.Fatalf("unexpected buckets: %+v", buckets)
+	}
+	if total := TotalCostUSD(buckets); total != 2.0 {
+		t.Fatalf("TotalCostUSD = %v, want 2.0", total)
+	}
+}
+
+func TestClientErrorsOnNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-admin-test", WithBaseURL(server.URL))
+	if _, err := client.Usage(context.Background(), Params{}); err == nil {
+		t.Fatal("expected an error for a 401 response")
+	}
+}
+
+func TestReconcileComputesDiscrepancy(t *testing.T) {
+	r := Reconcile(100.0, 90.0)
+	if r.DiscrepancyUSD != -10.0 {
+		t.Fatalf("DiscrepancyUSD = %v, want -10.0", r.DiscrepancyUSD)
+	}
+	if r.DiscrepancyPercent != -10.0 {
+		t.Fatalf("DiscrepancyPercent = %v, want -10.0", r.DiscrepancyPercent)
+	}
+}
+
+func TestReconcileZeroProviderCostAvoidsDivideByZero(t *testing.T) {
+	r := Reconcile(0, 5.0)
+	if r.DiscrepancyPercent != 0 {
+		t.Fatalf("DiscrepancyPercent = %v, want 0", r.DiscrepancyPercent)
+	}
+}
+
+type fakeExporter struct {
+	traces []*langwatch.Trace
+}
+
+func (f *fakeExporter) Export(ctx context.Context, trace *langwatch.Trace) error {
+	f.traces = append(f.traces, trace)
+	return nil
+}
+
+func TestReportExportsReconciliationAsSpan(t *testing.T) {
+	exporter := &fakeExporter{}
+	r := Reconcile(100.0, 95.0)
+
+	if err := Report(context.Background(), exporter, r); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(exporter.traces) != 1 {
+		t.Fatalf("expected 1 exported trace, got %d", len(exporter.traces))
+	}
+	trace := exporter.traces[0]
+
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(matches))
+	}
+	record := matches[0].Span
+	if record.Metadata[metadataProviderCostUSD] != "100.000000" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataProviderCostUSD, record.Metadata[metadataProviderCostUSD], "100.000000")
+	}
+	if record.Metadata[metadataDiscrepancyUSD] != "-5.000000" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataDiscrepancyUSD, record.Metadata[metadataDiscrepancyUSD], "-5.000000")
+	}
+}
@@ -0,0 +1,234 @@
+// Package otelopenai provides a pre-instrumented client for OpenAI's chat
+// completions API, so services stop hand-rolling the same ~10 lines of
+// http.Client setup, retry-on-429 loop, and LangWatch span bookkeeping at
+// every call site.
+//
+// This package does not depend on OpenAI's own Go SDK - it isn't a module
+// this repo currently vendors, and GOPROXY is disabled in this environment
+// - so NewClient returns this package's own minimal Client rather than a
+// wrapped openai.Client. It covers the same "one call instead of ten
+// lines" goal: an authenticated http.Client, LangWatch spans per request,
+// and a retry policy that honors 429 Retry-After, all applied by default.
+package otelopenai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+const defaultBaseURL = "https://api.openai.com"
+
+func init() {
+	langwatch.RegisterInstrumentation("otelopenai")
+}
+
+// ClientOption configures a Client built with NewClient.
+type ClientOption func(*Client)
+
+// WithBaseURL overrides the API base URL, e.g. to point at a proxy or an
+// OpenAI-compatible provider. Defaults to https://api.openai.com.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) { c.baseURL = baseURL }
+}
+
+// WithHTTPClient overrides the underlying http.Client. The client is used
+// as given; its Transport is not wrapped.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithMaxRetries sets how many times a request is retried. A request that
+// fails to send is retried at once; an error response is retried only when
+// langwatch.ParseRetryAfter finds a Retry-After or rate-limit reset header
+// on it (as on a typical 429), sleeping for its Retry-After duration
+// before the retry. Defaults to 2.
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithValidators runs validators against every completion's output,
+// recording each outcome as a timeline event on the request's span. When a
+// completion fails validation and retry budget remains (see
+// WithMaxRetries), CreateChatCompletion automatically retries the request
+// with langwatch.FailureFeedback(result) appended as a user message,
+// giving the model a chance to correct itself.
+func WithValidators(validators ...langwatch.Validator) ClientOption {
+	return func(c *Client) { c.validators = langwatch.NewValidatorChain(validators...) }
+}
+
+// ChatMessage is a single message in a chat completion request or
+// response, matching langwatch.ChatMessage's shape.
+type ChatMessage = langwatch.ChatMessage
+
+// ChatCompletionRequest is the subset of OpenAI's chat completions request
+// body this client sends.
+type ChatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+// ChatCompletionResponse is the subset of OpenAI's chat completions
+// response body this client reads.
+type ChatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      ChatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Client calls OpenAI's chat completions endpoint, recording a LangWatch
+// LLM span - with model, messages, usage, and errors - around every call.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	validators *langwatch.ValidatorChain
+}
+
+// NewClient builds a Client authenticated with apiKey (an OpenAI API key),
+// with LangWatch instrumentation and a Retry-After retry policy applied by
+// default. NewClient sets no capture settings of its own.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+		maxRetries: 2,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateChatCompletion sends req to /v1/chat/completions, retrying up to
+// the client's WithMaxRetries limit as WithMaxRetries describes, honoring
+// the response's Retry-After header between attempts. If WithValidators was
+// used, a response that fails validation is also retried within the same
+// budget, with langwatch.FailureFeedback appended to the conversation so
+// the model sees what was wrong with its previous answer.
+func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error) {
+	ctx, span := langwatch.StartSpan(ctx, "openai.chat.completions", langwatch.WithType(langwatch.SpanTypeLLM), langwatch.WithModel("openai", req.Model))
+	defer span.End()
+	span.RecordInput(langwatch.NewJSONValue(req.Messages))
+
+	var lastErr error
+	var lastValidation *langwatch.ValidationResult
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		body, err := json.Marshal(req)
+		if err != nil {
+			span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+			return ChatCompletionResponse{}, fmt.Errorf("otelopenai: encode request: %w", err)
+		}
+
+		resp, err := c.do(ctx, body)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.err != nil {
+			if retryAfter := langwatch.ParseRetryAfter(resp.header); retryAfter != nil && attempt < c.maxRetries {
+				lastErr = resp.err
+				sleep(ctx, retryAfter.RetryAfter)
+				continue
+			}
+			span.RecordError(langwatch.ErrorCapture{Message: resp.err.Error()})
+			return ChatCompletionResponse{}, resp.err
+		}
+
+		if c.validators != nil && len(resp.completion.Choices) > 0 {
+			output := contentOf(resp.completion.Choices[0].Message)
+			if result := c.validators.Validate(ctx, output); result != nil {
+				lastValidation = result
+				if attempt < c.maxRetries {
+					req.Messages = append(req.Messages, ChatMessage{Role: langwatch.RoleUser, Content: stringPtr(langwatch.FailureFeedback(*result))})
+					continue
+				}
+				break
+			}
+		}
+
+		span.RecordOutput(langwatch.NewJSONValue(resp.completion.Choices))
+		promptTokens, completionTokens := resp.completion.Usage.PromptTokens, resp.completion.Usage.CompletionTokens
+		span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+		return resp.completion, nil
+	}
+
+	if lastValidation != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: lastValidation.Reason})
+		return ChatCompletionResponse{}, fmt.Errorf("otelopenai: response failed validation after %d retries: %s", c.maxRetries, lastValidation.Reason)
+	}
+	span.RecordError(langwatch.ErrorCapture{Message: lastErr.Error()})
+	return ChatCompletionResponse{}, fmt.Errorf("otelopenai: request failed after %d retries: %w", c.maxRetries, lastErr)
+}
+
+// contentOf returns m's content, or "" if it's nil.
+func contentOf(m ChatMessage) string {
+	if m.Content == nil {
+		return ""
+	}
+	return *m.Content
+}
+
+func stringPtr(s string) *string { return &s }
+
+type rawResponse struct {
+	completion ChatCompletionResponse
+	header     http.Header
+	err        error
+}
+
+func (c *Client) do(ctx context.Context, body []byte) (rawResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return rawResponse{}, fmt.Errorf("otelopenai: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return rawResponse{}, fmt.Errorf("otelopenai: send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rawResponse{}, fmt.Errorf("otelopenai: read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return rawResponse{header: resp.Header, err: fmt.Errorf("otelopenai: chat completions returned status %d: %s", resp.StatusCode, respBody)}, nil
+	}
+
+	var completion ChatCompletionResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return rawResponse{}, fmt.Errorf("otelopenai: decode response: %w", err)
+	}
+	return rawResponse{completion: completion, header: resp.Header}, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
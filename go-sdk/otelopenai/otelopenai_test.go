@@ -0,0 +1,115 @@
+package otelopenai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestClientCreateChatCompletionRecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 5, "completion_tokens": 2}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test", WithBaseURL(server.URL))
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []ChatMessage{{Role: langwatch.RoleUser, Content: strPtr("hello")}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(resp.Choices) != 1 || contentOf(resp.Choices[0].Message) != "hi" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.PromptTokens != 5 || resp.Usage.CompletionTokens != 2 {
+		t.Fatalf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestClientRetriesOnRateLimitThenSucceeds(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test", WithBaseURL(server.URL), WithMaxRetries(1))
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests (1 rate-limited + 1 retry), got %d", requests)
+	}
+	if len(resp.Choices) != 1 || contentOf(resp.Choices[0].Message) != "ok" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestClientFailsAfterExhaustingRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test", WithBaseURL(server.URL), WithMaxRetries(0))
+	if _, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "gpt-4o"}); err == nil {
+		t.Fatal("expected an error from a non-2xx response")
+	}
+}
+
+func TestClientRetriesWithFeedbackOnValidationFailure(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": ""}}]}`))
+			return
+		}
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": "a real answer"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test", WithBaseURL(server.URL), WithMaxRetries(1), WithValidators(langwatch.NoEmptyOutput()))
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests (1 empty + 1 retry), got %d", requests)
+	}
+	if contentOf(resp.Choices[0].Message) != "a real answer" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestClientFailsAfterExhaustingValidationRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": ""}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test", WithBaseURL(server.URL), WithMaxRetries(1), WithValidators(langwatch.NoEmptyOutput()))
+	if _, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "gpt-4o"}); err == nil {
+		t.Fatal("expected an error after exhausting validation retries")
+	}
+}
@@ -0,0 +1,88 @@
+package langwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Policy holds the runtime-tunable knobs that PolicyWatcher hot-reloads from
+// a file: how much traffic to sample and which attributes to drop before
+// export.
+type Policy struct {
+	// SampleRate is the fraction of traces to export, in [0, 1]. 1 exports
+	// everything.
+	SampleRate float64 `json:"sample_rate"`
+	// CaptureDisabled mirrors WithCaptureDisabled's effect, applied
+	// globally instead of per-request.
+	CaptureDisabled bool `json:"capture_disabled"`
+	// DeniedAttributes lists metadata keys stripped from every span before
+	// export (see PushAttrs).
+	DeniedAttributes []string `json:"denied_attributes"`
+}
+
+// DefaultPolicy is used until a PolicyWatcher successfully loads its first
+// file, and is the base LoadPolicyFile overlays the file's fields onto, so
+// fields the file omits keep their default values.
+var DefaultPolicy = Policy{SampleRate: 1}
+
+func (p Policy) validate() error {
+	if p.SampleRate < 0 || p.SampleRate > 1 {
+		return fmt.Errorf("langwatch: sample_rate must be between 0 and 1, got %v", p.SampleRate)
+	}
+	return nil
+}
+
+// PolicySource is implemented by whatever keeps a Policy up to date -
+// PolicyWatcher (a local file) and RemoteSyncer (the LangWatch API) both
+// already expose a matching Current() Policy method, so either can be
+// passed to SetActivePolicy as-is.
+type PolicySource interface {
+	Current() Policy
+}
+
+var activePolicy struct {
+	mu     sync.RWMutex
+	source PolicySource
+}
+
+// SetActivePolicy makes source's Policy take effect process-wide: from this
+// call on, CaptureEnabled honors CaptureDisabled, and every Exporter built
+// with NewExporter samples traces by SampleRate and strips DeniedAttributes
+// in addition to any configured with WithDeniedAttributes. Without a call
+// to SetActivePolicy, a PolicyWatcher or RemoteSyncer only hot-reloads and
+// exposes Policy via Current - it has no effect on SDK behavior until
+// wired in here.
+func SetActivePolicy(source PolicySource) {
+	activePolicy.mu.Lock()
+	defer activePolicy.mu.Unlock()
+	activePolicy.source = source
+}
+
+// activePolicyOrDefault returns the Policy from the source registered with
+// SetActivePolicy, or DefaultPolicy if none has been registered.
+func activePolicyOrDefault() Policy {
+	activePolicy.mu.RLock()
+	defer activePolicy.mu.RUnlock()
+	if activePolicy.source == nil {
+		return DefaultPolicy
+	}
+	return activePolicy.source.Current()
+}
+
+// LoadPolicyFile reads and validates a Policy from a JSON file.
+func LoadPolicyFile(path string) (Policy, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("langwatch: read policy file %s: %w", path, err)
+	}
+	policy := DefaultPolicy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return Policy{}, fmt.Errorf("langwatch: parse policy file %s: %w", path, err)
+	}
+	if err := policy.validate(); err != nil {
+		return Policy{}, fmt.Errorf("langwatch: policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
@@ -0,0 +1,113 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testPolicySource is a fixed PolicySource for exercising SetActivePolicy
+// without a real PolicyWatcher or RemoteSyncer.
+type testPolicySource struct {
+	policy Policy
+}
+
+func (s testPolicySource) Current() Policy {
+	return s.policy
+}
+
+func resetActivePolicy(t *testing.T) {
+	t.Helper()
+	SetActivePolicy(nil)
+	t.Cleanup(func() { SetActivePolicy(nil) })
+}
+
+func TestActivePolicyOrDefaultWithoutSetActivePolicy(t *testing.T) {
+	resetActivePolicy(t)
+	got := activePolicyOrDefault()
+	if got.SampleRate != DefaultPolicy.SampleRate || got.CaptureDisabled != DefaultPolicy.CaptureDisabled || len(got.DeniedAttributes) != 0 {
+		t.Fatalf("activePolicyOrDefault() = %+v, want DefaultPolicy", got)
+	}
+}
+
+func TestSetActivePolicyIsConsultedByActivePolicyOrDefault(t *testing.T) {
+	resetActivePolicy(t)
+	SetActivePolicy(testPolicySource{policy: Policy{SampleRate: 0.5, CaptureDisabled: true}})
+
+	got := activePolicyOrDefault()
+	if got.SampleRate != 0.5 || !got.CaptureDisabled {
+		t.Fatalf("activePolicyOrDefault() = %+v", got)
+	}
+}
+
+func TestCaptureEnabledHonorsActivePolicy(t *testing.T) {
+	resetActivePolicy(t)
+	SetActivePolicy(testPolicySource{policy: Policy{SampleRate: 1, CaptureDisabled: true}})
+
+	if CaptureEnabled(context.Background()) {
+		t.Fatal("expected the active policy's CaptureDisabled to disable capture")
+	}
+}
+
+func TestExporterDropsUnsampledTraces(t *testing.T) {
+	resetActivePolicy(t)
+	SetActivePolicy(testPolicySource{policy: Policy{SampleRate: 0}})
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected an unsampled trace to skip the request entirely, got %d requests", requests)
+	}
+}
+
+func TestExporterStripsPolicyDeniedAttributes(t *testing.T) {
+	resetActivePolicy(t)
+	SetActivePolicy(testPolicySource{policy: Policy{SampleRate: 1, DeniedAttributes: []string{"secret"}}})
+
+	var got CollectorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op")
+	span.SetMetadata("secret", "shh")
+	span.SetMetadata("kept", "yes")
+	span.End()
+
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	if len(got.Spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(got.Spans))
+	}
+	if _, ok := got.Spans[0].Metadata["secret"]; ok {
+		t.Fatal("expected the policy-denied attribute to be stripped")
+	}
+	if got.Spans[0].Metadata["kept"] != "yes" {
+		t.Fatalf("expected an unrelated attribute to survive, got %+v", got.Spans[0].Metadata)
+	}
+}
@@ -0,0 +1,87 @@
+package langwatch
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// PolicyWatcher keeps a Policy in sync with a file on disk, applying changes
+// atomically as they're written and leaving the previously-applied policy in
+// place if a new version fails to parse or validate, so a bad edit never
+// disrupts the running export pipeline. A PolicyWatcher only has an effect
+// on capture/sampling/export once passed to SetActivePolicy - by itself it
+// just parses and hot-reloads the file.
+type PolicyWatcher struct {
+	path    string
+	current atomic.Pointer[Policy]
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewPolicyWatcher loads path once synchronously and then watches it for
+// changes in the background until Close is called.
+func NewPolicyWatcher(path string) (*PolicyWatcher, error) {
+	policy, err := LoadPolicyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(path); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	pw := &PolicyWatcher{path: path, watcher: watcher, done: make(chan struct{})}
+	pw.current.Store(&policy)
+	go pw.run()
+	return pw, nil
+}
+
+// Current returns the most recently applied policy.
+func (pw *PolicyWatcher) Current() Policy {
+	return *pw.current.Load()
+}
+
+// Close stops watching the file. It does not affect the last applied Policy.
+func (pw *PolicyWatcher) Close() error {
+	close(pw.done)
+	return pw.watcher.Close()
+}
+
+func (pw *PolicyWatcher) run() {
+	for {
+		select {
+		case <-pw.done:
+			return
+		case event, ok := <-pw.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			pw.reload()
+		case err, ok := <-pw.watcher.Errors:
+			if !ok {
+				return
+			}
+			debugLog("policy watcher error on %s: %v", pw.path, err)
+		}
+	}
+}
+
+func (pw *PolicyWatcher) reload() {
+	policy, err := LoadPolicyFile(pw.path)
+	if err != nil {
+		debugLog("policy reload of %s rejected, keeping previous policy: %v", pw.path, err)
+		return
+	}
+	pw.current.Store(&policy)
+	publish(context.Background(), Event{Type: EventPolicyReloaded})
+}
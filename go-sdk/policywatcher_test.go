@@ -0,0 +1,68 @@
+package langwatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePolicyFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("write policy file: %v", err)
+	}
+}
+
+func TestPolicyWatcherHotReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicyFile(t, path, `{"sample_rate": 1}`)
+
+	pw, err := NewPolicyWatcher(path)
+	if err != nil {
+		t.Fatalf("NewPolicyWatcher returned error: %v", err)
+	}
+	defer pw.Close()
+
+	if pw.Current().SampleRate != 1 {
+		t.Fatalf("expected initial sample rate 1, got %v", pw.Current().SampleRate)
+	}
+
+	writePolicyFile(t, path, `{"sample_rate": 0.25}`)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if pw.Current().SampleRate == 0.25 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected sample rate to hot-reload to 0.25, got %v", pw.Current().SampleRate)
+}
+
+func TestPolicyWatcherKeepsPreviousPolicyOnInvalidReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicyFile(t, path, `{"sample_rate": 0.5}`)
+
+	pw, err := NewPolicyWatcher(path)
+	if err != nil {
+		t.Fatalf("NewPolicyWatcher returned error: %v", err)
+	}
+	defer pw.Close()
+
+	writePolicyFile(t, path, `{"sample_rate": 5}`)
+
+	time.Sleep(200 * time.Millisecond)
+	if pw.Current().SampleRate != 0.5 {
+		t.Fatalf("expected invalid reload to be rejected, got %v", pw.Current().SampleRate)
+	}
+}
+
+func TestLoadPolicyFileRejectsInvalidSampleRate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicyFile(t, path, `{"sample_rate": -1}`)
+
+	if _, err := LoadPolicyFile(path); err == nil {
+		t.Fatal("expected an error for an out-of-range sample_rate")
+	}
+}
@@ -0,0 +1,99 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// deletionPath is the LangWatch admin endpoint for right-to-erasure
+// requests, mirroring the collector's own path convention.
+const deletionPath = "/api/privacy/delete"
+
+// DeleteResult reports how many traces a deletion request removed.
+type DeleteResult struct {
+	DeletedTraces int
+}
+
+// PrivacyClient wraps the LangWatch data-deletion API, so compliance
+// tooling can execute right-to-erasure requests without hand-rolling the
+// HTTP and pagination involved.
+type PrivacyClient struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewPrivacyClient builds a PrivacyClient from cfg, applying the same
+// environment defaults as NewExporter.
+func NewPrivacyClient(cfg Config) *PrivacyClient {
+	return &PrivacyClient{config: cfg.withDefaults(), httpClient: http.DefaultClient}
+}
+
+// DeleteByUser deletes every trace attributed to userID, following
+// pagination until the collector reports nothing left to delete.
+func (p *PrivacyClient) DeleteByUser(ctx context.Context, userID string) (DeleteResult, error) {
+	return p.deleteBy(ctx, "user_id", userID)
+}
+
+// DeleteByThread deletes every trace attributed to threadID, following
+// pagination until the collector reports nothing left to delete.
+func (p *PrivacyClient) DeleteByThread(ctx context.Context, threadID string) (DeleteResult, error) {
+	return p.deleteBy(ctx, "thread_id", threadID)
+}
+
+func (p *PrivacyClient) deleteBy(ctx context.Context, field, value string) (DeleteResult, error) {
+	var total DeleteResult
+	cursor := ""
+	for {
+		page, next, err := p.deletePage(ctx, field, value, cursor)
+		if err != nil {
+			return total, err
+		}
+		total.DeletedTraces += page.DeletedTraces
+		if next == "" {
+			return total, nil
+		}
+		cursor = next
+	}
+}
+
+// deletePageResponse is the wire shape of one page of a deletion request.
+type deletePageResponse struct {
+	DeletedTraces int    `json:"deleted_traces"`
+	NextCursor    string `json:"next_cursor"`
+}
+
+func (p *PrivacyClient) deletePage(ctx context.Context, field, value, cursor string) (DeleteResult, string, error) {
+	body, err := json.Marshal(map[string]string{field: value, "cursor": cursor})
+	if err != nil {
+		return DeleteResult{}, "", fmt.Errorf("langwatch: build deletion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Endpoint+deletionPath, bytes.NewReader(body))
+	if err != nil {
+		return DeleteResult{}, "", fmt.Errorf("langwatch: build deletion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", resolveAPIKey(ctx, p.config.APIKey))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return DeleteResult{}, "", fmt.Errorf("langwatch: deletion request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return DeleteResult{}, "", fmt.Errorf("langwatch: deletion rejected, check your API key: %w", ErrUnauthorized)
+	}
+	if resp.StatusCode >= 300 {
+		return DeleteResult{}, "", &ExportError{Status: resp.StatusCode}
+	}
+
+	var page deletePageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return DeleteResult{}, "", fmt.Errorf("langwatch: decode deletion response: %w", err)
+	}
+	return DeleteResult{DeletedTraces: page.DeletedTraces}, page.NextCursor, nil
+}
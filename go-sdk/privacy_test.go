@@ -0,0 +1,52 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrivacyClientDeleteByUserFollowsPagination(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["user_id"] != "user-42" {
+			t.Fatalf("expected user_id=user-42, got %q", body["user_id"])
+		}
+
+		if body["cursor"] == "" {
+			json.NewEncoder(w).Encode(deletePageResponse{DeletedTraces: 2, NextCursor: "page-2"})
+			return
+		}
+		json.NewEncoder(w).Encode(deletePageResponse{DeletedTraces: 1})
+	}))
+	defer server.Close()
+
+	client := NewPrivacyClient(Config{APIKey: "key", Endpoint: server.URL})
+	result, err := client.DeleteByUser(context.Background(), "user-42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeletedTraces != 3 {
+		t.Fatalf("DeletedTraces = %d, want 3", result.DeletedTraces)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 paginated requests, got %d", requests)
+	}
+}
+
+func TestPrivacyClientDeleteByThreadRejectsBadAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewPrivacyClient(Config{APIKey: "bad-key", Endpoint: server.URL})
+	if _, err := client.DeleteByThread(context.Background(), "thread-1"); err == nil {
+		t.Fatal("expected an error for a rejected API key")
+	}
+}
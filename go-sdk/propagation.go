@@ -0,0 +1,122 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Header names used when propagating LangWatch context over a message bus,
+// mirroring the naming used for HTTP headers elsewhere in the SDK.
+const (
+	headerTraceID    = "Langwatch-Trace-Id"
+	headerThreadID   = "Langwatch-Thread-Id"
+	headerUserID     = "Langwatch-User-Id"
+	headerCustomerID = "Langwatch-Customer-Id"
+	headerLabels     = "Langwatch-Labels"
+	headerBaggage    = "Langwatch-Baggage"
+)
+
+// Carrier is anything LangWatch context can be propagated through as
+// key/value string pairs, matching the shape of message headers across
+// Kafka, NATS and similar buses.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier adapts http.Header (and, since it shares the same
+// underlying type, NATS' Msg.Header) to Carrier.
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string { return http.Header(c).Get(key) }
+func (c HeaderCarrier) Set(key, value string) { http.Header(c).Set(key, value) }
+
+// KafkaHeader mirrors the header shape used by the common Kafka Go clients
+// (segmentio/kafka-go, confluent-kafka-go), avoiding a hard dependency on
+// either.
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaHeaderCarrier adapts a slice of Kafka headers to Carrier.
+type KafkaHeaderCarrier struct {
+	Headers *[]KafkaHeader
+}
+
+func (c KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c KafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if strings.EqualFold(h.Key, key) {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, KafkaHeader{Key: key, Value: []byte(value)})
+}
+
+// Inject writes ctx's trace link, thread/user/customer IDs, labels and
+// baggage (including the capture kill-switch) onto carrier, so a consumer
+// on the other side of a message bus can continue the trace with Extract.
+func Inject(ctx context.Context, carrier Carrier) {
+	if trace, ok := TraceFromContext(ctx); ok {
+		carrier.Set(headerTraceID, trace.ID())
+	}
+	if threadID := ThreadID(ctx); threadID != "" {
+		carrier.Set(headerThreadID, threadID)
+	}
+	if userID := UserID(ctx); userID != "" {
+		carrier.Set(headerUserID, userID)
+	}
+	if customerID := CustomerID(ctx); customerID != "" {
+		carrier.Set(headerCustomerID, customerID)
+	}
+	if labels := Labels(ctx); len(labels) > 0 {
+		carrier.Set(headerLabels, strings.Join(labels, ","))
+	}
+	if baggage := baggageFromContext(ctx); len(baggage) > 0 {
+		if encoded, err := json.Marshal(baggage); err == nil {
+			carrier.Set(headerBaggage, string(encoded))
+		}
+	}
+}
+
+// Extract restores the LangWatch context previously written by Inject,
+// starting a new Trace linked back to the producer's trace. It is the
+// message-bus equivalent of ExtractJob.
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	if threadID := carrier.Get(headerThreadID); threadID != "" {
+		ctx = WithThreadID(ctx, threadID)
+	}
+	if userID := carrier.Get(headerUserID); userID != "" {
+		ctx = WithUserID(ctx, userID)
+	}
+	if customerID := carrier.Get(headerCustomerID); customerID != "" {
+		ctx = WithCustomerID(ctx, customerID)
+	}
+	if labels := carrier.Get(headerLabels); labels != "" {
+		ctx = WithLabels(ctx, strings.Split(labels, ",")...)
+	}
+	if raw := carrier.Get(headerBaggage); raw != "" {
+		var baggage map[string]string
+		if err := json.Unmarshal([]byte(raw), &baggage); err == nil {
+			for k, v := range baggage {
+				ctx = WithBaggage(ctx, k, v)
+			}
+		}
+	}
+
+	ctx, trace := NewTrace(ctx)
+	trace.linkedFromTraceID = carrier.Get(headerTraceID)
+	return ctx
+}
@@ -0,0 +1,28 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKafkaHeaderCarrierRoundTrip(t *testing.T) {
+	ctx := WithThreadID(context.Background(), "thread-1")
+	ctx = WithCaptureDisabled(ctx)
+	ctx, trace := NewTrace(ctx)
+
+	var headers []KafkaHeader
+	Inject(ctx, KafkaHeaderCarrier{Headers: &headers})
+
+	consumerCtx := Extract(context.Background(), KafkaHeaderCarrier{Headers: &headers})
+
+	if got := ThreadID(consumerCtx); got != "thread-1" {
+		t.Fatalf("expected thread-1, got %q", got)
+	}
+	if CaptureEnabled(consumerCtx) {
+		t.Fatal("expected capture kill-switch to survive Kafka propagation")
+	}
+	consumerTrace, _ := TraceFromContext(consumerCtx)
+	if consumerTrace.LinkedFromTraceID() != trace.ID() {
+		t.Fatalf("expected linked trace id %q, got %q", trace.ID(), consumerTrace.LinkedFromTraceID())
+	}
+}
@@ -0,0 +1,81 @@
+package langwatch
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// metadataProviderRegion mirrors the OpenTelemetry GenAI semantic
+// conventions' attribute name (gen_ai.provider.region) rather than this
+// SDK's usual langwatch.* prefix, so it lines up with other OTel-based
+// GenAI instrumentation a LangWatch user might already have.
+const metadataProviderRegion = "gen_ai.provider.region"
+
+// hostRegions maps known provider endpoint hosts to a normalized region
+// label. Only hosts whose hostname alone identifies a fixed region are
+// listed here; Azure's regional subdomains are handled separately by
+// ClassifyProviderRegion since the region varies per deployment.
+var hostRegions = map[string]string{
+	"api.openai.com":    "us",
+	"eu.api.openai.com": "eu",
+	"api.anthropic.com": "us",
+}
+
+// ClassifyProviderRegion normalizes the host a request actually served from
+// (as opposed to the host it was addressed to, relevant behind a load
+// balancer or CDN) into a region label - "us", "eu", or an Azure region
+// name such as "westeurope" parsed from *.<region>.api.cognitive.microsoft.com
+// hosts; *.openai.azure.com hosts carry no region and yield "". Returns ""
+// for a host the SDK doesn't recognize, rather than guessing.
+func ClassifyProviderRegion(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return ""
+	}
+	if region, ok := hostRegions[host]; ok {
+		return region
+	}
+	if region, ok := azureRegion(host); ok {
+		return region
+	}
+	return ""
+}
+
+// azureRegion extracts the region from an Azure OpenAI/Cognitive Services
+// host. Azure resource hosts are named <resource>.openai.azure.com (no
+// region in the hostname) or <resource>.<region>.api.cognitive.microsoft.com
+// (region as the last label before the fixed api.cognitive.microsoft.com
+// suffix); only the latter shape identifies a region from the host alone.
+func azureRegion(host string) (string, bool) {
+	const suffix = ".api.cognitive.microsoft.com"
+	if !strings.HasSuffix(host, suffix) {
+		return "", false
+	}
+	labels := strings.Split(strings.TrimSuffix(host, suffix), ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	return labels[len(labels)-1], true
+}
+
+// RecordProviderRegion classifies rawURL's host with ClassifyProviderRegion
+// and, if it resolves to a known region, records it as gen_ai.provider.region
+// metadata on the span found in ctx. A no-op for an unparsable URL or an
+// unrecognized host, so an unknown or self-hosted endpoint just leaves the
+// attribute unset rather than recording a guess.
+func RecordProviderRegion(ctx context.Context, rawURL string) {
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+	region := ClassifyProviderRegion(parsed.Hostname())
+	if region == "" {
+		return
+	}
+	span.SetMetadata(metadataProviderRegion, region)
+}
@@ -0,0 +1,59 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClassifyProviderRegionKnownHosts(t *testing.T) {
+	cases := map[string]string{
+		"api.openai.com":    "us",
+		"eu.api.openai.com": "eu",
+		"API.OPENAI.COM":    "us",
+		"my-resource.westeurope.api.cognitive.microsoft.com": "westeurope",
+		"my-resource.openai.azure.com":                       "",
+		"":                                                   "",
+		"internal-gateway.example.com":                       "",
+	}
+	for host, want := range cases {
+		if got := ClassifyProviderRegion(host); got != want {
+			t.Errorf("ClassifyProviderRegion(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestRecordProviderRegionSetsMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordProviderRegion(ctx, "https://eu.api.openai.com/v1/chat/completions")
+	span.End()
+
+	if span.metadata[metadataProviderRegion] != "eu" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataProviderRegion, span.metadata[metadataProviderRegion], "eu")
+	}
+}
+
+func TestRecordProviderRegionNoOpForUnrecognizedHost(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordProviderRegion(ctx, "https://my-self-hosted-gateway.internal/v1/chat/completions")
+	span.End()
+
+	if _, ok := span.metadata[metadataProviderRegion]; ok {
+		t.Fatalf("expected no region metadata for an unrecognized host, got %+v", span.metadata)
+	}
+}
+
+func TestRecordProviderRegionNoOpForUnparsableURL(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordProviderRegion(ctx, "://not-a-url")
+	span.End()
+
+	if _, ok := span.metadata[metadataProviderRegion]; ok {
+		t.Fatalf("expected no region metadata for an unparsable URL, got %+v", span.metadata)
+	}
+}
@@ -0,0 +1,48 @@
+package langwatch
+
+import "encoding/json"
+
+// metadataRAGContexts is the metadata key RecordRAGContexts writes to,
+// carrying the full per-chunk detail (document/chunk IDs, retrieval score)
+// that the wire schema's plain-string Contexts field can't hold.
+const metadataRAGContexts = "langwatch.rag_contexts"
+
+// RAGChunk is a single chunk returned by a retrieval step, passed to
+// Span.RecordRAGContexts.
+type RAGChunk struct {
+	DocumentID string  `json:"document_id,omitempty"`
+	ChunkID    string  `json:"chunk_id,omitempty"`
+	Content    string  `json:"content"`
+	Score      float64 `json:"score,omitempty"`
+}
+
+// RecordRAGContexts records chunks retrieved for this span's query, so RAG
+// evaluations in LangWatch (context relevance, faithfulness, ...) have the
+// actual retrieved content to grade the response against, unless capture has
+// been disabled.
+//
+// It sets the span's Contexts wire field to each chunk's Content, and
+// additionally records the full chunk detail - document and chunk IDs,
+// retrieval score - as JSON under the langwatch.rag_contexts metadata key,
+// since Contexts only carries plain text.
+func (s *Span) RecordRAGContexts(chunks []RAGChunk) {
+	if s.noop {
+		return
+	}
+	if !s.captureEnabled {
+		return
+	}
+
+	contents := make([]string, len(chunks))
+	for i, c := range chunks {
+		contents[i] = c.Content
+	}
+
+	s.mu.Lock()
+	s.contexts = contents
+	s.mu.Unlock()
+
+	if encoded, err := json.Marshal(chunks); err == nil {
+		s.SetMetadata(metadataRAGContexts, string(encoded))
+	}
+}
@@ -0,0 +1,58 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestRecordRAGContextsSetsContextsAndMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "retrieve", WithType(SpanTypeRAG))
+
+	span.RecordRAGContexts([]RAGChunk{
+		{DocumentID: "doc-1", ChunkID: "chunk-1", Content: "Paris is the capital of France.", Score: 0.91},
+		{DocumentID: "doc-2", ChunkID: "chunk-4", Content: "France is in Western Europe.", Score: 0.77},
+	})
+	span.End()
+
+	record := span.toRecord()
+	if len(record.Contexts) != 2 || record.Contexts[0] != "Paris is the capital of France." {
+		t.Fatalf("unexpected Contexts: %+v", record.Contexts)
+	}
+
+	var chunks []RAGChunk
+	if err := json.Unmarshal([]byte(record.Metadata[metadataRAGContexts]), &chunks); err != nil {
+		t.Fatalf("unmarshal rag_contexts metadata: %v", err)
+	}
+	if len(chunks) != 2 || chunks[1].DocumentID != "doc-2" || chunks[1].Score != 0.77 {
+		t.Fatalf("unexpected chunks: %+v", chunks)
+	}
+}
+
+func TestRecordRAGContextsSkippedWhenCaptureDisabled(t *testing.T) {
+	ctx := WithCaptureDisabled(context.Background())
+	ctx, _ = NewTrace(ctx)
+	_, span := StartSpan(ctx, "retrieve", WithType(SpanTypeRAG))
+
+	span.RecordRAGContexts([]RAGChunk{{DocumentID: "doc-1", Content: "Paris is the capital of France."}})
+	span.End()
+
+	record := span.toRecord()
+	if len(record.Contexts) != 0 {
+		t.Fatalf("expected no Contexts when capture is disabled, got %+v", record.Contexts)
+	}
+	if _, ok := record.Metadata[metadataRAGContexts]; ok {
+		t.Fatal("expected RecordRAGContexts to be skipped when capture is disabled")
+	}
+}
+
+func TestRecordRAGContextsOnNoopSpanDoesNotPanic(t *testing.T) {
+	Disable()
+	defer disabled.Store(false)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "retrieve")
+	span.RecordRAGContexts([]RAGChunk{{Content: "x"}})
+	span.End()
+}
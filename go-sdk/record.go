@@ -0,0 +1,74 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+)
+
+// RecordInput records input on the span found in ctx, if any. It is a
+// convenience wrapper around Span.RecordInput for call sites that only have
+// a context, and is a no-op (aside from the capture check) when ctx carries
+// no span.
+func RecordInput(ctx context.Context, value TypedValue) {
+	if publishIfCaptureDisabled(ctx) {
+		return
+	}
+	if span, ok := SpanFromContext(ctx); ok {
+		span.RecordInput(value)
+	}
+}
+
+// RecordOutput records output on the span found in ctx, if any.
+func RecordOutput(ctx context.Context, value TypedValue) {
+	if publishIfCaptureDisabled(ctx) {
+		return
+	}
+	if span, ok := SpanFromContext(ctx); ok {
+		span.RecordOutput(value)
+	}
+}
+
+// SetTraceMetadata merges kv into the metadata of the trace found in ctx,
+// distinct from any individual span's Span.SetMetadata. It returns an error
+// if ctx carries no trace, or if a value in kv can't be JSON-encoded - see
+// Trace.SetMetadata.
+func SetTraceMetadata(ctx context.Context, kv map[string]any) error {
+	trace, ok := TraceFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("langwatch: no trace in context to set metadata on")
+	}
+	return trace.SetMetadata(kv)
+}
+
+// RecordTraceInput sets the top-level input of the trace found in ctx,
+// distinct from RecordInput's per-span input. It respects the same capture
+// kill switch as RecordInput/RecordOutput, and is a no-op when ctx carries
+// no trace.
+func RecordTraceInput(ctx context.Context, value TypedValue) {
+	if publishIfCaptureDisabled(ctx) {
+		return
+	}
+	if trace, ok := TraceFromContext(ctx); ok {
+		trace.RecordInput(value)
+	}
+}
+
+// RecordTraceOutput sets the top-level output of the trace found in ctx,
+// distinct from RecordOutput's per-span output; see RecordTraceInput.
+func RecordTraceOutput(ctx context.Context, value TypedValue) {
+	if publishIfCaptureDisabled(ctx) {
+		return
+	}
+	if trace, ok := TraceFromContext(ctx); ok {
+		trace.RecordOutput(value)
+	}
+}
+
+// RecordError records an error on the span found in ctx, if any. Unlike
+// RecordInput/RecordOutput this proceeds even when capture is disabled - see
+// Span.RecordError.
+func RecordError(ctx context.Context, err ErrorCapture) {
+	if span, ok := SpanFromContext(ctx); ok {
+		span.RecordError(err)
+	}
+}
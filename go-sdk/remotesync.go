@@ -0,0 +1,234 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// remoteConfigPath is the LangWatch endpoint SDKs poll for project-level
+// directives (sampling rate, capture toggles, denied attributes, kill
+// switch), mirroring the collector's own POST /api/collector convention.
+const remoteConfigPath = "/api/sdk/config"
+
+// Metadata keys set by RecordCacheStatus.
+const (
+	metadataRemoteConfigStale    = "langwatch.remote_config.stale"
+	metadataRemoteConfigCachedAt = "langwatch.remote_config.cached_at"
+)
+
+// RemoteSyncerOption configures a RemoteSyncer built with NewRemoteSyncer.
+type RemoteSyncerOption func(*RemoteSyncer)
+
+// WithDiskCache persists every successfully fetched Policy to path, and
+// falls back to it - instead of DefaultPolicy - when LangWatch is
+// unreachable at startup or during a poll. This keeps a service running
+// with its last-known-good sampling/capture/deny-list configuration through
+// an outage, rather than silently reverting to defaults.
+func WithDiskCache(path string) RemoteSyncerOption {
+	return func(rs *RemoteSyncer) { rs.cachePath = path }
+}
+
+// diskCachedPolicy is the envelope persisted by WithDiskCache, recording
+// when the policy was fetched so staleness can be reported on spans that
+// used it.
+type diskCachedPolicy struct {
+	Policy    Policy    `json:"policy"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// RemoteSyncer periodically pulls project-level SDK directives from
+// LangWatch and exposes them as a Policy via Current. Pass it to
+// SetActivePolicy for sampling/capture/deny-list changes made in the
+// LangWatch UI to actually take effect without redeploying - by itself a
+// RemoteSyncer only fetches and caches, the same way PolicyWatcher only
+// parses and hot-reloads a file. It uses ETag caching so unchanged config
+// costs a 304 rather than a full payload on every poll.
+type RemoteSyncer struct {
+	config    Config
+	client    *http.Client
+	interval  time.Duration
+	cachePath string
+
+	mu   sync.Mutex
+	etag string
+
+	current  atomic.Pointer[Policy]
+	fromDisk atomic.Bool
+	cachedAt atomic.Pointer[time.Time]
+	done     chan struct{}
+}
+
+// NewRemoteSyncer performs an initial synchronous fetch against cfg's
+// endpoint and then polls every interval in the background until Close is
+// called. If the initial fetch fails, the WithDiskCache policy (if any and
+// if present on disk) is used instead of DefaultPolicy, and the first
+// background poll retries the live fetch.
+func NewRemoteSyncer(cfg Config, interval time.Duration, opts ...RemoteSyncerOption) *RemoteSyncer {
+	rs := &RemoteSyncer{
+		config:   cfg.withDefaults(),
+		client:   http.DefaultClient,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(rs)
+	}
+	policy := DefaultPolicy
+	rs.current.Store(&policy)
+
+	if p, _, err := rs.fetch(context.Background()); err == nil {
+		rs.current.Store(&p)
+		rs.persistToDisk(p)
+	} else {
+		debugLog("remote sync initial fetch failed, using default policy: %v", err)
+		if cached, ok := rs.loadFromDisk(); ok {
+			debugLog("remote sync falling back to disk-cached policy from %s", cached.FetchedAt)
+			rs.current.Store(&cached.Policy)
+			rs.fromDisk.Store(true)
+			fetchedAt := cached.FetchedAt
+			rs.cachedAt.Store(&fetchedAt)
+		}
+	}
+
+	go rs.run()
+	return rs
+}
+
+// Current returns the most recently synced policy.
+func (rs *RemoteSyncer) Current() Policy {
+	return *rs.current.Load()
+}
+
+// RecordCacheStatus records, on the span found in ctx, whether Current is
+// currently serving a disk-cached policy rather than one freshly fetched
+// from LangWatch, and if so how old it is - so traces produced while
+// running on stale config are visible as such.
+func (rs *RemoteSyncer) RecordCacheStatus(ctx context.Context) {
+	if !rs.fromDisk.Load() {
+		return
+	}
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	span.SetMetadata(metadataRemoteConfigStale, "true")
+	if cachedAt := rs.cachedAt.Load(); cachedAt != nil {
+		span.SetMetadata(metadataRemoteConfigCachedAt, cachedAt.Format(time.RFC3339))
+	}
+}
+
+// persistToDisk saves policy as the disk cache, if WithDiskCache was
+// configured. A write failure is logged, not returned: a stale cache write
+// failing shouldn't fail the fetch that just succeeded.
+func (rs *RemoteSyncer) persistToDisk(policy Policy) {
+	if rs.cachePath == "" {
+		return
+	}
+	body, err := json.Marshal(diskCachedPolicy{Policy: policy, FetchedAt: time.Now()})
+	if err != nil {
+		debugLog("remote sync: marshal disk cache: %v", err)
+		return
+	}
+	if err := os.WriteFile(rs.cachePath, body, 0o644); err != nil {
+		debugLog("remote sync: write disk cache %s: %v", rs.cachePath, err)
+	}
+}
+
+// loadFromDisk reads back a policy persisted by persistToDisk.
+func (rs *RemoteSyncer) loadFromDisk() (diskCachedPolicy, bool) {
+	if rs.cachePath == "" {
+		return diskCachedPolicy{}, false
+	}
+	body, err := os.ReadFile(rs.cachePath)
+	if err != nil {
+		return diskCachedPolicy{}, false
+	}
+	var cached diskCachedPolicy
+	if err := json.Unmarshal(body, &cached); err != nil {
+		debugLog("remote sync: parse disk cache %s: %v", rs.cachePath, err)
+		return diskCachedPolicy{}, false
+	}
+	if err := cached.Policy.validate(); err != nil {
+		debugLog("remote sync: disk cache %s failed validation: %v", rs.cachePath, err)
+		return diskCachedPolicy{}, false
+	}
+	return cached, true
+}
+
+// Close stops the background poll loop.
+func (rs *RemoteSyncer) Close() {
+	close(rs.done)
+}
+
+func (rs *RemoteSyncer) run() {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rs.done:
+			return
+		case <-ticker.C:
+			policy, changed, err := rs.fetch(context.Background())
+			if err != nil {
+				debugLog("remote sync poll failed, keeping previous policy: %v", err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			rs.current.Store(&policy)
+			rs.fromDisk.Store(false)
+			rs.persistToDisk(policy)
+			publish(context.Background(), Event{Type: EventPolicyReloaded, Reason: "remote sync"})
+		}
+	}
+}
+
+// fetch requests the current policy, returning changed=false on a 304 (the
+// cached policy is still current).
+func (rs *RemoteSyncer) fetch(ctx context.Context) (Policy, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rs.config.Endpoint+remoteConfigPath, nil)
+	if err != nil {
+		return Policy{}, false, fmt.Errorf("langwatch: build remote config request: %w", err)
+	}
+	req.Header.Set("X-Auth-Token", rs.config.APIKey)
+
+	rs.mu.Lock()
+	if rs.etag != "" {
+		req.Header.Set("If-None-Match", rs.etag)
+	}
+	rs.mu.Unlock()
+
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return Policy{}, false, fmt.Errorf("langwatch: fetch remote config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return Policy{}, false, nil
+	}
+	if resp.StatusCode >= 300 {
+		return Policy{}, false, &ExportError{Status: resp.StatusCode}
+	}
+
+	policy := DefaultPolicy
+	if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+		return Policy{}, false, fmt.Errorf("langwatch: decode remote config: %w", err)
+	}
+	if err := policy.validate(); err != nil {
+		return Policy{}, false, err
+	}
+
+	rs.mu.Lock()
+	rs.etag = resp.Header.Get("ETag")
+	rs.mu.Unlock()
+
+	return policy, true, nil
+}
@@ -0,0 +1,155 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRemoteSyncerFetchesInitialPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(Policy{SampleRate: 0.5})
+	}))
+	defer server.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: server.URL}, time.Hour)
+	defer rs.Close()
+
+	if rs.Current().SampleRate != 0.5 {
+		t.Fatalf("expected initial sync to apply sample_rate 0.5, got %v", rs.Current().SampleRate)
+	}
+}
+
+func TestRemoteSyncerHonorsETag(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(Policy{SampleRate: 1})
+	}))
+	defer server.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: server.URL}, time.Hour)
+	defer rs.Close()
+
+	if _, changed, err := rs.fetch(context.Background()); err != nil || changed {
+		t.Fatalf("expected a 304 to report changed=false, got changed=%v err=%v", changed, err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests (initial + explicit fetch), got %d", requests)
+	}
+}
+
+func TestRemoteSyncerPersistsFetchedPolicyToDisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Policy{SampleRate: 0.25})
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "policy.json")
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: server.URL}, time.Hour, WithDiskCache(cachePath))
+	defer rs.Close()
+
+	cached, ok := rs.loadFromDisk()
+	if !ok {
+		t.Fatal("expected a successful fetch to persist the policy to disk")
+	}
+	if cached.Policy.SampleRate != 0.25 {
+		t.Fatalf("cached SampleRate = %v, want 0.25", cached.Policy.SampleRate)
+	}
+}
+
+func TestRemoteSyncerFallsBackToDiskCacheWhenUnreachable(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "policy.json")
+	seedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Policy{SampleRate: 0.75})
+	}))
+	seed := NewRemoteSyncer(Config{APIKey: "key", Endpoint: seedServer.URL}, time.Hour, WithDiskCache(cachePath))
+	seed.Close()
+	seedServer.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: "http://127.0.0.1:0"}, time.Hour, WithDiskCache(cachePath))
+	defer rs.Close()
+
+	if rs.Current().SampleRate != 0.75 {
+		t.Fatalf("expected fallback to disk-cached sample_rate 0.75, got %v", rs.Current().SampleRate)
+	}
+	if !rs.fromDisk.Load() {
+		t.Fatal("expected fromDisk to be true when serving the disk-cached policy")
+	}
+}
+
+func TestRecordCacheStatusMarksSpanWhenServingFromDisk(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "policy.json")
+	seedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Policy{SampleRate: 0.9})
+	}))
+	seed := NewRemoteSyncer(Config{APIKey: "key", Endpoint: seedServer.URL}, time.Hour, WithDiskCache(cachePath))
+	seed.Close()
+	seedServer.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: "http://127.0.0.1:0"}, time.Hour, WithDiskCache(cachePath))
+	defer rs.Close()
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "op")
+	rs.RecordCacheStatus(ctx)
+	span.End()
+
+	if span.metadata[metadataRemoteConfigStale] != "true" {
+		t.Fatalf("metadata[%s] = %q, want %q", metadataRemoteConfigStale, span.metadata[metadataRemoteConfigStale], "true")
+	}
+	if span.metadata[metadataRemoteConfigCachedAt] == "" {
+		t.Fatalf("expected metadata[%s] to be set", metadataRemoteConfigCachedAt)
+	}
+}
+
+func TestRecordCacheStatusNoOpWhenServingLivePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Policy{SampleRate: 1})
+	}))
+	defer server.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: server.URL}, time.Hour)
+	defer rs.Close()
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "op")
+	rs.RecordCacheStatus(ctx)
+	span.End()
+
+	if _, ok := span.metadata[metadataRemoteConfigStale]; ok {
+		t.Fatalf("expected no %s metadata when serving a live policy", metadataRemoteConfigStale)
+	}
+}
+
+func TestRemoteSyncerDrivesCaptureOnceRegisteredAsActivePolicy(t *testing.T) {
+	resetActivePolicy(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Policy{SampleRate: 1, CaptureDisabled: true})
+	}))
+	defer server.Close()
+
+	rs := NewRemoteSyncer(Config{APIKey: "key", Endpoint: server.URL}, time.Hour)
+	defer rs.Close()
+
+	if !CaptureEnabled(context.Background()) {
+		t.Fatal("policy fetched by RemoteSyncer should have no effect before SetActivePolicy is called")
+	}
+
+	SetActivePolicy(rs)
+
+	if CaptureEnabled(context.Background()) {
+		t.Fatal("expected CaptureDisabled from the synced remote policy to disable capture once registered")
+	}
+}
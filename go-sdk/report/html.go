@@ -0,0 +1,64 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// HTML renders req as a standalone HTML document with the same content as
+// Markdown, for pasting into tools that don't render Markdown.
+func HTML(req langwatch.CollectorRequest) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Trace ")
+	b.WriteString(html.EscapeString(req.TraceID))
+	b.WriteString("</title></head><body>\n")
+	fmt.Fprintf(&b, "<h1>Trace %s</h1>\n", html.EscapeString(req.TraceID))
+	writeMetaHTML(&b, req)
+
+	s := summarize(req.Spans)
+	fmt.Fprintf(&b, "<p><strong>Tokens:</strong> %d prompt + %d completion = %d total<br>\n",
+		s.promptTokens, s.completionTokens, s.promptTokens+s.completionTokens)
+	fmt.Fprintf(&b, "<strong>Cost:</strong> $%.4f</p>\n", s.cost)
+
+	b.WriteString("<h2>Conversation</h2>\n")
+	for _, span := range req.Spans {
+		writeSpanHTML(&b, span)
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+func writeMetaHTML(b *strings.Builder, req langwatch.CollectorRequest) {
+	b.WriteString("<ul>\n")
+	if req.UserID != "" {
+		fmt.Fprintf(b, "<li><strong>User:</strong> %s</li>\n", html.EscapeString(req.UserID))
+	}
+	if req.ThreadID != "" {
+		fmt.Fprintf(b, "<li><strong>Thread:</strong> %s</li>\n", html.EscapeString(req.ThreadID))
+	}
+	if len(req.Labels) > 0 {
+		fmt.Fprintf(b, "<li><strong>Labels:</strong> %s</li>\n", html.EscapeString(strings.Join(req.Labels, ", ")))
+	}
+	b.WriteString("</ul>\n")
+}
+
+func writeSpanHTML(b *strings.Builder, span langwatch.SpanRecord) {
+	fmt.Fprintf(b, "<h3>%s (%s)</h3>\n", html.EscapeString(spanTitle(span)), html.EscapeString(string(span.Type)))
+	if in := text(span.Input); in != "" {
+		fmt.Fprintf(b, "<p><strong>Input:</strong></p>\n<pre>%s</pre>\n", html.EscapeString(in))
+	}
+	for i := range span.Outputs {
+		if out := text(&span.Outputs[i]); out != "" {
+			fmt.Fprintf(b, "<p><strong>Output:</strong></p>\n<pre>%s</pre>\n", html.EscapeString(out))
+		}
+	}
+	if span.Error != nil {
+		fmt.Fprintf(b, "<p><strong>Error:</strong> %s</p>\n", html.EscapeString(span.Error.Message))
+	}
+	if expected := expectedOutputText(span); expected != "" {
+		fmt.Fprintf(b, "<p><strong>Expected output:</strong> %s</p>\n", html.EscapeString(expected))
+	}
+}
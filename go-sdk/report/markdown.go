@@ -0,0 +1,59 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Markdown renders req as a Markdown document: a header with trace
+// metadata, a token/cost summary, and one section per span showing its
+// input, output, and (if recorded) expected output.
+func Markdown(req langwatch.CollectorRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Trace %s\n\n", req.TraceID)
+	writeMetaMarkdown(&b, req)
+
+	s := summarize(req.Spans)
+	fmt.Fprintf(&b, "**Tokens:** %d prompt + %d completion = %d total\n\n",
+		s.promptTokens, s.completionTokens, s.promptTokens+s.completionTokens)
+	fmt.Fprintf(&b, "**Cost:** $%.4f\n\n", s.cost)
+
+	b.WriteString("## Conversation\n\n")
+	for _, span := range req.Spans {
+		writeSpanMarkdown(&b, span)
+	}
+	return b.String()
+}
+
+func writeMetaMarkdown(b *strings.Builder, req langwatch.CollectorRequest) {
+	if req.UserID != "" {
+		fmt.Fprintf(b, "- **User:** %s\n", req.UserID)
+	}
+	if req.ThreadID != "" {
+		fmt.Fprintf(b, "- **Thread:** %s\n", req.ThreadID)
+	}
+	if len(req.Labels) > 0 {
+		fmt.Fprintf(b, "- **Labels:** %s\n", strings.Join(req.Labels, ", "))
+	}
+	b.WriteString("\n")
+}
+
+func writeSpanMarkdown(b *strings.Builder, span langwatch.SpanRecord) {
+	fmt.Fprintf(b, "### %s (%s)\n\n", spanTitle(span), span.Type)
+	if in := text(span.Input); in != "" {
+		fmt.Fprintf(b, "**Input:**\n\n%s\n\n", in)
+	}
+	for i := range span.Outputs {
+		if out := text(&span.Outputs[i]); out != "" {
+			fmt.Fprintf(b, "**Output:**\n\n%s\n\n", out)
+		}
+	}
+	if span.Error != nil {
+		fmt.Fprintf(b, "**Error:** %s\n\n", span.Error.Message)
+	}
+	if expected := expectedOutputText(span); expected != "" {
+		fmt.Fprintf(b, "**Expected output:** %s\n\n", expected)
+	}
+}
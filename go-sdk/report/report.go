@@ -0,0 +1,112 @@
+// Package report renders a captured trace into a shareable Markdown or HTML
+// document showing the conversation, tool calls, and a token/cost summary,
+// so it can be pasted into an incident doc instead of a screenshot of the
+// LangWatch UI.
+//
+// Markdown and HTML work from a langwatch.CollectorRequest, the SDK's existing wire
+// shape for a trace: build one directly from spans still in memory, or from
+// the records a langwatch.LocalStore.Query call returns. This SDK has no
+// evaluation type yet, so evaluation results aren't rendered; the "Expected
+// output" line (from Span.RecordExpectedOutput) is included where present,
+// since that's the closest thing captured today.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// expectedOutputMetadataKey mirrors the unexported metadataExpectedOutput
+// key in the core package: Span.RecordExpectedOutput marshals a TypedValue
+// as JSON under this key since no dedicated wire field exists for it yet.
+const expectedOutputMetadataKey = "langwatch.expected_output"
+
+// tokenSummary aggregates token and cost accounting across every span in a
+// trace that carries Metrics, whatever its type.
+type tokenSummary struct {
+	promptTokens     int
+	completionTokens int
+	cost             float64
+}
+
+func summarize(spans []langwatch.SpanRecord) tokenSummary {
+	var s tokenSummary
+	for _, span := range spans {
+		if span.Metrics == nil {
+			continue
+		}
+		if span.Metrics.PromptTokens != nil {
+			s.promptTokens += *span.Metrics.PromptTokens
+		}
+		if span.Metrics.CompletionTokens != nil {
+			s.completionTokens += *span.Metrics.CompletionTokens
+		}
+		if span.Metrics.Cost != nil {
+			s.cost += *span.Metrics.Cost
+		}
+	}
+	return s
+}
+
+// text extracts the human-readable text of a typed value: plain text as-is,
+// or a chat message's content and tool calls joined into a readable block.
+// Values that have round-tripped through JSON (e.g. from a LocalStore file)
+// decode Value as a generic map rather than a langwatch.ChatMessage; text
+// only recognizes values still in their original Go types.
+func text(value *langwatch.TypedValue) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.Value.(type) {
+	case string:
+		return v
+	case langwatch.ChatMessage:
+		return chatMessageText(v)
+	case []langwatch.ChatMessage:
+		var parts []string
+		for _, m := range v {
+			if t := chatMessageText(m); t != "" {
+				parts = append(parts, t)
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+func chatMessageText(m langwatch.ChatMessage) string {
+	var b strings.Builder
+	if m.Content != nil {
+		b.WriteString(*m.Content)
+	}
+	for _, call := range m.ToolCalls {
+		fmt.Fprintf(&b, "\n[tool call] %s(%s)", call.Function.Name, call.Function.Arguments)
+	}
+	return b.String()
+}
+
+func expectedOutputText(span langwatch.SpanRecord) string {
+	raw, ok := span.Metadata[expectedOutputMetadataKey]
+	if !ok {
+		return ""
+	}
+	var value langwatch.TypedValue
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return ""
+	}
+	if s, ok := value.Value.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func spanTitle(span langwatch.SpanRecord) string {
+	if span.Name != "" {
+		return span.Name
+	}
+	return span.ID
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func sampleRequest() langwatch.CollectorRequest {
+	promptTokens := 10
+	completionTokens := 4
+	cost := 0.0021
+	content := "hi there"
+
+	return langwatch.CollectorRequest{
+		TraceID:  "trace-1",
+		UserID:   "user-1",
+		ThreadID: "thread-1",
+		Labels:   []string{"prod"},
+		Spans: []langwatch.SpanRecord{
+			{
+				ID:   "span-1",
+				Name: "chat",
+				Type: langwatch.SpanTypeLLM,
+				Input: &langwatch.TypedValue{
+					Type:  "chat_messages",
+					Value: []langwatch.ChatMessage{{Role: langwatch.RoleUser, Content: &content}},
+				},
+				Outputs: []langwatch.TypedValue{langwatch.NewTextValue("hello!")},
+				Metrics: &langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens, Cost: &cost},
+				Metadata: map[string]string{
+					"langwatch.expected_output": `{"type":"text","value":"hello!"}`,
+				},
+			},
+		},
+	}
+}
+
+func TestMarkdownIncludesConversationAndSummary(t *testing.T) {
+	out := Markdown(sampleRequest())
+
+	for _, want := range []string{
+		"# Trace trace-1",
+		"**User:** user-1",
+		"10 prompt + 4 completion = 14 total",
+		"$0.0021",
+		"hi there",
+		"hello!",
+		"**Expected output:** hello!",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTMLEscapesContentAndIncludesSummary(t *testing.T) {
+	req := sampleRequest()
+	req.Spans[0].Error = &langwatch.ErrorCapture{Message: "<boom>"}
+	out := HTML(req)
+
+	if !strings.Contains(out, "<h1>Trace trace-1</h1>") {
+		t.Fatalf("expected trace header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "&lt;boom&gt;") {
+		t.Fatalf("expected error message to be escaped, got:\n%s", out)
+	}
+	if strings.Contains(out, "<boom>") {
+		t.Fatalf("expected raw error message not to appear unescaped, got:\n%s", out)
+	}
+}
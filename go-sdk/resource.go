@@ -0,0 +1,58 @@
+package langwatch
+
+import (
+	"sort"
+	"sync"
+)
+
+// SDKName identifies this SDK to the LangWatch backend, mirroring the
+// telemetry.sdk.name resource attribute convention used by OpenTelemetry.
+const SDKName = "langwatch-sdk-go"
+
+// SDKVersion is the current version of this module. It is bumped alongside
+// releases; there is intentionally no VCS-derived value here since this
+// package must remain usable from a `go get` without build info.
+const SDKVersion = "0.1.0"
+
+var (
+	instrumentationsMu sync.Mutex
+	instrumentations   = map[string]bool{}
+)
+
+// RegisterInstrumentation records that an instrumentation package (e.g.
+// "temporal", "openai") is active in this process. Instrumentation
+// sub-packages call this from an init() function so their presence shows up
+// on every exported trace without the user having to configure anything.
+func RegisterInstrumentation(name string) {
+	instrumentationsMu.Lock()
+	defer instrumentationsMu.Unlock()
+	instrumentations[name] = true
+}
+
+// activeInstrumentations returns the sorted names of every instrumentation
+// package registered so far in this process.
+func activeInstrumentations() []string {
+	instrumentationsMu.Lock()
+	defer instrumentationsMu.Unlock()
+	names := make([]string, 0, len(instrumentations))
+	for name := range instrumentations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resourceLabels returns the SDK name/version and active instrumentation
+// packages encoded as labels, the closest equivalent LangWatch traces have
+// to OpenTelemetry resource attributes, so support/debugging can see at a
+// glance which SDK/instrumentation versions produced a trace.
+func resourceLabels() []string {
+	labels := []string{
+		"telemetry.sdk.name:" + SDKName,
+		"telemetry.sdk.version:" + SDKVersion,
+	}
+	for _, name := range activeInstrumentations() {
+		labels = append(labels, "telemetry.instrumentation:"+name)
+	}
+	return labels
+}
@@ -0,0 +1,30 @@
+package langwatch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceLabelsIncludeSDKVersion(t *testing.T) {
+	labels := resourceLabels()
+	found := false
+	for _, l := range labels {
+		if strings.HasPrefix(l, "telemetry.sdk.name:") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an SDK name label, got %v", labels)
+	}
+}
+
+func TestRegisterInstrumentation(t *testing.T) {
+	RegisterInstrumentation("test-instrumentation")
+	labels := resourceLabels()
+	for _, l := range labels {
+		if l == "telemetry.instrumentation:test-instrumentation" {
+			return
+		}
+	}
+	t.Fatalf("expected registered instrumentation label, got %v", labels)
+}
@@ -0,0 +1,102 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// metadataReconciledSpanID marks the supplementary span ReconcileStreamUsage
+// creates with the ID of the original streamed span its usage belongs to,
+// since that span may already have ended (and exported) by the time
+// authoritative usage is available.
+const metadataReconciledSpanID = "langwatch.usage_reconciliation.span_id"
+
+// ResponsesClientOption configures a ResponsesClient built with
+// NewResponsesClient.
+type ResponsesClientOption func(*ResponsesClient)
+
+// WithResponsesHTTPClient overrides the http.Client used for requests.
+func WithResponsesHTTPClient(httpClient *http.Client) ResponsesClientOption {
+	return func(c *ResponsesClient) { c.httpClient = httpClient }
+}
+
+// ResponsesClient fetches a completed response by ID from an
+// OpenAI-compatible Responses API (GET /v1/responses/{id}), to recover
+// authoritative usage for a stream that ended without any - some providers
+// omit usage from the final SSE chunk, particularly under load.
+type ResponsesClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewResponsesClient builds a ResponsesClient authenticated with apiKey
+// against baseURL (e.g. "https://api.openai.com").
+func NewResponsesClient(baseURL, apiKey string, opts ...ResponsesClientOption) *ResponsesClient {
+	c := &ResponsesClient{baseURL: baseURL, apiKey: apiKey, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type responseUsageBody struct {
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// FetchUsage issues the follow-up GET for responseID and returns its
+// authoritative usage as Metrics. It returns an error if the request fails
+// or the response carries no usage.
+func (c *ResponsesClient) FetchUsage(ctx context.Context, responseID string) (Metrics, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/responses/"+url.PathEscape(responseID), nil)
+	if err != nil {
+		return Metrics{}, fmt.Errorf("langwatch: build responses reconciliation request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Metrics{}, fmt.Errorf("langwatch: fetch response %s: %w", responseID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return Metrics{}, &ExportError{Status: resp.StatusCode}
+	}
+
+	var body responseUsageBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Metrics{}, fmt.Errorf("langwatch: decode response %s: %w", responseID, err)
+	}
+	if body.Usage == nil {
+		return Metrics{}, fmt.Errorf("langwatch: response %s carries no usage", responseID)
+	}
+
+	promptTokens := body.Usage.InputTokens
+	completionTokens := body.Usage.OutputTokens
+	return Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens}, nil
+}
+
+// ReconcileStreamUsage fetches authoritative usage for responseID via
+// client and records it on a new supplementary span, tagged with
+// originalSpanID so the reconciled counts can be matched back to the
+// stream they belong to. It's meant to be called after StreamAccumulator's
+// Result reports no usage but a non-empty ResponseID.
+func ReconcileStreamUsage(ctx context.Context, client *ResponsesClient, originalSpanID, responseID string) error {
+	metrics, err := client.FetchUsage(ctx, responseID)
+	if err != nil {
+		return err
+	}
+
+	_, span := StartSpan(ctx, "usage.reconciliation", WithType(SpanTypeLLM))
+	span.SetMetadata(metadataReconciledSpanID, originalSpanID)
+	span.RecordMetrics(metrics)
+	span.End()
+	return nil
+}
@@ -0,0 +1,80 @@
+package langwatch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponsesClientFetchUsageReturnsAuthoritativeTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/responses/resp_123" {
+			t.Fatalf("path = %s, want /v1/responses/resp_123", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Fatalf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"usage":{"input_tokens":12,"output_tokens":34}}`))
+	}))
+	defer server.Close()
+
+	client := NewResponsesClient(server.URL, "test-key")
+	metrics, err := client.FetchUsage(context.Background(), "resp_123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metrics.PromptTokens == nil || *metrics.PromptTokens != 12 {
+		t.Fatalf("PromptTokens = %+v, want 12", metrics.PromptTokens)
+	}
+	if metrics.CompletionTokens == nil || *metrics.CompletionTokens != 34 {
+		t.Fatalf("CompletionTokens = %+v, want 34", metrics.CompletionTokens)
+	}
+}
+
+func TestResponsesClientFetchUsageFailsWithoutUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewResponsesClient(server.URL, "test-key")
+	if _, err := client.FetchUsage(context.Background(), "resp_123"); err == nil {
+		t.Fatal("expected an error when the response carries no usage")
+	}
+}
+
+func TestReconcileStreamUsageRecordsSupplementarySpan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"usage":{"input_tokens":7,"output_tokens":9}}`))
+	}))
+	defer server.Close()
+
+	ctx, _ := NewTrace(context.Background())
+	client := NewResponsesClient(server.URL, "test-key")
+	if err := ReconcileStreamUsage(ctx, client, "original-span-id", "resp_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, span := StartSpan(ctx, "noop")
+	span.End()
+	trace, _ := TraceFromContext(ctx)
+	spans := trace.Spans()
+
+	var reconciliation *Span
+	for _, s := range spans {
+		if s.name == "usage.reconciliation" {
+			reconciliation = s
+		}
+	}
+	if reconciliation == nil {
+		t.Fatal("expected a usage.reconciliation span to be recorded")
+	}
+	record := reconciliation.toRecord()
+	if record.Metadata[metadataReconciledSpanID] != "original-span-id" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataReconciledSpanID, record.Metadata[metadataReconciledSpanID], "original-span-id")
+	}
+	if record.Metrics == nil || record.Metrics.PromptTokens == nil || *record.Metrics.PromptTokens != 7 {
+		t.Fatalf("Metrics = %+v, want PromptTokens=7", record.Metrics)
+	}
+}
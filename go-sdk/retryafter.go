@@ -0,0 +1,125 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metadata keys set by RecordRetryAfter.
+const (
+	metadataRetryAfterSeconds       = "langwatch.retry_after.seconds"
+	metadataRetryAfterResetRequests = "langwatch.retry_after.reset_requests_seconds"
+	metadataRetryAfterResetTokens   = "langwatch.retry_after.reset_tokens_seconds"
+)
+
+// RetryAfterError reports that a call was rejected with 429, carrying how
+// long the provider says to wait before retrying.
+type RetryAfterError struct {
+	// RetryAfter is parsed from the standard Retry-After header (a delay in
+	// seconds, or an HTTP-date).
+	RetryAfter time.Duration
+	// ResetRequests and ResetTokens are provider-specific rate limit
+	// windows - e.g. OpenAI's x-ratelimit-reset-requests and
+	// x-ratelimit-reset-tokens headers - zero when the response didn't set
+	// them.
+	ResetRequests time.Duration
+	ResetTokens   time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("langwatch: rate limited, retry after %s", e.RetryAfter)
+}
+
+// ParseRetryAfter reads the standard Retry-After header plus OpenAI's
+// provider-specific x-ratelimit-reset-requests/x-ratelimit-reset-tokens
+// headers from a 429 response, returning nil if none of them are set.
+func ParseRetryAfter(header http.Header) *RetryAfterError {
+	retryAfter, ok := parseRetryAfterHeader(header.Get("Retry-After"))
+	resetRequests := parseProviderResetHeader(header.Get("x-ratelimit-reset-requests"))
+	resetTokens := parseProviderResetHeader(header.Get("x-ratelimit-reset-tokens"))
+	if !ok && resetRequests == 0 && resetTokens == 0 {
+		return nil
+	}
+	return &RetryAfterError{RetryAfter: retryAfter, ResetRequests: resetRequests, ResetTokens: resetTokens}
+}
+
+func parseRetryAfterHeader(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// parseProviderResetHeader parses OpenAI's rate-limit reset headers, which
+// are formatted as Go-style durations (e.g. "1s", "6m0s").
+func parseProviderResetHeader(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// RecordRetryAfter records e's fields as metadata on the span found in ctx,
+// if any. A no-op when e is nil.
+func RecordRetryAfter(ctx context.Context, e *RetryAfterError) {
+	if e == nil {
+		return
+	}
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	span.SetMetadata(metadataRetryAfterSeconds, formatSeconds(e.RetryAfter))
+	if e.ResetRequests > 0 {
+		span.SetMetadata(metadataRetryAfterResetRequests, formatSeconds(e.ResetRequests))
+	}
+	if e.ResetTokens > 0 {
+		span.SetMetadata(metadataRetryAfterResetTokens, formatSeconds(e.ResetTokens))
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// WithRespectRetryAfter returns a waiter for batch/offline workloads that
+// would rather block until a rate limit clears than retry immediately and
+// get rejected again. The returned function sleeps for e's RetryAfter,
+// capped at maxWait, respecting ctx cancellation; it returns nil
+// immediately when e is nil or its RetryAfter is zero.
+func WithRespectRetryAfter(maxWait time.Duration) func(ctx context.Context, e *RetryAfterError) error {
+	return func(ctx context.Context, e *RetryAfterError) error {
+		if e == nil || e.RetryAfter <= 0 {
+			return nil
+		}
+		wait := e.RetryAfter
+		if wait > maxWait {
+			wait = maxWait
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
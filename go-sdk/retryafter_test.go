@@ -0,0 +1,111 @@
+package langwatch
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfterReadsSecondsAndProviderHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "20")
+	header.Set("x-ratelimit-reset-requests", "1s")
+	header.Set("x-ratelimit-reset-tokens", "6m0s")
+
+	e := ParseRetryAfter(header)
+	if e == nil {
+		t.Fatal("expected a non-nil RetryAfterError")
+	}
+	if e.RetryAfter != 20*time.Second {
+		t.Fatalf("RetryAfter = %v, want 20s", e.RetryAfter)
+	}
+	if e.ResetRequests != time.Second {
+		t.Fatalf("ResetRequests = %v, want 1s", e.ResetRequests)
+	}
+	if e.ResetTokens != 6*time.Minute {
+		t.Fatalf("ResetTokens = %v, want 6m", e.ResetTokens)
+	}
+}
+
+func TestParseRetryAfterReadsHTTPDate(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+
+	e := ParseRetryAfter(header)
+	if e == nil {
+		t.Fatal("expected a non-nil RetryAfterError")
+	}
+	if e.RetryAfter <= 0 || e.RetryAfter > 31*time.Second {
+		t.Fatalf("RetryAfter = %v, want ~30s", e.RetryAfter)
+	}
+}
+
+func TestParseRetryAfterReturnsNilWithoutHeaders(t *testing.T) {
+	if e := ParseRetryAfter(http.Header{}); e != nil {
+		t.Fatalf("expected nil, got %+v", e)
+	}
+}
+
+func TestRecordRetryAfterSetsMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordRetryAfter(ctx, &RetryAfterError{RetryAfter: 15 * time.Second, ResetTokens: 90 * time.Second})
+	span.End()
+
+	if span.metadata[metadataRetryAfterSeconds] != "15" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataRetryAfterSeconds, span.metadata[metadataRetryAfterSeconds], "15")
+	}
+	if span.metadata[metadataRetryAfterResetTokens] != "90" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataRetryAfterResetTokens, span.metadata[metadataRetryAfterResetTokens], "90")
+	}
+	if _, ok := span.metadata[metadataRetryAfterResetRequests]; ok {
+		t.Fatal("expected no reset-requests metadata when ResetRequests is zero")
+	}
+}
+
+func TestRecordRetryAfterNilIsNoOp(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	RecordRetryAfter(ctx, nil)
+	span.End()
+
+	if _, ok := span.metadata[metadataRetryAfterSeconds]; ok {
+		t.Fatalf("expected no retry-after metadata, got %+v", span.metadata)
+	}
+}
+
+func TestWithRespectRetryAfterWaitsCappedAtMaxWait(t *testing.T) {
+	waiter := WithRespectRetryAfter(20 * time.Millisecond)
+
+	start := time.Now()
+	err := waiter(context.Background(), &RetryAfterError{RetryAfter: time.Hour})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("waiter: %v", err)
+	}
+	if elapsed < 20*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Fatalf("elapsed = %v, want ~20ms (capped)", elapsed)
+	}
+}
+
+func TestWithRespectRetryAfterReturnsContextError(t *testing.T) {
+	waiter := WithRespectRetryAfter(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := waiter(ctx, &RetryAfterError{RetryAfter: time.Hour}); err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithRespectRetryAfterNoOpWithoutRetryAfter(t *testing.T) {
+	waiter := WithRespectRetryAfter(time.Hour)
+	if err := waiter(context.Background(), nil); err != nil {
+		t.Fatalf("waiter: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// reviewRequestPath is the LangWatch endpoint for flagging a trace for
+// human review.
+const reviewRequestPath = "/api/review/request"
+
+// metadataReviewRequested marks, on the trace's active span, that a review
+// was requested - a best-effort local annotation in case the network call
+// itself fails partway through the span's lifetime.
+const metadataReviewRequested = "langwatch.review.requested"
+
+// reviewRequestBody is the wire payload for a review request.
+type reviewRequestBody struct {
+	TraceID   string   `json:"trace_id"`
+	Reason    string   `json:"reason"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// ReviewClient files human review requests against traces via the
+// LangWatch review queue API. Named and shaped like TracesClient rather
+// than a package-level singleton, since - like trace updates - it needs an
+// API key and endpoint, and this SDK always threads those through an
+// explicit client rather than global state.
+type ReviewClient struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewReviewClient builds a ReviewClient from cfg, applying the same
+// environment defaults as NewExporter.
+func NewReviewClient(cfg Config) *ReviewClient {
+	return &ReviewClient{config: cfg.withDefaults(), httpClient: http.DefaultClient}
+}
+
+// Request flags the trace found in ctx for human review, tagging it with
+// reason and, if given, the users or teams that should be notified. It's
+// meant for services that can tell a response is low-confidence (a weak
+// retrieval match, a validator failure, an out-of-policy tool call) and
+// want that trace to enter LangWatch's human-in-the-loop queue
+// automatically instead of only being caught by manual spot-checking.
+//
+// Request returns an error if ctx carries no trace (NewTrace/StartSpan
+// weren't called first) or if the collector rejects the request.
+func (c *ReviewClient) Request(ctx context.Context, reason string, assignees ...string) error {
+	trace, ok := TraceFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("langwatch: no trace in context to flag for review")
+	}
+	if span, ok := SpanFromContext(ctx); ok {
+		span.SetMetadata(metadataReviewRequested, "true")
+	}
+
+	body, err := json.Marshal(reviewRequestBody{TraceID: trace.ID(), Reason: reason, Assignees: assignees})
+	if err != nil {
+		return fmt.Errorf("langwatch: marshal review request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+reviewRequestPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("langwatch: build review request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", resolveAPIKey(ctx, c.config.APIKey))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("langwatch: review request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("langwatch: review request rejected, check your API key: %w", ErrUnauthorized)
+	}
+	if resp.StatusCode >= 300 {
+		return &ExportError{Status: resp.StatusCode}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReviewClientRequestSendsTraceIDReasonAndAssignees(t *testing.T) {
+	var got reviewRequestBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/review/request" {
+			t.Fatalf("path = %s, want /api/review/request", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, trace := NewTrace(context.Background())
+	client := NewReviewClient(Config{APIKey: "key", Endpoint: server.URL})
+	if err := client.Request(ctx, "low retrieval confidence", "alice", "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.TraceID != trace.ID() {
+		t.Fatalf("TraceID = %q, want %q", got.TraceID, trace.ID())
+	}
+	if got.Reason != "low retrieval confidence" {
+		t.Fatalf("Reason = %q", got.Reason)
+	}
+	if len(got.Assignees) != 2 || got.Assignees[0] != "alice" || got.Assignees[1] != "bob" {
+		t.Fatalf("Assignees = %v", got.Assignees)
+	}
+}
+
+func TestReviewClientRequestFailsWithoutTraceInContext(t *testing.T) {
+	client := NewReviewClient(Config{APIKey: "key", Endpoint: "http://example.invalid"})
+	if err := client.Request(context.Background(), "reason"); err == nil {
+		t.Fatal("expected an error when ctx carries no trace")
+	}
+}
+
+func TestReviewClientRequestRejectsUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ctx, _ := NewTrace(context.Background())
+	client := NewReviewClient(Config{APIKey: "bad-key", Endpoint: server.URL})
+	err := client.Request(ctx, "reason")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestReviewClientRequestMarksSpanMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "answer")
+	client := NewReviewClient(Config{APIKey: "key", Endpoint: server.URL})
+	if err := client.Request(ctx, "reason"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	record := span.toRecord()
+	if record.Metadata[metadataReviewRequested] != "true" {
+		t.Fatalf("expected review requested metadata, got %+v", record.Metadata)
+	}
+}
@@ -0,0 +1,65 @@
+package langwatch
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// Metadata keys WithRuntimeMetrics stamps onto a span at End.
+const (
+	metadataRuntimeHeapDelta  = "langwatch.runtime.heap_alloc_delta_bytes"
+	metadataRuntimeGCPauses   = "langwatch.runtime.gc_pause_delta_ns"
+	metadataRuntimeGCCount    = "langwatch.runtime.gc_count_delta"
+	metadataRuntimeGoroutines = "langwatch.runtime.goroutines_delta"
+)
+
+// runtimeSample is a point-in-time snapshot of Go runtime health, used to
+// compute the deltas WithRuntimeMetrics stamps onto a span.
+type runtimeSample struct {
+	heapAlloc  uint64
+	numGC      uint32
+	pauseTotal uint64
+	goroutines int
+}
+
+func sampleRuntime() runtimeSample {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return runtimeSample{
+		heapAlloc:  m.HeapAlloc,
+		numGC:      m.NumGC,
+		pauseTotal: m.PauseTotalNs,
+		goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// WithRuntimeMetrics samples Go runtime health (heap size, GC pause time,
+// goroutine count) at span start and end, recording the deltas as span
+// metadata. Useful for telling provider latency apart from local resource
+// contention (GC pressure, goroutine pileup) in high-load services.
+// runtime.ReadMemStats has a small but non-zero cost, so this is opt-in per
+// span rather than automatic.
+func WithRuntimeMetrics() SpanOption {
+	return func(s *Span) {
+		sample := sampleRuntime()
+		s.runtimeStart = &sample
+	}
+}
+
+// recordRuntimeMetrics must be called with s.mu held, after s.ended has
+// been set, so its heap/goroutine sample reflects the span's own work.
+func (s *Span) recordRuntimeMetrics() {
+	if s.runtimeStart == nil {
+		return
+	}
+	start := *s.runtimeStart
+	end := sampleRuntime()
+
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[metadataRuntimeHeapDelta] = strconv.FormatInt(int64(end.heapAlloc)-int64(start.heapAlloc), 10)
+	s.metadata[metadataRuntimeGCPauses] = strconv.FormatUint(end.pauseTotal-start.pauseTotal, 10)
+	s.metadata[metadataRuntimeGCCount] = strconv.FormatInt(int64(end.numGC)-int64(start.numGC), 10)
+	s.metadata[metadataRuntimeGoroutines] = strconv.Itoa(end.goroutines - start.goroutines)
+}
@@ -0,0 +1,33 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithRuntimeMetricsStampsDeltasOnEnd(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithRuntimeMetrics())
+	span.End()
+
+	for _, key := range []string{
+		metadataRuntimeHeapDelta,
+		metadataRuntimeGCPauses,
+		metadataRuntimeGCCount,
+		metadataRuntimeGoroutines,
+	} {
+		if _, ok := span.metadata[key]; !ok {
+			t.Fatalf("expected metadata key %q to be set", key)
+		}
+	}
+}
+
+func TestWithoutRuntimeMetricsLeavesMetadataUntouched(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.End()
+
+	if _, ok := span.metadata[metadataRuntimeHeapDelta]; ok {
+		t.Fatal("expected no runtime metadata without WithRuntimeMetrics")
+	}
+}
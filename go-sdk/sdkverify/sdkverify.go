@@ -0,0 +1,102 @@
+// Package sdkverify runs a configurable matrix of scenarios against a real
+// LangWatch endpoint and reports whether each one succeeded, so a customer
+// (or CI) can confirm their SDK setup actually gets traces to LangWatch
+// rather than trusting that no error was returned locally.
+package sdkverify
+
+import (
+	"context"
+	"fmt"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Scenario is one exercise of the SDK against a real exporter.
+type Scenario struct {
+	Name string
+	Run  func(ctx context.Context, exporter langwatch.Exporter) error
+}
+
+// Verifier confirms, server-side, that a trace produced by a Scenario
+// actually arrived with the expected shape. It's optional: RunMatrix skips
+// verification for a nil Verifier and only reports local export errors.
+type Verifier interface {
+	Verify(ctx context.Context, traceID string) error
+}
+
+// Result is the outcome of running a single Scenario.
+type Result struct {
+	Scenario string
+	Err      error
+}
+
+// Passed reports whether the scenario completed without a local export or
+// remote verification error.
+func (r Result) Passed() bool { return r.Err == nil }
+
+// RunMatrix runs every scenario in scenarios in order against exporter,
+// optionally confirming server-side arrival through verifier, and returns
+// one Result per scenario.
+func RunMatrix(ctx context.Context, exporter langwatch.Exporter, verifier Verifier, scenarios []Scenario) []Result {
+	results := make([]Result, 0, len(scenarios))
+	for _, sc := range scenarios {
+		results = append(results, Result{Scenario: sc.Name, Err: runOne(ctx, exporter, verifier, sc)})
+	}
+	return results
+}
+
+func runOne(ctx context.Context, exporter langwatch.Exporter, verifier Verifier, sc Scenario) error {
+	ctx, trace := langwatch.NewTrace(ctx)
+	if err := sc.Run(ctx, exporter); err != nil {
+		return fmt.Errorf("scenario %q: %w", sc.Name, err)
+	}
+	if verifier == nil {
+		return nil
+	}
+	if err := verifier.Verify(ctx, trace.ID()); err != nil {
+		return fmt.Errorf("scenario %q: verify trace %s: %w", sc.Name, trace.ID(), err)
+	}
+	return nil
+}
+
+// DefaultScenarios returns a small matrix covering the SDK's basic shapes:
+// a plain span, an LLM span with metrics, and a span that records an error.
+func DefaultScenarios() []Scenario {
+	return []Scenario{
+		{
+			Name: "plain-span",
+			Run: func(ctx context.Context, exporter langwatch.Exporter) error {
+				trace, _ := langwatch.TraceFromContext(ctx)
+				ctx, span := langwatch.StartSpan(ctx, "sdkverify.plain-span")
+				span.RecordOutput(langwatch.NewTextValue("ok"))
+				span.End()
+				return exporter.Export(ctx, trace)
+			},
+		},
+		{
+			Name: "llm-span-with-metrics",
+			Run: func(ctx context.Context, exporter langwatch.Exporter) error {
+				trace, _ := langwatch.TraceFromContext(ctx)
+				ctx, span := langwatch.StartSpan(ctx, "sdkverify.llm-span", langwatch.WithType(langwatch.SpanTypeLLM), langwatch.WithModel("openai", "gpt-4o-mini"))
+				span.RecordInput(langwatch.NewChatMessagesValue([]langwatch.ChatMessage{{Role: langwatch.RoleUser, Content: strPtr("ping")}}))
+				span.RecordOutput(langwatch.NewTextValue("pong"))
+				promptTokens, completionTokens := 1, 1
+				span.RecordMetrics(langwatch.Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens})
+				span.End()
+				return exporter.Export(ctx, trace)
+			},
+		},
+		{
+			Name: "errored-span",
+			Run: func(ctx context.Context, exporter langwatch.Exporter) error {
+				trace, _ := langwatch.TraceFromContext(ctx)
+				ctx, span := langwatch.StartSpan(ctx, "sdkverify.errored-span")
+				span.RecordError(langwatch.ErrorCapture{Message: "sdkverify: simulated failure"})
+				span.End()
+				return exporter.Export(ctx, trace)
+			},
+		},
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,36 @@
+package sdkverify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type noopExporter struct{}
+
+func (noopExporter) Export(ctx context.Context, trace *langwatch.Trace) error { return nil }
+
+type failingVerifier struct{ err error }
+
+func (f failingVerifier) Verify(ctx context.Context, traceID string) error { return f.err }
+
+func TestRunMatrixAllPass(t *testing.T) {
+	results := RunMatrix(context.Background(), noopExporter{}, nil, DefaultScenarios())
+	for _, r := range results {
+		if !r.Passed() {
+			t.Fatalf("expected scenario %q to pass, got %v", r.Scenario, r.Err)
+		}
+	}
+}
+
+func TestRunMatrixReportsVerifyFailure(t *testing.T) {
+	verifier := failingVerifier{err: errors.New("trace never arrived")}
+	results := RunMatrix(context.Background(), noopExporter{}, verifier, DefaultScenarios())
+	for _, r := range results {
+		if r.Passed() {
+			t.Fatalf("expected scenario %q to fail verification", r.Scenario)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package langwatch
+
+import "time"
+
+// Metadata keys used to record OpenAI-style service tier information. The
+// wire schema has no dedicated field for this yet, so it rides on the same
+// SDK-side Metadata extension point as hedging attempts.
+const (
+	metadataServiceTierRequested = "langwatch.service_tier.requested"
+	metadataServiceTierActual    = "langwatch.service_tier.actual"
+	metadataLatencyClass         = "langwatch.latency_class"
+)
+
+// Latency class boundaries used by RecordServiceTier's automatic
+// classification. Deliberately coarse: this is for eyeballing cost/latency
+// tradeoffs across tiers, not precise SLO tracking (see WithSLO for that).
+const (
+	latencyClassFastMax   = time.Second
+	latencyClassNormalMax = 5 * time.Second
+)
+
+// RecordServiceTier records the OpenAI service_tier requested (e.g. "flex",
+// "priority", "default") and the one the response actually used - they can
+// differ when a requested tier isn't honored - alongside a coarse latency
+// class derived from the span's duration so far, letting cost/latency
+// tradeoffs across tiers be compared inside LangWatch without any
+// backend-side changes.
+func (s *Span) RecordServiceTier(requested, actual string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	if requested != "" {
+		s.metadata[metadataServiceTierRequested] = requested
+	}
+	if actual != "" {
+		s.metadata[metadataServiceTierActual] = actual
+	}
+	s.metadata[metadataLatencyClass] = latencyClass(time.Since(s.startedAt))
+}
+
+func latencyClass(d time.Duration) string {
+	switch {
+	case d <= latencyClassFastMax:
+		return "fast"
+	case d <= latencyClassNormalMax:
+		return "normal"
+	default:
+		return "slow"
+	}
+}
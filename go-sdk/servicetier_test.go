@@ -0,0 +1,35 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordServiceTierSetsMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	span.RecordServiceTier("flex", "default")
+
+	if span.metadata[metadataServiceTierRequested] != "flex" {
+		t.Fatalf("expected requested tier to be recorded, got %v", span.metadata)
+	}
+	if span.metadata[metadataServiceTierActual] != "default" {
+		t.Fatalf("expected actual tier to be recorded, got %v", span.metadata)
+	}
+	if span.metadata[metadataLatencyClass] != "fast" {
+		t.Fatalf("expected a fast latency class immediately after starting, got %v", span.metadata)
+	}
+}
+
+func TestLatencyClassBuckets(t *testing.T) {
+	cases := map[string]string{
+		"fast":   latencyClass(0),
+		"normal": latencyClass(2 * latencyClassFastMax),
+		"slow":   latencyClass(latencyClassNormalMax * 2),
+	}
+	for want, got := range cases {
+		if want != got {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package shadow
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// Metadata keys Mirror stamps onto a shadow span to quantify how its output
+// differs from the primary response it shadows.
+const (
+	metadataDiffExactMatch       = "langwatch.shadow.diff.exact_match"
+	metadataDiffLengthDelta      = "langwatch.shadow.diff.length_delta"
+	metadataDiffCosineSimilarity = "langwatch.shadow.diff.cosine_similarity"
+)
+
+// Embedder computes embedding vectors for a batch of texts. The
+// embeddings package's Client interface already satisfies this, so an
+// existing embeddings.Client can be passed straight to WithEmbedder.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error)
+}
+
+// recordDiff computes similarity metrics between the primary and shadow
+// outputs and stamps them on span. Cosine similarity is only computed (and
+// only recorded) when an Embedder is configured and both outputs have
+// text.
+func (r *Runner) recordDiff(ctx context.Context, span *langwatch.Span, primaryOutput, shadowOutput langwatch.TypedValue) {
+	primaryText := outputText(primaryOutput)
+	shadowText := outputText(shadowOutput)
+
+	span.SetMetadata(metadataDiffExactMatch, strconv.FormatBool(primaryText == shadowText))
+	span.SetMetadata(metadataDiffLengthDelta, strconv.Itoa(len(shadowText)-len(primaryText)))
+
+	if r.embedder == nil || primaryText == "" || shadowText == "" {
+		return
+	}
+	vectors, _, err := r.embedder.Embed(ctx, []string{primaryText, shadowText})
+	if err != nil || len(vectors) != 2 {
+		return
+	}
+	span.SetMetadata(metadataDiffCosineSimilarity, strconv.FormatFloat(cosineSimilarity(vectors[0], vectors[1]), 'f', 4, 64))
+}
+
+// outputText extracts the plain text of a typed value: text as-is, or a
+// chat message's content joined across messages.
+func outputText(value langwatch.TypedValue) string {
+	switch v := value.Value.(type) {
+	case string:
+		return v
+	case langwatch.ChatMessage:
+		if v.Content != nil {
+			return *v.Content
+		}
+		return ""
+	case []langwatch.ChatMessage:
+		var parts []string
+		for _, m := range v {
+			if m.Content != nil {
+				parts = append(parts, *m.Content)
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
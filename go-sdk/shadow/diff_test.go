@@ -0,0 +1,102 @@
+package shadow
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+type fakeEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, langwatch.Metrics, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = e.vectors[t]
+	}
+	return out, langwatch.Metrics{}, nil
+}
+
+func TestMirrorRecordsExactMatchAndLengthDelta(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	candidate := func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error) {
+		defer wg.Done()
+		return langwatch.NewTextValue("hello world!"), nil
+	}
+
+	runner := NewRunner(1, candidate)
+	ctx, trace := langwatch.NewTrace(context.Background())
+	_, primary := langwatch.StartSpan(ctx, "primary")
+	primary.End()
+
+	runner.Mirror(ctx, primary.ID(), langwatch.NewTextValue("hi"), langwatch.NewTextValue("hello world"))
+	waitTimeout(t, &wg, time.Second)
+
+	rec := shadowRecord(t, ctx, trace)
+	if rec.Metadata[metadataDiffExactMatch] != "false" {
+		t.Fatalf("exact_match = %q, want false", rec.Metadata[metadataDiffExactMatch])
+	}
+	if rec.Metadata[metadataDiffLengthDelta] != "1" {
+		t.Fatalf("length_delta = %q, want 1", rec.Metadata[metadataDiffLengthDelta])
+	}
+	if _, ok := rec.Metadata[metadataDiffCosineSimilarity]; ok {
+		t.Fatal("expected no cosine similarity without an Embedder configured")
+	}
+}
+
+func TestMirrorRecordsCosineSimilarityWithEmbedder(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	candidate := func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error) {
+		defer wg.Done()
+		return langwatch.NewTextValue("shadow text"), nil
+	}
+
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"primary text": {1, 0},
+		"shadow text":  {1, 0},
+	}}
+	runner := NewRunner(1, candidate, WithEmbedder(embedder))
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	_, primary := langwatch.StartSpan(ctx, "primary")
+	primary.End()
+
+	runner.Mirror(ctx, primary.ID(), langwatch.NewTextValue("hi"), langwatch.NewTextValue("primary text"))
+	waitTimeout(t, &wg, time.Second)
+
+	rec := shadowRecord(t, ctx, trace)
+	if rec.Metadata[metadataDiffCosineSimilarity] != "1.0000" {
+		t.Fatalf("cosine_similarity = %q, want 1.0000", rec.Metadata[metadataDiffCosineSimilarity])
+	}
+}
+
+func shadowRecord(t *testing.T, ctx context.Context, trace *langwatch.Trace) langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	for _, m := range matches {
+		if m.Span.Metadata[metadataShadow] == "true" {
+			return m.Span
+		}
+	}
+	t.Fatal("expected a shadow span to be recorded")
+	return langwatch.SpanRecord{}
+}
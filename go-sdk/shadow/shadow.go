@@ -0,0 +1,114 @@
+// Package shadow mirrors a percentage of production requests to a
+// candidate model or prompt on a background goroutine, recording its
+// result as a span on the same trace as the primary call, without ever
+// affecting the user-facing response. It's meant for measuring a model
+// upgrade against real traffic in LangWatch before switching production
+// over to it.
+package shadow
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+// metadataShadow marks a span as a shadow (candidate) call rather than the
+// primary one that produced the user-facing response.
+const metadataShadow = "langwatch.shadow"
+
+// metadataShadowOf records the ID of the primary span a shadow span
+// mirrors, so the two can be correlated in LangWatch.
+const metadataShadowOf = "langwatch.shadow.primary_span"
+
+// Candidate calls the model/prompt under evaluation with input, returning
+// its output the same way the primary call would.
+type Candidate func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error)
+
+// RunnerOption configures a Runner built with NewRunner.
+type RunnerOption func(*Runner)
+
+// WithSpanName sets the name recorded on shadow spans. Defaults to
+// "shadow".
+func WithSpanName(name string) RunnerOption {
+	return func(r *Runner) { r.spanName = name }
+}
+
+// WithEmbedder enables embedding-cosine similarity in the diff metrics
+// Mirror records, using embedder to embed the primary and shadow outputs.
+// Any embeddings.Client already satisfies this interface. Without it, only
+// the exact-match and length-delta metrics are recorded.
+func WithEmbedder(embedder Embedder) RunnerOption {
+	return func(r *Runner) { r.embedder = embedder }
+}
+
+// Runner mirrors a fraction of calls to a candidate, off the request path.
+type Runner struct {
+	rate      float64
+	candidate Candidate
+	spanName  string
+	embedder  Embedder
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRunner creates a Runner that mirrors the given fraction (0-1) of
+// Mirror calls to candidate.
+func NewRunner(rate float64, candidate Candidate, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		rate:      rate,
+		candidate: candidate,
+		spanName:  "shadow",
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Runner) sampled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64() < r.rate
+}
+
+// Mirror asynchronously calls the candidate with input, unless this call is
+// not sampled, in which case it does nothing. It never blocks the caller
+// and never returns the candidate's result: the candidate's span is
+// recorded on the trace found in ctx, correlated to primarySpanID, so it
+// shows up in LangWatch next to the primary call it shadows. primaryOutput
+// is the response already sent to the user, used to compute the diff
+// metrics recorded alongside the candidate's own output.
+//
+// The candidate's span is added to the in-memory Trace on a goroutine that
+// may still be running when the primary request returns; callers should
+// export the trace after the response has been sent (e.g. via
+// BatchProcessor, or a short grace period) rather than exporting
+// synchronously before Mirror's goroutine has had a chance to finish.
+func (r *Runner) Mirror(ctx context.Context, primarySpanID string, input, primaryOutput langwatch.TypedValue) {
+	if !r.sampled() {
+		return
+	}
+
+	go func() {
+		defer func() { recover() }()
+
+		spanCtx, span := langwatch.StartSpan(ctx, r.spanName, langwatch.WithType(langwatch.SpanTypeLLM))
+		span.SetMetadata(metadataShadow, "true")
+		span.SetMetadata(metadataShadowOf, primarySpanID)
+		span.RecordInput(input)
+
+		output, err := r.candidate(spanCtx, input)
+		if err != nil {
+			span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		} else {
+			span.RecordOutput(output)
+			r.recordDiff(spanCtx, span, primaryOutput, output)
+		}
+		span.End()
+	}()
+}
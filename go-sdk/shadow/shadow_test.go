@@ -0,0 +1,132 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func TestMirrorRecordsShadowSpanLinkedToPrimary(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	candidate := func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error) {
+		defer wg.Done()
+		return langwatch.NewTextValue("candidate output"), nil
+	}
+
+	runner := NewRunner(1, candidate)
+
+	ctx, trace := langwatch.NewTrace(context.Background())
+	_, primary := langwatch.StartSpan(ctx, "primary")
+	primary.End()
+
+	runner.Mirror(ctx, primary.ID(), langwatch.NewTextValue("hi"), langwatch.NewTextValue("primary output"))
+	waitTimeout(t, &wg, time.Second)
+
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+
+	var shadowSpan *langwatch.LocalStoreRecord
+	for i := range matches {
+		if matches[i].Span.Metadata[metadataShadow] == "true" {
+			shadowSpan = &matches[i]
+		}
+	}
+	if shadowSpan == nil {
+		t.Fatal("expected a shadow span to be recorded")
+	}
+	if shadowSpan.Span.Metadata[metadataShadowOf] != primary.ID() {
+		t.Fatalf("shadow span linked to %q, want %q", shadowSpan.Span.Metadata[metadataShadowOf], primary.ID())
+	}
+}
+
+func TestMirrorRecordsCandidateError(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	candidate := func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error) {
+		defer wg.Done()
+		return langwatch.TypedValue{}, errors.New("candidate failed")
+	}
+
+	runner := NewRunner(1, candidate)
+	ctx, trace := langwatch.NewTrace(context.Background())
+	_, primary := langwatch.StartSpan(ctx, "primary")
+	primary.End()
+
+	runner.Mirror(ctx, primary.ID(), langwatch.NewTextValue("hi"), langwatch.NewTextValue("primary output"))
+	waitTimeout(t, &wg, time.Second)
+
+	store, err := langwatch.OpenLocalStore(filepath.Join(t.TempDir(), "traces.ndjson"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(ctx, trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+
+	found := false
+	for _, m := range matches {
+		if m.Span.Metadata[metadataShadow] == "true" {
+			found = true
+			if m.Span.Error == nil || m.Span.Error.Message != "candidate failed" {
+				t.Fatalf("expected shadow span error to be recorded, got %+v", m.Span.Error)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a shadow span to be recorded")
+	}
+}
+
+func TestMirrorSkipsCandidateAtZeroRate(t *testing.T) {
+	called := false
+	candidate := func(ctx context.Context, input langwatch.TypedValue) (langwatch.TypedValue, error) {
+		called = true
+		return langwatch.TypedValue{}, nil
+	}
+
+	runner := NewRunner(0, candidate)
+	ctx, _ := langwatch.NewTrace(context.Background())
+	runner.Mirror(ctx, "primary-1", langwatch.NewTextValue("hi"), langwatch.NewTextValue("primary output"))
+
+	time.Sleep(10 * time.Millisecond)
+	if called {
+		t.Fatal("expected candidate not to be called at rate 0")
+	}
+}
+
+func waitTimeout(t *testing.T, wg *sync.WaitGroup, timeout time.Duration) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for candidate to run")
+	}
+	// give the goroutine time to finish recording the span after wg.Done.
+	time.Sleep(20 * time.Millisecond)
+}
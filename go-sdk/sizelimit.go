@@ -0,0 +1,102 @@
+package langwatch
+
+import "encoding/json"
+
+// defaultMaxSpanSize is a conservative default for the collector's
+// per-span payload limit; spans over it are likely to be rejected or
+// truncated server-side anyway.
+const defaultMaxSpanSize = 1 << 20 // 1 MiB
+
+// WithMaxSpanSize sets the serialized size, in bytes, above which a span is
+// considered oversized. Defaults to 1 MiB.
+func WithMaxSpanSize(bytes int) ExporterOption {
+	return func(e *httpExporter) { e.maxSpanSize = bytes }
+}
+
+// WithAutoTruncateOversizedSpans makes the exporter truncate a span's
+// largest attribute (input, outputs or raw_response) instead of just
+// warning, so an oversized span still reaches the collector instead of
+// being silently rejected. Input and output values are cut only when they
+// are strings; other JSON values are left as they are.
+func WithAutoTruncateOversizedSpans() ExporterOption {
+	return func(e *httpExporter) { e.autoTruncateOversized = true }
+}
+
+// attributeSize is a named attribute's serialized byte size, used to report
+// which attribute is responsible for an oversized span.
+type attributeSize struct {
+	name  string
+	bytes int
+}
+
+// checkSpanSize measures record's serialized size and, if it exceeds
+// maxSize, logs a warning naming the largest attribute; if truncate is
+// set, it also shrinks that attribute (a string input or output is cut
+// down to fit, raw_response is replaced by a placeholder, a non-string
+// value is left as is) and returns its name. It returns nil when the span
+// is within maxSize or truncate is unset.
+func checkSpanSize(record *SpanRecord, maxSize int, truncate bool) []string {
+	sizes := []attributeSize{
+		{"input", jsonSize(record.Input)},
+		{"outputs", jsonSize(record.Outputs)},
+		{"raw_response", jsonSize(record.RawResponse)},
+	}
+
+	total := 0
+	largest := sizes[0]
+	for _, s := range sizes {
+		total += s.bytes
+		if s.bytes > largest.bytes {
+			largest = s
+		}
+	}
+	if total <= maxSize {
+		return nil
+	}
+
+	debugLog("span %s (%s) is oversized: %d bytes (limit %d), largest attribute is %q at %d bytes",
+		record.ID, record.Name, total, maxSize, largest.name, largest.bytes)
+
+	if !truncate {
+		return nil
+	}
+	switch largest.name {
+	case "input":
+		if record.Input != nil {
+			category := CategoryInput
+			if record.Type == SpanTypeTool {
+				category = CategoryToolArgs
+			}
+			truncateTypedValue(record.Input, maxSize, category)
+		}
+	case "outputs":
+		for i := range record.Outputs {
+			truncateTypedValue(&record.Outputs[i], maxSize/max(1, len(record.Outputs)), CategoryOutput)
+		}
+	case "raw_response":
+		record.RawResponse = "[truncated: oversized raw_response]"
+	}
+	return []string{largest.name}
+}
+
+func truncateTypedValue(v *TypedValue, limit int, category TruncationCategory) {
+	text, ok := v.Value.(string)
+	if !ok || len(text) <= limit {
+		return
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	v.Value = truncationStrategyFor(category)(text, limit)
+}
+
+func jsonSize(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
@@ -0,0 +1,29 @@
+package langwatch
+
+import "testing"
+
+func TestCheckSpanSizeTruncatesInput(t *testing.T) {
+	huge := TypedValue{Type: "text", Value: string(make([]byte, 100))}
+	record := SpanRecord{ID: "span_1", Name: "big", Input: &huge}
+
+	checkSpanSize(&record, 10, true)
+
+	text, ok := record.Input.Value.(string)
+	if !ok {
+		t.Fatalf("expected input to remain a string, got %T", record.Input.Value)
+	}
+	if len(text) >= 100 {
+		t.Fatalf("expected input to be truncated, got %d bytes", len(text))
+	}
+}
+
+func TestCheckSpanSizeLeavesSmallSpansAlone(t *testing.T) {
+	small := TypedValue{Type: "text", Value: "hi"}
+	record := SpanRecord{ID: "span_1", Name: "small", Input: &small}
+
+	checkSpanSize(&record, defaultMaxSpanSize, true)
+
+	if record.Input.Value.(string) != "hi" {
+		t.Fatalf("expected input to be untouched, got %v", record.Input.Value)
+	}
+}
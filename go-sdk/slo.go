@@ -0,0 +1,74 @@
+package langwatch
+
+import "time"
+
+// Metadata keys set when a span violates an SLO declared with WithSLO.
+const (
+	metadataSLOViolated = "langwatch.slo.violated"
+)
+
+// SLOViolationFunc is invoked synchronously from Span.End when a span
+// violates the SLO configured on it via WithSLO.
+type SLOViolationFunc func(span *Span, violated string)
+
+// SLO declares latency budgets for a span. A zero field means "no budget"
+// for that dimension.
+type SLO struct {
+	// TimeToFirstToken is the maximum allowed delay between the span
+	// starting and RecordFirstToken being called.
+	TimeToFirstToken time.Duration
+	// Total is the maximum allowed span duration, start to End.
+	Total time.Duration
+	// OnViolation, if set, is called for every SLO dimension the span
+	// violates. It runs on the goroutine that calls End, so it should be
+	// quick (e.g. incrementing a counter or tripping a circuit breaker) -
+	// LangWatch alert rules are driven by the span attribute regardless of
+	// whether a callback is set.
+	OnViolation SLOViolationFunc
+}
+
+// WithSLO attaches a latency budget to a span. Violations are recorded as a
+// langwatch.slo.violated metadata attribute (naming which budgets were
+// missed) when the span ends, and optionally reported through slo.OnViolation.
+func WithSLO(slo SLO) SpanOption {
+	return func(s *Span) { s.slo = &slo }
+}
+
+// checkSLO evaluates s.slo against the span's recorded timings. Must be
+// called with s.mu held.
+func (s *Span) checkSLO() {
+	if s.slo == nil {
+		return
+	}
+
+	var violated string
+	if s.slo.TimeToFirstToken > 0 && s.firstTokenAt != nil {
+		if s.firstTokenAt.Sub(s.startedAt) > s.slo.TimeToFirstToken {
+			violated = appendViolation(violated, "ttft")
+		}
+	}
+	if s.slo.Total > 0 {
+		if s.finishedAt.Sub(s.startedAt) > s.slo.Total {
+			violated = appendViolation(violated, "total")
+		}
+	}
+	if violated == "" {
+		return
+	}
+
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[metadataSLOViolated] = violated
+
+	if s.slo.OnViolation != nil {
+		s.slo.OnViolation(s, violated)
+	}
+}
+
+func appendViolation(existing, name string) string {
+	if existing == "" {
+		return name
+	}
+	return existing + "," + name
+}
@@ -0,0 +1,47 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithSLOFlagsTotalViolation(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	var violatedArg string
+	_, span := StartSpan(ctx, "slow", WithSLO(SLO{
+		Total:       time.Nanosecond,
+		OnViolation: func(s *Span, violated string) { violatedArg = violated },
+	}))
+	time.Sleep(time.Millisecond)
+	span.End()
+
+	if span.metadata[metadataSLOViolated] != "total" {
+		t.Fatalf("expected total violation to be flagged, got %v", span.metadata)
+	}
+	if violatedArg != "total" {
+		t.Fatalf("expected OnViolation callback to fire with 'total', got %q", violatedArg)
+	}
+}
+
+func TestWithSLOFlagsFirstTokenViolation(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "slow", WithSLO(SLO{TimeToFirstToken: time.Nanosecond}))
+	time.Sleep(time.Millisecond)
+	span.RecordFirstToken()
+	span.End()
+
+	if span.metadata[metadataSLOViolated] != "ttft" {
+		t.Fatalf("expected ttft violation to be flagged, got %v", span.metadata)
+	}
+}
+
+func TestWithSLONoViolationWhenWithinBudget(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "fast", WithSLO(SLO{Total: time.Hour}))
+	span.End()
+
+	if _, ok := span.metadata[metadataSLOViolated]; ok {
+		t.Fatalf("expected no violation, got %v", span.metadata)
+	}
+}
@@ -0,0 +1,423 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// metadataExpectedOutput is the metadata key RecordExpectedOutput writes to.
+const metadataExpectedOutput = "langwatch.expected_output"
+
+// SpanRecorder is the subset of *Span's methods used to record data onto a
+// span. Helper functions that only need to record inputs/outputs/errors
+// should accept a SpanRecorder rather than *Span, so callers can pass a test
+// fake (see langwatchtest.FakeSpan) instead of a real span wired to a trace
+// and exporter.
+type SpanRecorder interface {
+	ID() string
+	RecordInput(value TypedValue)
+	RecordOutput(value TypedValue)
+	RecordExpectedOutput(value TypedValue)
+	RecordError(err ErrorCapture)
+	RecordMetrics(m Metrics)
+	RecordParams(p Params)
+	SetMetadata(key, value string)
+	RecordFirstToken()
+	Mark(name string)
+	AddTimelineEvent(name string, attrs map[string]string)
+	End()
+}
+
+var _ SpanRecorder = (*Span)(nil)
+
+// SpanOption configures a span at creation time.
+type SpanOption func(*Span)
+
+// WithType sets the span's type. Defaults to SpanTypeSpan.
+func WithType(t SpanType) SpanOption {
+	return func(s *Span) { s.spanType = t }
+}
+
+// SetType reclassifies the span's type after it's already started, e.g.
+// when a generic span turns out to be a guardrail check once its result is
+// known. Most callers should pass WithType at StartSpan time instead; this
+// exists for the cases - a shared helper that starts a plain SpanTypeSpan
+// and only its caller knows the real type - where that isn't possible.
+func (s *Span) SetType(t SpanType) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spanType = t
+}
+
+// WithModel sets the vendor and model name for an LLM span.
+func WithModel(vendor, model string) SpanOption {
+	return func(s *Span) {
+		s.vendor = vendor
+		s.model = model
+	}
+}
+
+// SpanKind classifies a span by its role in a distributed call, following
+// OpenTelemetry's span kind concept: SpanKindServer for a span representing
+// work done to handle an inbound request, SpanKindClient for a span
+// representing an outbound call this process made. It doesn't change
+// tracing behavior; it's recorded as metadata (see WithKind) so a service
+// that is both an inbound LLM gateway and an outbound caller to the real
+// provider - see langwatchproxy - can distinguish "who called us" from
+// "who we called" once traces reach LangWatch.
+type SpanKind string
+
+const (
+	SpanKindUnspecified SpanKind = ""
+	SpanKindServer      SpanKind = "server"
+	SpanKindClient      SpanKind = "client"
+)
+
+const (
+	metadataSpanKind      = "langwatch.span.kind"
+	metadataServerAddress = "server.address"
+	metadataClientAddress = "client.address"
+)
+
+// WithKind sets the span's kind. Defaults to SpanKindUnspecified, which
+// records no kind metadata at all.
+func WithKind(kind SpanKind) SpanOption {
+	return func(s *Span) { s.kind = kind }
+}
+
+// WithServerAddress records the address of the server side of this span's
+// call - the peer a SpanKindClient span connected to - using
+// OpenTelemetry's server.address semantic convention key, so it lines up
+// with attributes a collector or downstream tooling may already expect.
+func WithServerAddress(address string) SpanOption {
+	return func(s *Span) { s.SetMetadata(metadataServerAddress, address) }
+}
+
+// WithClientAddress records the address of the client side of this span's
+// call - who called into a SpanKindServer span - using OpenTelemetry's
+// client.address semantic convention key.
+func WithClientAddress(address string) SpanOption {
+	return func(s *Span) { s.SetMetadata(metadataClientAddress, address) }
+}
+
+// Span represents a single unit of work (an LLM call, a tool invocation, a
+// chain step, ...) within a Trace.
+type Span struct {
+	mu sync.Mutex
+
+	trace    *Trace
+	parent   *Span
+	id       string
+	name     string
+	spanType SpanType
+	kind     SpanKind
+
+	startedAt    time.Time
+	firstTokenAt *time.Time
+	finishedAt   time.Time
+	ended        bool
+
+	input   *TypedValue
+	outputs []TypedValue
+	err     *ErrorCapture
+
+	vendor  string
+	model   string
+	params  *Params
+	metrics *Metrics
+
+	contexts []string
+	metadata map[string]string
+	slo      *SLO
+	threadID string
+	marks    map[string]time.Time
+
+	timelineEvents []TimelineEvent
+	runtimeStart   *runtimeSample
+
+	captureEnabled bool
+	noop           bool
+}
+
+// StartSpan starts a new span as a child of the span (or trace) found in
+// ctx, creating a new trace first if ctx doesn't carry one yet. It returns a
+// context carrying the new span so nested calls pick it up automatically.
+func StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	if Disabled() {
+		return ctx, noopSpan
+	}
+
+	trace, ok := TraceFromContext(ctx)
+	if !ok {
+		ctx, trace = NewTrace(ctx)
+	}
+	parent, _ := SpanFromContext(ctx)
+
+	s := &Span{
+		trace:          trace,
+		parent:         parent,
+		id:             newSpanID(),
+		name:           name,
+		spanType:       SpanTypeSpan,
+		startedAt:      time.Now(),
+		captureEnabled: CaptureEnabled(ctx),
+		threadID:       ThreadID(ctx),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.applyCaptureProfile()
+	for k, v := range AttrsFromContext(ctx) {
+		s.SetMetadata(k, v)
+	}
+
+	if s.spanType == SpanTypeLLM {
+		if existing, ok := ctx.Value(inFlightLLMSpanContextKey).(*Span); ok && existing != nil {
+			switch duplicatePolicy {
+			case DuplicateSuppress:
+				return ctx, existing
+			case DuplicateMark:
+				s.SetMetadata(metadataDuplicate, "true")
+			}
+		}
+	}
+
+	trace.addSpan(s)
+	ctx = context.WithValue(ctx, spanContextKey, s)
+	if s.spanType == SpanTypeLLM {
+		ctx = context.WithValue(ctx, inFlightLLMSpanContextKey, s)
+	}
+	return ctx, s
+}
+
+// ID returns the span's unique identifier.
+func (s *Span) ID() string { return s.id }
+
+// RecordInput records the span's input, unless capture has been disabled.
+func (s *Span) RecordInput(value TypedValue) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.captureEnabled {
+		return
+	}
+	value = redactToolInput(s.spanType, value)
+	s.input = &value
+}
+
+// RecordOutput appends an output value to the span, unless capture has been
+// disabled.
+func (s *Span) RecordOutput(value TypedValue) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.captureEnabled {
+		return
+	}
+	s.outputs = append(s.outputs, redactToolOutput(value))
+}
+
+// RecordExpectedOutput records the answer a test harness expects for this
+// span, as langwatch.expected_output metadata, so LangWatch evaluators
+// (exact match, similarity, ...) can run automatically against traces
+// generated from supervised test runs.
+func (s *Span) RecordExpectedOutput(value TypedValue) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.captureEnabled {
+		return
+	}
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	s.metadata[metadataExpectedOutput] = string(body)
+}
+
+// RecordError attaches an error to the span. Errors are recorded even when
+// content capture is disabled, since the message/stacktrace are diagnostic
+// rather than user content; callers holding sensitive data in an error
+// should scrub it before calling RecordError.
+func (s *Span) RecordError(err ErrorCapture) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = &err
+	if err.Type != "" || err.Code != "" {
+		if s.metadata == nil {
+			s.metadata = map[string]string{}
+		}
+		s.metadata[metadataErrorFingerprint] = errorFingerprint(err.Type, err.Code, s.model)
+	}
+}
+
+// RecordMetrics attaches token usage / cost metrics to an LLM span.
+func (s *Span) RecordMetrics(m Metrics) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = &m
+}
+
+// RecordParams attaches the request parameters used for an LLM call.
+func (s *Span) RecordParams(p Params) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.params = &p
+}
+
+// SetMetadata attaches an SDK-side key/value annotation to the span. Unlike
+// input/output/metrics, which mirror fields the collector understands
+// natively, metadata is a free-form extension point for annotations (such
+// as hedging attempt numbers) that don't have a dedicated wire field yet.
+func (s *Span) SetMetadata(key, value string) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[key] = value
+}
+
+// SetUserID sets the end-user identifier exported with the span's trace.
+// It's a convenience for callers holding a *Span but not the ctx WithUserID
+// was meant for; see Trace.SetUserID.
+func (s *Span) SetUserID(userID string) {
+	if s.noop {
+		return
+	}
+	s.trace.SetUserID(userID)
+}
+
+// SetCustomerID sets the customer/tenant identifier exported with the
+// span's trace; see Trace.SetCustomerID.
+func (s *Span) SetCustomerID(customerID string) {
+	if s.noop {
+		return
+	}
+	s.trace.SetCustomerID(customerID)
+}
+
+// AddLabels appends labels to the span's trace; see Trace.AddLabels.
+func (s *Span) AddLabels(labels ...string) {
+	if s.noop {
+		return
+	}
+	s.trace.AddLabels(labels...)
+}
+
+// RecordFirstToken stamps the time the first streamed token was received.
+func (s *Span) RecordFirstToken() {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.firstTokenAt == nil {
+		now := time.Now()
+		s.firstTokenAt = &now
+	}
+}
+
+// End marks the span as finished. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.finishedAt = time.Now()
+	s.ended = true
+	s.recordRuntimeMetrics()
+	s.checkSLO()
+	s.checkAutoReviewRules()
+	s.validateAttributes()
+	s.recordThreadTurn(s.threadID)
+	s.summarizeTrace()
+}
+
+// toRecord converts the span into its wire representation.
+func (s *Span) toRecord() SpanRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finishedAt := s.finishedAt
+	if !s.ended {
+		finishedAt = time.Now()
+	}
+
+	var parentID *string
+	if s.parent != nil {
+		id := s.parent.id
+		parentID = &id
+	}
+
+	var firstToken *int64
+	if s.firstTokenAt != nil {
+		ms := s.firstTokenAt.UnixMilli()
+		firstToken = &ms
+	}
+
+	outputs := s.outputs
+	if outputs == nil {
+		outputs = []TypedValue{}
+	}
+
+	if s.kind != SpanKindUnspecified {
+		if s.metadata == nil {
+			s.metadata = map[string]string{}
+		}
+		s.metadata[metadataSpanKind] = string(s.kind)
+	}
+
+	return SpanRecord{
+		Type:     s.spanType,
+		Name:     s.name,
+		ID:       s.id,
+		ParentID: parentID,
+		TraceID:  s.trace.id,
+		Input:    s.input,
+		Outputs:  outputs,
+		Error:    s.err,
+		Timestamps: Timestamps{
+			StartedAt:    s.startedAt.UnixMilli(),
+			FirstTokenAt: firstToken,
+			FinishedAt:   finishedAt.UnixMilli(),
+		},
+		Vendor:         s.vendor,
+		Model:          s.model,
+		Params:         s.params,
+		Metrics:        s.metrics,
+		Contexts:       s.contexts,
+		Metadata:       s.metadata,
+		TimelineEvents: s.timelineEvents,
+	}
+}
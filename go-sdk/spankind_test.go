@@ -0,0 +1,42 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithKindRecordsSpanKindMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op", WithKind(SpanKindClient))
+	span.End()
+
+	record := span.toRecord()
+	if record.Metadata[metadataSpanKind] != "client" {
+		t.Fatalf("expected span kind metadata %q, got %+v", "client", record.Metadata)
+	}
+}
+
+func TestWithoutKindOmitsSpanKindMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op")
+	span.End()
+
+	record := span.toRecord()
+	if _, ok := record.Metadata[metadataSpanKind]; ok {
+		t.Fatalf("expected no span kind metadata, got %+v", record.Metadata)
+	}
+}
+
+func TestWithServerAndClientAddressRecordPeerMetadata(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "op", WithServerAddress("api.example.com"), WithClientAddress("10.0.0.1"))
+	span.End()
+
+	record := span.toRecord()
+	if record.Metadata[metadataServerAddress] != "api.example.com" {
+		t.Fatalf("expected server.address metadata, got %+v", record.Metadata)
+	}
+	if record.Metadata[metadataClientAddress] != "10.0.0.1" {
+		t.Fatalf("expected client.address metadata, got %+v", record.Metadata)
+	}
+}
@@ -0,0 +1,143 @@
+package langwatch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StreamChunk is the subset of an OpenAI-compatible chat completion chunk
+// that StreamAccumulator understands.
+type StreamChunk struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// StreamResult is the accumulated outcome of a fed SSE stream.
+type StreamResult struct {
+	Output       string
+	FinishReason string
+	Metrics      Metrics
+	// ResponseID is the id of the completion the stream produced, if the
+	// provider sent one on its chunks. When Metrics carries no usage
+	// (some providers omit it from the stream), a provider whose Responses
+	// API supports GET-by-id can be queried with ResponseID to reconcile
+	// authoritative usage after the fact - see ReconcileStreamUsage.
+	ResponseID string
+}
+
+// StreamAccumulator incrementally reconstructs the full output, finish
+// reason and usage of an OpenAI-compatible SSE stream, one line at a time.
+// It is exported so teams proxying model output to their own clients (and
+// therefore already reading the SSE stream themselves) can feed it the same
+// lines and get a span-ready result, instead of reimplementing
+// accumulation logic the SDK already needs internally.
+type StreamAccumulator struct {
+	output       strings.Builder
+	finishReason string
+	metrics      Metrics
+	responseID   string
+}
+
+// NewStreamAccumulator returns an empty accumulator ready to Feed lines to.
+func NewStreamAccumulator() *StreamAccumulator {
+	return &StreamAccumulator{}
+}
+
+// Feed processes a single line of an SSE stream (as delivered by
+// bufio.Scanner). Lines that aren't a "data: ..." payload, and the
+// terminating "data: [DONE]" line, are ignored.
+func (a *StreamAccumulator) Feed(line string) {
+	chunk, ok := parseSSELine(line)
+	if !ok {
+		return
+	}
+	a.feedChunk(chunk)
+}
+
+// FeedNDJSON processes a single line of an NDJSON stream (one JSON chunk per
+// line, no "data:" prefix or "[DONE]" sentinel), used by OpenAI-compatible
+// gateways that stream application/x-ndjson instead of SSE. Blank lines and
+// lines that don't parse as a StreamChunk are ignored.
+func (a *StreamAccumulator) FeedNDJSON(line string) {
+	chunk, ok := parseNDJSONLine(line)
+	if !ok {
+		return
+	}
+	a.feedChunk(chunk)
+}
+
+func (a *StreamAccumulator) feedChunk(chunk StreamChunk) {
+	if chunk.ID != "" {
+		a.responseID = chunk.ID
+	}
+	for _, choice := range chunk.Choices {
+		a.output.WriteString(choice.Delta.Content)
+		if choice.FinishReason != nil {
+			a.finishReason = *choice.FinishReason
+		}
+	}
+	if chunk.Usage != nil {
+		promptTokens := chunk.Usage.PromptTokens
+		completionTokens := chunk.Usage.CompletionTokens
+		a.metrics.PromptTokens = &promptTokens
+		a.metrics.CompletionTokens = &completionTokens
+	}
+}
+
+// Result returns the output, finish reason and usage accumulated so far.
+func (a *StreamAccumulator) Result() StreamResult {
+	return StreamResult{
+		Output:       a.output.String(),
+		FinishReason: a.finishReason,
+		Metrics:      a.metrics,
+		ResponseID:   a.responseID,
+	}
+}
+
+// StampOnto records the accumulated result onto span as its output and
+// metrics, as if the call hadn't been streamed at all. span accepts a
+// SpanRecorder so callers can pass a test fake in unit tests.
+func (a *StreamAccumulator) StampOnto(span SpanRecorder) {
+	result := a.Result()
+	span.RecordOutput(NewTextValue(result.Output))
+	span.RecordMetrics(result.Metrics)
+}
+
+// parseSSELine decodes a single SSE line into its chunk, if it carries one.
+// ok is false for non-data lines and the terminating "data: [DONE]" line.
+func parseSSELine(line string) (chunk StreamChunk, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "data:") {
+		return StreamChunk{}, false
+	}
+	data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+	if data == "" || data == "[DONE]" {
+		return StreamChunk{}, false
+	}
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return StreamChunk{}, false
+	}
+	return chunk, true
+}
+
+// parseNDJSONLine decodes a single NDJSON line into its chunk, if it carries
+// one. ok is false for blank lines and lines that don't parse as JSON.
+func parseNDJSONLine(line string) (chunk StreamChunk, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return StreamChunk{}, false
+	}
+	if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+		return StreamChunk{}, false
+	}
+	return chunk, true
+}
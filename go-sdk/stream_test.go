@@ -0,0 +1,70 @@
+package langwatch
+
+import "testing"
+
+func TestStreamAccumulator(t *testing.T) {
+	a := NewStreamAccumulator()
+	lines := []string{
+		`data: {"choices":[{"delta":{"content":"Hel"}}]}`,
+		`data: {"choices":[{"delta":{"content":"lo!"}}]}`,
+		`data: {"choices":[{"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`,
+		`data: [DONE]`,
+	}
+	for _, line := range lines {
+		a.Feed(line)
+	}
+
+	result := a.Result()
+	if result.Output != "Hello!" {
+		t.Fatalf("expected %q, got %q", "Hello!", result.Output)
+	}
+	if result.FinishReason != "stop" {
+		t.Fatalf("expected finish reason stop, got %q", result.FinishReason)
+	}
+	if result.Metrics.PromptTokens == nil || *result.Metrics.PromptTokens != 5 {
+		t.Fatalf("expected 5 prompt tokens, got %+v", result.Metrics.PromptTokens)
+	}
+}
+
+func TestStreamAccumulatorCapturesResponseID(t *testing.T) {
+	a := NewStreamAccumulator()
+	lines := []string{
+		`data: {"id":"chatcmpl-abc123","choices":[{"delta":{"content":"Hi"}}]}`,
+		`data: {"id":"chatcmpl-abc123","choices":[{"delta":{},"finish_reason":"stop"}]}`,
+		`data: [DONE]`,
+	}
+	for _, line := range lines {
+		a.Feed(line)
+	}
+
+	result := a.Result()
+	if result.ResponseID != "chatcmpl-abc123" {
+		t.Fatalf("ResponseID = %q, want %q", result.ResponseID, "chatcmpl-abc123")
+	}
+	if result.Metrics.PromptTokens != nil {
+		t.Fatalf("expected no usage, got %+v", result.Metrics.PromptTokens)
+	}
+}
+
+func TestStreamAccumulatorFeedNDJSON(t *testing.T) {
+	a := NewStreamAccumulator()
+	lines := []string{
+		`{"choices":[{"delta":{"content":"Hel"}}]}`,
+		"",
+		`{"choices":[{"delta":{"content":"lo!"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`,
+	}
+	for _, line := range lines {
+		a.FeedNDJSON(line)
+	}
+
+	result := a.Result()
+	if result.Output != "Hello!" {
+		t.Fatalf("expected %q, got %q", "Hello!", result.Output)
+	}
+	if result.FinishReason != "stop" {
+		t.Fatalf("expected finish reason stop, got %q", result.FinishReason)
+	}
+	if result.Metrics.PromptTokens == nil || *result.Metrics.PromptTokens != 5 {
+		t.Fatalf("expected 5 prompt tokens, got %+v", result.Metrics.PromptTokens)
+	}
+}
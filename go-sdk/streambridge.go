@@ -0,0 +1,238 @@
+package langwatch
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// metadataStreamIntervention is the timeline event name recorded whenever a
+// StreamHook modifies or stops a stream, so interventions are visible
+// alongside the rest of the span's timeline.
+const metadataStreamIntervention = "stream_intervention"
+
+// StreamHook inspects (and may modify) one line of a streamed response
+// before it's forwarded to the client. line is the raw SSE line as read
+// from upstream; offset is the number of bytes of the raw stream consumed
+// so far, including line.
+//
+// Returning a modified line different from the input substitutes it in the
+// forwarded stream (e.g. to mask profanity mid-stream). Returning
+// stop = true ends forwarding to the client for the rest of the stream;
+// upstream keeps being drained and accumulated so the trace still reflects
+// the full response, the same way a canceled context is handled.
+type StreamHook func(offset int, line string) (modified string, stop bool)
+
+// metadataEarlyStopReason is recorded on the span once an EarlyStopPredicate
+// fires. metadataEarlyStopSavedTokensEstimate is recorded alongside it only
+// when WithEarlyStopMaxTokens was used, since without a max-tokens budget
+// there's nothing to compute a saving against.
+const (
+	metadataEarlyStopReason              = "langwatch.early_stop.reason"
+	metadataEarlyStopSavedTokensEstimate = "langwatch.early_stop.saved_tokens_estimate"
+	charsPerEstimatedToken               = 4
+)
+
+// EarlyStopPredicate inspects the response accumulated so far (as plain
+// text, the same shape StreamAccumulator would report as output) and
+// decides whether generation should stop, e.g. because the model started
+// repeating itself or a banned phrase appeared. reason is recorded on the
+// span when stop is true, so it shows up next to the trace.
+type EarlyStopPredicate func(accumulated string) (stop bool, reason string)
+
+// StreamFormat selects how StreamBridge parses each line it reads from
+// upstream into a StreamChunk.
+type StreamFormat int
+
+const (
+	// StreamFormatSSE expects "data: {...}" lines terminated by
+	// "data: [DONE]", per OpenAI's chat completions streaming format. This
+	// is StreamBridge's default.
+	StreamFormatSSE StreamFormat = iota
+	// StreamFormatNDJSON expects one bare JSON chunk per line, used by
+	// OpenAI-compatible gateways that stream application/x-ndjson instead
+	// of SSE.
+	StreamFormatNDJSON
+)
+
+// DetectStreamFormat maps a response's Content-Type header to the
+// StreamFormat StreamBridge should use to parse it, defaulting to
+// StreamFormatSSE for anything other than application/x-ndjson (including
+// text/event-stream and unset/unrecognized content types), so callers can
+// pass a response's Content-Type straight through without special-casing
+// the SSE case themselves.
+func DetectStreamFormat(contentType string) StreamFormat {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-ndjson" {
+		return StreamFormatNDJSON
+	}
+	return StreamFormatSSE
+}
+
+// StreamBridgeOption configures a StreamBridge call.
+type StreamBridgeOption func(*streamBridgeConfig)
+
+type streamBridgeConfig struct {
+	hook               StreamHook
+	earlyStop          EarlyStopPredicate
+	earlyStopCancel    context.CancelFunc
+	earlyStopMaxTokens int
+	format             StreamFormat
+}
+
+// WithStreamFormat selects how upstream lines are parsed. Defaults to
+// StreamFormatSSE; pass StreamFormatNDJSON (or the result of
+// DetectStreamFormat) for gateways that stream NDJSON instead.
+func WithStreamFormat(format StreamFormat) StreamBridgeOption {
+	return func(c *streamBridgeConfig) { c.format = format }
+}
+
+// WithStreamHook installs a StreamHook that runs on every line before it's
+// forwarded to the client.
+func WithStreamHook(hook StreamHook) StreamBridgeOption {
+	return func(c *streamBridgeConfig) { c.hook = hook }
+}
+
+// WithEarlyStop installs a predicate that runs against the accumulated
+// output after every line forwarded to the client; once the client is gone
+// or a WithStreamHook has stopped forwarding, it is no longer checked. Once
+// it fires, StreamBridge stops reading from
+// upstream (rather than draining it like WithStreamHook's stop does) and
+// calls cancel - the CancelFunc for the context the caller used to start
+// the upstream request - so the model actually stops generating instead of
+// just being ignored. The stop reason is recorded on the span; see
+// WithEarlyStopMaxTokens for the saved-tokens estimate.
+//
+// StreamBridge only has an io.Reader for upstream, not the request that
+// produced it, so cancel is required to make "cancels the upstream
+// request" true; without it there would be nothing left to cancel.
+func WithEarlyStop(predicate EarlyStopPredicate, cancel context.CancelFunc) StreamBridgeOption {
+	return func(c *streamBridgeConfig) {
+		c.earlyStop = predicate
+		c.earlyStopCancel = cancel
+	}
+}
+
+// WithEarlyStopMaxTokens records, alongside WithEarlyStop's stop reason, an
+// estimate of how many completion tokens generation avoided by stopping
+// early: maxTokens minus the tokens generated up to the point the
+// EarlyStopPredicate fired. maxTokens should be the same max_tokens/
+// max_output_tokens budget passed to the upstream request, since without it
+// there's nothing to measure the saving against. If WithEarlyStopMaxTokens
+// isn't used, the saved-tokens metadata is omitted rather than reporting
+// tokens generated as tokens saved.
+func WithEarlyStopMaxTokens(maxTokens int) StreamBridgeOption {
+	return func(c *streamBridgeConfig) { c.earlyStopMaxTokens = maxTokens }
+}
+
+// estimateTokens approximates a token count from text length. The SDK has
+// no tokenizer of its own, so this is a rough chars-per-token heuristic,
+// good enough for a "roughly how much did stopping early save" metric, not
+// for billing.
+func estimateTokens(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	return (len(s) + charsPerEstimatedToken - 1) / charsPerEstimatedToken
+}
+
+// StreamBridge forwards a Server-Sent Events stream read from upstream to
+// w line by line, flushing after every line so the browser sees tokens at
+// the same latency they arrive from the model, while feeding the same
+// lines to a StreamAccumulator and stamping the accumulated output/usage
+// onto span once upstream closes.
+//
+// If ctx is canceled (the client disconnected) StreamBridge stops writing
+// to w, but keeps draining and accumulating from upstream so the trace
+// still reflects the full response even though nobody received the rest of
+// it. A write error to w is treated the same way, since the two most common
+// causes - a canceled context and a broken client connection - both mean
+// "no point writing any more."
+func StreamBridge(ctx context.Context, w http.ResponseWriter, upstream io.Reader, span SpanRecorder, opts ...StreamBridgeOption) error {
+	var cfg streamBridgeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	acc := NewStreamAccumulator()
+
+	scanner := bufio.NewScanner(upstream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	clientGone := false
+	offset := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		offset += len(line) + 1
+
+		forwarded := line
+		if cfg.hook != nil {
+			modified, stop := cfg.hook(offset, line)
+			if modified != line || stop {
+				span.AddTimelineEvent(metadataStreamIntervention, map[string]string{
+					"offset": strconv.Itoa(offset),
+					"stop":   strconv.FormatBool(stop),
+				})
+			}
+			forwarded = modified
+			if stop {
+				clientGone = true
+			}
+		}
+		// The accumulator always sees the original line, regardless of
+		// what a hook forwarded to the client, so the trace reflects what
+		// the model actually generated for later audit.
+		if cfg.format == StreamFormatNDJSON {
+			acc.FeedNDJSON(line)
+		} else {
+			acc.Feed(line)
+		}
+
+		if clientGone {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			clientGone = true
+			continue
+		default:
+		}
+
+		if _, err := io.WriteString(w, forwarded+"\n"); err != nil {
+			clientGone = true
+			continue
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if cfg.earlyStop != nil {
+			if stop, reason := cfg.earlyStop(acc.Result().Output); stop {
+				span.SetMetadata(metadataEarlyStopReason, reason)
+				if cfg.earlyStopMaxTokens > 0 {
+					saved := cfg.earlyStopMaxTokens - estimateTokens(acc.Result().Output)
+					if saved < 0 {
+						saved = 0
+					}
+					span.SetMetadata(metadataEarlyStopSavedTokensEstimate, strconv.Itoa(saved))
+				}
+				if cfg.earlyStopCancel != nil {
+					cfg.earlyStopCancel()
+				}
+				break
+			}
+		}
+	}
+
+	acc.StampOnto(span)
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("langwatch: read upstream stream: %w", err)
+	}
+	return nil
+}
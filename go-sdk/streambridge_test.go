@@ -0,0 +1,267 @@
+package langwatch
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamBridgeForwardsAndAccumulates(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2}}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	if err := StreamBridge(context.Background(), rec, upstream, span); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	if !rec.Flushed {
+		t.Fatal("expected the response writer to be flushed")
+	}
+	if !strings.Contains(rec.Body.String(), "Hel") || !strings.Contains(rec.Body.String(), "lo") {
+		t.Fatalf("expected forwarded body to contain the streamed content, got %q", rec.Body.String())
+	}
+
+	records := trace.Spans()[0].toRecord()
+	if len(records.Outputs) != 1 || records.Outputs[0].Value != "Hello" {
+		t.Fatalf("expected accumulated output %q, got %+v", "Hello", records.Outputs)
+	}
+	if records.Metrics == nil || *records.Metrics.PromptTokens != 3 || *records.Metrics.CompletionTokens != 2 {
+		t.Fatalf("expected accumulated usage to be stamped onto the span, got %+v", records.Metrics)
+	}
+}
+
+func TestStreamBridgeStopsWritingOnClientDisconnect(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"b\"}}]}\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, span := StartSpan(context.Background(), "chat")
+	if err := StreamBridge(ctx, rec, upstream, span); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected nothing written to a disconnected client, got %q", rec.Body.String())
+	}
+}
+
+func TestStreamBridgeHookMasksContentAndRecordsIntervention(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"damn\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\" it\"},\"finish_reason\":\"stop\"}]}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	hook := func(offset int, line string) (string, bool) {
+		if strings.Contains(line, "damn") {
+			return strings.ReplaceAll(line, "damn", "****"), false
+		}
+		return line, false
+	}
+
+	if err := StreamBridge(context.Background(), rec, upstream, span, WithStreamHook(hook)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	if strings.Contains(rec.Body.String(), "damn") {
+		t.Fatalf("expected masked content in forwarded body, got %q", rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "****") {
+		t.Fatalf("expected mask placeholder in forwarded body, got %q", rec.Body.String())
+	}
+
+	record := trace.Spans()[0].toRecord()
+	if record.Outputs[0].Value != "damn it" {
+		t.Fatalf("expected accumulated output to preserve the original text, got %+v", record.Outputs)
+	}
+	if len(record.TimelineEvents) != 1 || record.TimelineEvents[0].Name != metadataStreamIntervention {
+		t.Fatalf("expected 1 stream_intervention timeline event, got %+v", record.TimelineEvents)
+	}
+}
+
+func TestStreamBridgeHookStopsForwardingButKeepsAccumulating(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"safe\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\" violation\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\" more\"},\"finish_reason\":\"stop\"}]}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	hook := func(offset int, line string) (string, bool) {
+		if strings.Contains(line, "violation") {
+			return line, true
+		}
+		return line, false
+	}
+
+	if err := StreamBridge(context.Background(), rec, upstream, span, WithStreamHook(hook)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	if strings.Contains(rec.Body.String(), "violation") || strings.Contains(rec.Body.String(), "more") {
+		t.Fatalf("expected forwarding to stop at the violation, got %q", rec.Body.String())
+	}
+
+	record := trace.Spans()[0].toRecord()
+	if record.Outputs[0].Value != "safe violation more" {
+		t.Fatalf("expected accumulator to keep draining the full response, got %+v", record.Outputs)
+	}
+}
+
+func TestStreamBridgeEarlyStopCancelsAndRecordsEstimate(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot. \"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot. \"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot.\"},\"finish_reason\":\"stop\"}]}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	canceled := false
+	cancel := func() { canceled = true }
+
+	repeating := func(accumulated string) (bool, string) {
+		if strings.Count(accumulated, "I am a teapot.") >= 2 {
+			return true, "repetition detected"
+		}
+		return false, ""
+	}
+
+	err := StreamBridge(context.Background(), rec, upstream, span, WithEarlyStop(repeating, cancel), WithEarlyStopMaxTokens(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	if !canceled {
+		t.Fatal("expected the upstream request to be canceled once the predicate fired")
+	}
+	if strings.Count(rec.Body.String(), "teapot") >= 3 {
+		t.Fatalf("expected the third chunk not to be read after stopping, got %q", rec.Body.String())
+	}
+
+	record := trace.Spans()[0].toRecord()
+	if record.Metadata[metadataEarlyStopReason] != "repetition detected" {
+		t.Fatalf("expected the stop reason to be recorded, got %+v", record.Metadata)
+	}
+	generated := estimateTokens("I am a teapot. I am a teapot. ")
+	wantSaved := strconv.Itoa(100 - generated)
+	if record.Metadata[metadataEarlyStopSavedTokensEstimate] != wantSaved {
+		t.Fatalf("saved-token estimate = %q, want %q (100 - %d generated)", record.Metadata[metadataEarlyStopSavedTokensEstimate], wantSaved, generated)
+	}
+}
+
+func TestStreamBridgeEarlyStopWithoutMaxTokensOmitsEstimate(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot. \"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot. \"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"I am a teapot.\"},\"finish_reason\":\"stop\"}]}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	repeating := func(accumulated string) (bool, string) {
+		if strings.Count(accumulated, "I am a teapot.") >= 2 {
+			return true, "repetition detected"
+		}
+		return false, ""
+	}
+
+	if err := StreamBridge(context.Background(), rec, upstream, span, WithEarlyStop(repeating, func() {})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	record := trace.Spans()[0].toRecord()
+	if _, ok := record.Metadata[metadataEarlyStopSavedTokensEstimate]; ok {
+		t.Fatalf("expected no saved-token estimate without WithEarlyStopMaxTokens, got %q", record.Metadata[metadataEarlyStopSavedTokensEstimate])
+	}
+}
+
+func TestStreamBridgeWithoutEarlyStopIgnoresPredicateHooks(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"fine\"},\"finish_reason\":\"stop\"}]}\n" +
+			"data: [DONE]\n",
+	)
+
+	rec := httptest.NewRecorder()
+	_, span := StartSpan(context.Background(), "chat")
+
+	if err := StreamBridge(context.Background(), rec, upstream, span); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(rec.Body.String(), "fine") {
+		t.Fatalf("expected the full response to be forwarded when no early stop is configured, got %q", rec.Body.String())
+	}
+}
+
+func TestStreamBridgeNDJSONFormatAccumulates(t *testing.T) {
+	upstream := strings.NewReader(
+		"{\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n" +
+			"{\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2}}\n",
+	)
+
+	rec := httptest.NewRecorder()
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	if err := StreamBridge(context.Background(), rec, upstream, span, WithStreamFormat(StreamFormatNDJSON)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	span.End()
+
+	record := trace.Spans()[0].toRecord()
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "Hello" {
+		t.Fatalf("expected accumulated output %q, got %+v", "Hello", record.Outputs)
+	}
+	if record.Metrics == nil || *record.Metrics.PromptTokens != 3 || *record.Metrics.CompletionTokens != 2 {
+		t.Fatalf("expected accumulated usage to be stamped onto the span, got %+v", record.Metrics)
+	}
+}
+
+func TestDetectStreamFormat(t *testing.T) {
+	cases := map[string]StreamFormat{
+		"application/x-ndjson":                StreamFormatNDJSON,
+		"application/x-ndjson; charset=utf-8": StreamFormatNDJSON,
+		"text/event-stream":                   StreamFormatSSE,
+		"":                                    StreamFormatSSE,
+		"application/json":                    StreamFormatSSE,
+	}
+	for contentType, want := range cases {
+		if got := DetectStreamFormat(contentType); got != want {
+			t.Errorf("DetectStreamFormat(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package temporal instruments Temporal (https://temporal.io) workflows and
+// activities with LangWatch spans, so LLM calls made from within an
+// orchestrated workflow show up as one coherent trace per workflow run.
+package temporal
+
+import (
+	"context"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("temporal")
+}
+
+// threadIDHeaderKey is the Temporal header field used to carry the
+// workflow's thread ID down to its activities, since an activity gets its
+// own context.Context rather than inheriting the workflow's.
+const threadIDHeaderKey = "langwatch-thread-id"
+
+// NewWorkerInterceptor returns a Temporal WorkerInterceptor that starts a
+// LangWatch span for every workflow execution and every activity execution,
+// exporting each as its own trace via exporter. Activities are linked to
+// their workflow's thread ID via a Temporal header, so every span for a
+// workflow run - and its activities - lands on one LangWatch thread.
+//
+// Spans are never started while a workflow is replaying: replay
+// re-executes workflow code deterministically from history and must not
+// have observable side effects such as emitting a duplicate span.
+func NewWorkerInterceptor(exporter langwatch.Exporter) interceptor.WorkerInterceptor {
+	return &workerInterceptor{exporter: exporter}
+}
+
+type workerInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	exporter langwatch.Exporter
+}
+
+func (w *workerInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityInboundInterceptor{exporter: w.exporter}
+	i.Next = next
+	return i
+}
+
+func (w *workerInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	i := &workflowInboundInterceptor{exporter: w.exporter}
+	i.Next = next
+	return i
+}
+
+type workflowInboundInterceptor struct {
+	interceptor.WorkflowInboundInterceptorBase
+	exporter langwatch.Exporter
+}
+
+func (w *workflowInboundInterceptor) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	o := &workflowOutboundInterceptor{}
+	o.Next = outbound
+	return w.Next.Init(o)
+}
+
+func (w *workflowInboundInterceptor) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	if workflow.IsReplaying(ctx) {
+		return w.Next.ExecuteWorkflow(ctx, in)
+	}
+
+	info := workflow.GetInfo(ctx)
+	threadID := info.WorkflowExecution.ID
+
+	if header := interceptor.WorkflowHeader(ctx); header != nil {
+		if payload, err := converter.GetDefaultDataConverter().ToPayload(threadID); err == nil {
+			header[threadIDHeaderKey] = payload
+		}
+	}
+
+	tctx := langwatch.WithThreadID(context.Background(), threadID)
+	tctx, trace := langwatch.NewTrace(tctx)
+	_, span := langwatch.StartSpan(tctx, info.WorkflowType.Name, langwatch.WithType(langwatch.SpanTypeAgent))
+	span.RecordInput(langwatch.NewJSONValue(in.Args))
+
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+	} else {
+		span.RecordOutput(langwatch.NewJSONValue(result))
+	}
+	span.End()
+
+	// Workflow code cannot perform its own blocking IO; workflow.Go schedules
+	// the export as a coroutine on the workflow's own goroutine, matching how
+	// the SDK expects side effects to be dispatched from inside a workflow.
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		_ = w.exporter.Export(tctx, trace)
+	})
+
+	return result, err
+}
+
+type workflowOutboundInterceptor struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+type activityInboundInterceptor struct {
+	interceptor.ActivityInboundInterceptorBase
+	exporter langwatch.Exporter
+}
+
+func (a *activityInboundInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	info := activity.GetInfo(ctx)
+
+	tctx := ctx
+	if payload, ok := interceptor.Header(ctx)[threadIDHeaderKey]; ok {
+		var threadID string
+		if err := converter.GetDefaultDataConverter().FromPayload(payload, &threadID); err == nil {
+			tctx = langwatch.WithThreadID(context.Background(), threadID)
+		}
+	}
+	tctx, trace := langwatch.NewTrace(tctx)
+	_, span := langwatch.StartSpan(tctx, info.ActivityType.Name, langwatch.WithType(langwatch.SpanTypeTool))
+	span.RecordInput(langwatch.NewJSONValue(in.Args))
+
+	result, err := a.Next.ExecuteActivity(ctx, in)
+
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+	} else {
+		span.RecordOutput(langwatch.NewJSONValue(result))
+	}
+	span.End()
+
+	_ = a.exporter.Export(tctx, trace)
+
+	return result, err
+}
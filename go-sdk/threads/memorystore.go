@@ -0,0 +1,38 @@
+package threads
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store, useful for tests and single-process
+// deployments where checkpoints don't need to survive a restart. The zero
+// value is not usable; construct one with NewMemoryStore.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: map[string][]byte{}}
+}
+
+// Put implements Store.
+func (m *MemoryStore) Put(ctx context.Context, threadID string, state []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state[threadID] = append([]byte(nil), state...)
+	return nil
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(ctx context.Context, threadID string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.state[threadID]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), state...), true, nil
+}
@@ -0,0 +1,64 @@
+// Package threads lets a long-running conversation checkpoint and restore
+// its own agent state - scratchpad, tool-call history, whatever a caller
+// needs to resume from - keyed by the LangWatch thread ID, so a process
+// restart doesn't lose the conversation and the resumed work still
+// correlates back to the same thread in LangWatch.
+//
+// Like ContentStore, storage is pluggable: Checkpoint and Restore take a
+// Store implementation rather than assuming a particular backend, since
+// where checkpoints live (Redis, a database row, a file) is a deployment
+// decision this package shouldn't make.
+package threads
+
+import (
+	"context"
+	"strconv"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func init() {
+	langwatch.RegisterInstrumentation("threads")
+}
+
+// metadataCheckpointFound records, on a Restore span, whether a checkpoint
+// existed for the thread.
+const metadataCheckpointFound = "langwatch.thread.checkpoint_found"
+
+// Store persists and retrieves opaque per-thread checkpoint state, keyed by
+// LangWatch thread ID. Implementations must be safe for concurrent use and
+// Put must be idempotent - Checkpoint doesn't check existence first.
+type Store interface {
+	Put(ctx context.Context, threadID string, state []byte) error
+	Get(ctx context.Context, threadID string) (state []byte, found bool, err error)
+}
+
+// Checkpoint saves state for threadID in store, tracing the operation so
+// checkpoint activity shows up on the thread's trace alongside the
+// conversation itself.
+func Checkpoint(ctx context.Context, store Store, threadID string, state []byte) error {
+	ctx, span := langwatch.StartSpan(langwatch.WithThreadID(ctx, threadID), "thread.checkpoint")
+	defer span.End()
+
+	if err := store.Put(ctx, threadID, state); err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return err
+	}
+	return nil
+}
+
+// Restore loads the most recently checkpointed state for threadID from
+// store. found is false when no checkpoint exists yet, distinguishing
+// "never checkpointed" from an empty checkpoint.
+func Restore(ctx context.Context, store Store, threadID string) (state []byte, found bool, err error) {
+	ctx, span := langwatch.StartSpan(langwatch.WithThreadID(ctx, threadID), "thread.restore")
+	defer span.End()
+
+	state, found, err = store.Get(ctx, threadID)
+	if err != nil {
+		span.RecordError(langwatch.ErrorCapture{Message: err.Error()})
+		return nil, false, err
+	}
+	span.SetMetadata(metadataCheckpointFound, strconv.FormatBool(found))
+	return state, found, nil
+}
@@ -0,0 +1,136 @@
+package threads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	langwatch "github.com/langwatch/langwatch/go-sdk"
+)
+
+func recordedSpans(t *testing.T, trace *langwatch.Trace) []langwatch.SpanRecord {
+	t.Helper()
+	store, err := langwatch.OpenLocalStore(t.TempDir() + "/traces.jsonl")
+	if err != nil {
+		t.Fatalf("OpenLocalStore: %v", err)
+	}
+	if err := store.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	matches, err := store.Query(langwatch.LocalStoreFilters{TraceID: trace.ID()})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	records := make([]langwatch.SpanRecord, len(matches))
+	for i, m := range matches {
+		records[i] = m.Span
+	}
+	return records
+}
+
+func TestCheckpointThenRestoreRoundTripsState(t *testing.T) {
+	ctx, trace := langwatch.NewTrace(context.Background())
+	store := NewMemoryStore()
+
+	if err := Checkpoint(ctx, store, "thread-1", []byte(`{"turn":3}`)); err != nil {
+		t.Fatalf("Checkpoint: %v", err)
+	}
+
+	state, found, err := Restore(ctx, store, "thread-1")
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if !found {
+		t.Fatal("expected a checkpoint to be found")
+	}
+	if string(state) != `{"turn":3}` {
+		t.Fatalf("state = %q, want %q", state, `{"turn":3}`)
+	}
+
+	spans := recordedSpans(t, trace)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 recorded spans, got %d", len(spans))
+	}
+	if spans[0].Name != "thread.checkpoint" || spans[1].Name != "thread.restore" {
+		t.Fatalf("unexpected span names: %q, %q", spans[0].Name, spans[1].Name)
+	}
+	if spans[1].Metadata[metadataCheckpointFound] != "true" {
+		t.Fatalf("expected checkpoint_found=true, got %+v", spans[1].Metadata)
+	}
+}
+
+func TestRestoreReportsNotFoundForUnknownThread(t *testing.T) {
+	ctx, trace := langwatch.NewTrace(context.Background())
+	store := NewMemoryStore()
+
+	state, found, err := Restore(ctx, store, "never-checkpointed")
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if found {
+		t.Fatal("expected found=false for a thread with no checkpoint")
+	}
+	if state != nil {
+		t.Fatalf("expected nil state, got %v", state)
+	}
+
+	spans := recordedSpans(t, trace)
+	if len(spans) != 1 || spans[0].Metadata[metadataCheckpointFound] != "false" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Put(ctx context.Context, threadID string, state []byte) error {
+	return errors.New("put failed")
+}
+
+func (failingStore) Get(ctx context.Context, threadID string) ([]byte, bool, error) {
+	return nil, false, errors.New("get failed")
+}
+
+func TestCheckpointAndRestoreRecordStoreErrors(t *testing.T) {
+	ctx, trace := langwatch.NewTrace(context.Background())
+	store := failingStore{}
+
+	if err := Checkpoint(ctx, store, "thread-1", []byte("state")); err == nil {
+		t.Fatal("expected Checkpoint to propagate the store error")
+	}
+	if _, _, err := Restore(ctx, store, "thread-1"); err == nil {
+		t.Fatal("expected Restore to propagate the store error")
+	}
+
+	spans := recordedSpans(t, trace)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 recorded spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Error == nil {
+			t.Fatalf("expected an error to be recorded on span %q", span.Name)
+		}
+	}
+}
+
+func TestMemoryStoreCopiesStateToAvoidAliasing(t *testing.T) {
+	store := NewMemoryStore()
+	state := []byte("original")
+	if err := store.Put(context.Background(), "t", state); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	state[0] = 'X'
+
+	got, found, err := store.Get(context.Background(), "t")
+	if err != nil || !found {
+		t.Fatalf("Get: %v, found=%v", err, found)
+	}
+	if string(got) != "original" {
+		t.Fatalf("got %q, want %q (Put should copy its input)", got, "original")
+	}
+
+	got[0] = 'Y'
+	got2, _, _ := store.Get(context.Background(), "t")
+	if string(got2) != "original" {
+		t.Fatalf("got2 %q, want %q (Get should copy its output)", got2, "original")
+	}
+}
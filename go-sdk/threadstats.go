@@ -0,0 +1,118 @@
+package langwatch
+
+import (
+	"container/list"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+// Metadata keys set by recordThreadTurn.
+const (
+	metadataThreadTurnNumber       = "langwatch.thread.turn_number"
+	metadataThreadRoleCounts       = "langwatch.thread.role_counts"
+	metadataThreadCumulativeTokens = "langwatch.thread.cumulative_tokens"
+)
+
+// defaultThreadStatsCapacity bounds how many distinct threads' stats are
+// kept in memory at once; long-running processes with many threads evict
+// the least-recently-used ones rather than growing unbounded.
+const defaultThreadStatsCapacity = 10_000
+
+// threadStats accumulates per-thread analytics across the LLM spans
+// recorded for it, so thread-length/role/token questions don't need
+// backend reprocessing over every span.
+type threadStats struct {
+	turnNumber       int
+	roleCounts       map[ChatRole]int
+	cumulativeTokens int
+}
+
+// threadStatsLRU is a small hand-rolled LRU cache keyed by thread ID. A map
+// plus container/list is the standard Go idiom for this and keeps the SDK
+// dependency-free rather than pulling in a cache library for one use site.
+type threadStatsLRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+}
+
+type threadStatsEntry struct {
+	threadID string
+	stats    *threadStats
+}
+
+func newThreadStatsLRU(capacity int) *threadStatsLRU {
+	return &threadStatsLRU{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// getOrCreate returns the stats for threadID, creating and touching it as
+// most-recently-used, evicting the least-recently-used thread if the cache
+// is at capacity.
+func (c *threadStatsLRU) getOrCreate(threadID string) *threadStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[threadID]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*threadStatsEntry).stats
+	}
+
+	stats := &threadStats{roleCounts: map[ChatRole]int{}}
+	el := c.order.PushFront(&threadStatsEntry{threadID: threadID, stats: stats})
+	c.entries[threadID] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*threadStatsEntry).threadID)
+	}
+	return stats
+}
+
+var globalThreadStats = newThreadStatsLRU(defaultThreadStatsCapacity)
+
+// recordThreadTurn updates the running stats for threadID with this span's
+// message roles and token usage, then stamps the result onto the span as
+// metadata. A no-op when threadID is empty or the span isn't an LLM span.
+// Must be called with s.mu held.
+func (s *Span) recordThreadTurn(threadID string) {
+	if threadID == "" || s.spanType != SpanTypeLLM {
+		return
+	}
+
+	stats := globalThreadStats.getOrCreate(threadID)
+	stats.turnNumber++
+	if s.input != nil {
+		if messages, ok := s.input.Value.([]ChatMessage); ok {
+			for _, m := range messages {
+				stats.roleCounts[m.Role]++
+			}
+		}
+	}
+	if s.metrics != nil {
+		if s.metrics.PromptTokens != nil {
+			stats.cumulativeTokens += *s.metrics.PromptTokens
+		}
+		if s.metrics.CompletionTokens != nil {
+			stats.cumulativeTokens += *s.metrics.CompletionTokens
+		}
+	}
+
+	roleCounts, err := json.Marshal(stats.roleCounts)
+	if err != nil {
+		return
+	}
+
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[metadataThreadTurnNumber] = strconv.Itoa(stats.turnNumber)
+	s.metadata[metadataThreadRoleCounts] = string(roleCounts)
+	s.metadata[metadataThreadCumulativeTokens] = strconv.Itoa(stats.cumulativeTokens)
+}
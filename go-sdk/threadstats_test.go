@@ -0,0 +1,55 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordThreadTurnIncrementsAcrossSpans(t *testing.T) {
+	threadID := newSpanID() // unique per test run
+	ctx := WithThreadID(context.Background(), threadID)
+	ctx, _ = NewTrace(ctx)
+
+	content := "hi"
+	_, span1 := StartSpan(ctx, "turn-1", WithType(SpanTypeLLM))
+	span1.RecordInput(NewChatMessagesValue([]ChatMessage{{Role: RoleUser, Content: &content}}))
+	promptTokens := 3
+	span1.RecordMetrics(Metrics{PromptTokens: &promptTokens})
+	span1.End()
+
+	_, span2 := StartSpan(ctx, "turn-2", WithType(SpanTypeLLM))
+	span2.RecordInput(NewChatMessagesValue([]ChatMessage{{Role: RoleUser, Content: &content}}))
+	span2.RecordMetrics(Metrics{PromptTokens: &promptTokens})
+	span2.End()
+
+	if span1.metadata[metadataThreadTurnNumber] != "1" {
+		t.Fatalf("expected turn 1, got %v", span1.metadata[metadataThreadTurnNumber])
+	}
+	if span2.metadata[metadataThreadTurnNumber] != "2" {
+		t.Fatalf("expected turn 2, got %v", span2.metadata[metadataThreadTurnNumber])
+	}
+	if span2.metadata[metadataThreadCumulativeTokens] != "6" {
+		t.Fatalf("expected cumulative tokens 6, got %v", span2.metadata[metadataThreadCumulativeTokens])
+	}
+}
+
+func TestRecordThreadTurnNoOpWithoutThreadID(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "turn", WithType(SpanTypeLLM))
+	span.End()
+
+	if _, ok := span.metadata[metadataThreadTurnNumber]; ok {
+		t.Fatal("expected no thread metadata without a thread ID")
+	}
+}
+
+func TestThreadStatsLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	lru := newThreadStatsLRU(2)
+	a := lru.getOrCreate("a")
+	lru.getOrCreate("b")
+	lru.getOrCreate("c") // evicts "a"
+
+	if lru.getOrCreate("a") == a {
+		t.Fatal("expected thread 'a' to have been evicted and recreated")
+	}
+}
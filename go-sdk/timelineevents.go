@@ -0,0 +1,22 @@
+package langwatch
+
+import "time"
+
+// AddTimelineEvent records a point-in-time event on the span, e.g.
+// span.AddTimelineEvent("cache_hit", map[string]string{"key": "..."}). Unlike
+// metadata, which describes the span as a whole, timeline events carry their
+// own timestamp and can be added any number of times over the span's
+// lifetime; LangWatch's UI renders them on the trace timeline rather than
+// flattening them into span attributes.
+func (s *Span) AddTimelineEvent(name string, attrs map[string]string) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timelineEvents = append(s.timelineEvents, TimelineEvent{
+		Name:       name,
+		Timestamp:  time.Now().UnixMilli(),
+		Attributes: attrs,
+	})
+}
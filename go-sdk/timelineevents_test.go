@@ -0,0 +1,44 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAddTimelineEventRecordsNameAndAttributes(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "retrieval")
+
+	span.AddTimelineEvent("cache_hit", map[string]string{"key": "doc-1"})
+
+	record := span.toRecord()
+	if len(record.TimelineEvents) != 1 {
+		t.Fatalf("TimelineEvents = %v, want 1 event", record.TimelineEvents)
+	}
+	event := record.TimelineEvents[0]
+	if event.Name != "cache_hit" {
+		t.Fatalf("Name = %q, want cache_hit", event.Name)
+	}
+	if event.Attributes["key"] != "doc-1" {
+		t.Fatalf("Attributes[key] = %q, want doc-1", event.Attributes["key"])
+	}
+	if event.Timestamp == 0 {
+		t.Fatal("expected a non-zero timestamp")
+	}
+}
+
+func TestAddTimelineEventSupportsMultipleEvents(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "retrieval")
+
+	span.AddTimelineEvent("cache_hit", nil)
+	span.AddTimelineEvent("guardrail_triggered", map[string]string{"rule": "pii"})
+
+	record := span.toRecord()
+	if len(record.TimelineEvents) != 2 {
+		t.Fatalf("TimelineEvents = %v, want 2 events", record.TimelineEvents)
+	}
+	if record.TimelineEvents[1].Name != "guardrail_triggered" {
+		t.Fatalf("TimelineEvents[1].Name = %q, want guardrail_triggered", record.TimelineEvents[1].Name)
+	}
+}
@@ -0,0 +1,39 @@
+package langwatch
+
+import (
+	"context"
+	"time"
+)
+
+// metadataTimedOut is set on a span started with StartSpanWithTimeout if its
+// deadline fires before the returned cancel func is called.
+const metadataTimedOut = "langwatch.timeout.exceeded"
+
+// StartSpanWithTimeout starts a span like StartSpan, but also binds its
+// underlying context to a deadline: if d elapses before the returned
+// context.CancelFunc is called, the span is stamped with a timeout status,
+// saving agent loops (where every step has its own time budget) from having
+// to check ctx.Err() and record it by hand at every step.
+//
+// There's no Tracer type in this SDK - StartSpan is a free function, not a
+// method on one - so this follows the same shape rather than introducing a
+// Tracer just for this helper.
+//
+// As with context.WithTimeout, the caller must call cancel once it's done
+// with the span, typically via defer, to release the timer promptly.
+func StartSpanWithTimeout(ctx context.Context, name string, d time.Duration, opts ...SpanOption) (context.Context, *Span, context.CancelFunc) {
+	ctx, span := StartSpan(ctx, name, opts...)
+	ctx, cancel := context.WithTimeout(ctx, d)
+
+	go func() {
+		// ctx.Done closes both when d elapses and when cancel is called
+		// explicitly; ctx.Err distinguishes the two so a span that finished
+		// its work in time isn't mislabeled as having timed out.
+		<-ctx.Done()
+		if ctx.Err() == context.DeadlineExceeded {
+			span.SetMetadata(metadataTimedOut, "true")
+		}
+	}()
+
+	return ctx, span, cancel
+}
@@ -0,0 +1,49 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartSpanWithTimeoutRecordsTimeoutOnDeadlineExceeded(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span, cancel := StartSpanWithTimeout(ctx, "step", 10*time.Millisecond, WithType(SpanTypeAgent))
+	defer cancel()
+
+	<-ctx.Done()
+	// Give the watcher goroutine a moment to observe ctx.Done and set
+	// metadata before End reads it.
+	time.Sleep(20 * time.Millisecond)
+	span.End()
+
+	if span.metadata[metadataTimedOut] != "true" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataTimedOut, span.metadata[metadataTimedOut], "true")
+	}
+}
+
+func TestStartSpanWithTimeoutNoTimeoutWhenCanceledEarly(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span, cancel := StartSpanWithTimeout(ctx, "step", time.Hour, WithType(SpanTypeAgent))
+
+	_ = ctx
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	span.End()
+
+	if _, ok := span.metadata[metadataTimedOut]; ok {
+		t.Fatalf("expected no timeout metadata for an early cancel, got %+v", span.metadata)
+	}
+}
+
+func TestStartSpanWithTimeoutNoTimeoutWhenFinishedInTime(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span, cancel := StartSpanWithTimeout(ctx, "step", time.Hour, WithType(SpanTypeAgent))
+	defer cancel()
+
+	span.End()
+
+	if _, ok := span.metadata[metadataTimedOut]; ok {
+		t.Fatalf("expected no timeout metadata when the span finished before its deadline, got %+v", span.metadata)
+	}
+}
@@ -0,0 +1,71 @@
+package langwatch
+
+import (
+	"fmt"
+	"time"
+)
+
+// Mark records the current time under name, so a later MeasureBetween call
+// can derive a duration between it and another mark. Useful for timing
+// phases inside a single span (e.g. "retrieval_done") without creating a
+// child span just to get a duration.
+func (s *Span) Mark(name string) {
+	if s.noop {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.marks == nil {
+		s.marks = map[string]time.Time{}
+	}
+	s.marks[name] = time.Now()
+}
+
+// MeasureBetween computes the duration between two marks and records it as
+// span metadata under "langwatch.timing.<start>_to_<end>", returning the
+// duration. Besides marks recorded with Mark, "start" resolves to the
+// span's start time, "first_token" to the time RecordFirstToken was called
+// (if any), and "end" to the span's finish time once it has ended.
+func (s *Span) MeasureBetween(startMark, endMark string) (time.Duration, error) {
+	if s.noop {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start, ok := s.resolveMark(startMark)
+	if !ok {
+		return 0, fmt.Errorf("langwatch: unknown timing mark %q", startMark)
+	}
+	end, ok := s.resolveMark(endMark)
+	if !ok {
+		return 0, fmt.Errorf("langwatch: unknown timing mark %q", endMark)
+	}
+
+	d := end.Sub(start)
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[fmt.Sprintf("langwatch.timing.%s_to_%s", startMark, endMark)] = d.String()
+	return d, nil
+}
+
+// resolveMark must be called with s.mu held.
+func (s *Span) resolveMark(name string) (time.Time, bool) {
+	switch name {
+	case "start":
+		return s.startedAt, true
+	case "first_token":
+		if s.firstTokenAt != nil {
+			return *s.firstTokenAt, true
+		}
+		return time.Time{}, false
+	case "end":
+		if s.ended {
+			return s.finishedAt, true
+		}
+		return time.Time{}, false
+	}
+	t, ok := s.marks[name]
+	return t, ok
+}
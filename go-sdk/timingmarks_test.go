@@ -0,0 +1,50 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMeasureBetweenCustomMarks(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "pipeline")
+
+	span.Mark("retrieval_start")
+	time.Sleep(time.Millisecond)
+	span.Mark("retrieval_done")
+
+	d, err := span.MeasureBetween("retrieval_start", "retrieval_done")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d <= 0 {
+		t.Fatalf("expected a positive duration, got %v", d)
+	}
+	if span.metadata["langwatch.timing.retrieval_start_to_retrieval_done"] != d.String() {
+		t.Fatal("expected the derived duration to be stamped onto metadata")
+	}
+}
+
+func TestMeasureBetweenBuiltinMarks(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordFirstToken()
+
+	d, err := span.MeasureBetween("start", "first_token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d < 0 {
+		t.Fatalf("expected a non-negative duration, got %v", d)
+	}
+}
+
+func TestMeasureBetweenUnknownMarkErrors(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	if _, err := span.MeasureBetween("start", "does_not_exist"); err == nil {
+		t.Fatal("expected an error for an unresolved mark")
+	}
+}
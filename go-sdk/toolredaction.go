@@ -0,0 +1,81 @@
+package langwatch
+
+import "sync/atomic"
+
+// toolArgumentsRedactedPlaceholder replaces tool call arguments (or, for a
+// tool span, the whole recorded input) when SetRecordToolArguments(false)
+// is in effect.
+const toolArgumentsRedactedPlaceholder = "[redacted: tool arguments]"
+
+var recordToolArguments atomic.Bool
+
+func init() {
+	recordToolArguments.Store(true)
+}
+
+// SetRecordToolArguments controls whether tool call arguments are captured,
+// separate from the general content-capture switch (see
+// WithCaptureDisabled). Tool arguments - a SQL query, a customer record
+// passed to a function - frequently hold more sensitive data than the
+// surrounding message text, so callers may want to keep message content
+// while dropping arguments. Defaults to true. Intended to be called once
+// at startup.
+func SetRecordToolArguments(enabled bool) {
+	recordToolArguments.Store(enabled)
+}
+
+// redactToolInput applies SetRecordToolArguments to a value about to be
+// recorded as a span's input. For a tool span, the recorded input is
+// itself the tool's arguments, so the whole value is replaced. For
+// anything else (e.g. an LLM span's chat messages), only the arguments of
+// any tool calls within it are redacted.
+func redactToolInput(spanType SpanType, value TypedValue) TypedValue {
+	if recordToolArguments.Load() {
+		return value
+	}
+	if spanType == SpanTypeTool {
+		value.Value = toolArgumentsRedactedPlaceholder
+		return value
+	}
+	return redactToolCallsInValue(value)
+}
+
+// redactToolOutput applies SetRecordToolArguments to a value about to be
+// recorded as a span's output, redacting the arguments of any tool calls
+// found in chat messages.
+func redactToolOutput(value TypedValue) TypedValue {
+	if recordToolArguments.Load() {
+		return value
+	}
+	return redactToolCallsInValue(value)
+}
+
+func redactToolCallsInValue(value TypedValue) TypedValue {
+	switch v := value.Value.(type) {
+	case []ChatMessage:
+		value.Value = redactToolCalls(v)
+	case ChatMessage:
+		value.Value = redactToolCalls([]ChatMessage{v})[0]
+	}
+	return value
+}
+
+// redactToolCalls returns messages with every tool call's arguments
+// replaced by toolArgumentsRedactedPlaceholder, leaving role, content and
+// function name untouched.
+func redactToolCalls(messages []ChatMessage) []ChatMessage {
+	redacted := make([]ChatMessage, len(messages))
+	for i, m := range messages {
+		redacted[i] = m
+		if len(m.ToolCalls) == 0 {
+			continue
+		}
+		calls := make([]ToolCall, len(m.ToolCalls))
+		for j, c := range m.ToolCalls {
+			calls[j] = c
+			calls[j].Function.Arguments = toolArgumentsRedactedPlaceholder
+		}
+		redacted[i].ToolCalls = calls
+	}
+	return redacted
+}
@@ -0,0 +1,66 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestRecordToolArgumentsRedactsToolCallsInChatMessages(t *testing.T) {
+	defer SetRecordToolArguments(true)
+	SetRecordToolArguments(false)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+
+	messages := []ChatMessage{{
+		Role:    RoleAssistant,
+		Content: strPtr("let me check that"),
+		ToolCalls: []ToolCall{{
+			ID:   "call_1",
+			Type: "function",
+			Function: FunctionCall{
+				Name:      "run_sql",
+				Arguments: `{"query":"SELECT * FROM customers"}`,
+			},
+		}},
+	}}
+	span.RecordOutput(NewChatMessagesValue(messages))
+
+	got := span.outputs[0].Value.([]ChatMessage)
+	if got[0].ToolCalls[0].Function.Arguments != toolArgumentsRedactedPlaceholder {
+		t.Fatalf("Arguments = %q, want redacted", got[0].ToolCalls[0].Function.Arguments)
+	}
+	if got[0].ToolCalls[0].Function.Name != "run_sql" {
+		t.Fatal("expected the function name to survive redaction")
+	}
+	if *got[0].Content != "let me check that" {
+		t.Fatal("expected message content to survive redaction")
+	}
+}
+
+func TestRecordToolArgumentsRedactsWholeToolSpanInput(t *testing.T) {
+	defer SetRecordToolArguments(true)
+	SetRecordToolArguments(false)
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "run_sql", WithType(SpanTypeTool))
+
+	span.RecordInput(NewJSONValue(map[string]string{"query": "SELECT * FROM customers"}))
+
+	if span.input.Value != toolArgumentsRedactedPlaceholder {
+		t.Fatalf("input.Value = %v, want redacted", span.input.Value)
+	}
+}
+
+func TestRecordToolArgumentsDefaultsToCapturing(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "run_sql", WithType(SpanTypeTool))
+
+	span.RecordInput(NewJSONValue(map[string]string{"query": "SELECT * FROM customers"}))
+
+	if span.input.Value == toolArgumentsRedactedPlaceholder {
+		t.Fatal("expected tool arguments to be captured by default")
+	}
+}
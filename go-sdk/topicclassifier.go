@@ -0,0 +1,75 @@
+package langwatch
+
+import (
+	"context"
+	"strings"
+)
+
+// TopicClassifier computes topic labels for a trace's aggregated
+// input/output text, letting teams attach their own taxonomy to LangWatch
+// traces without LangWatch needing to know how the classification works.
+type TopicClassifier func(ctx context.Context, text string) []string
+
+// TraceUpdater patches attributes onto an already-exported trace, such as
+// the topic labels a TopicClassifier computes after the fact.
+type TraceUpdater interface {
+	UpdateLabels(ctx context.Context, traceID string, labels []string) error
+}
+
+// WithTopicClassifier makes the exporter run classifier over every trace's
+// aggregated input/output text asynchronously, on its own goroutine, once
+// the trace has been exported, and attach the returned labels through the
+// TraceUpdater configured with WithTraceUpdater. Classification never
+// blocks or fails Export: with no TraceUpdater configured, computed labels
+// are dropped with a debug log line.
+func WithTopicClassifier(classifier TopicClassifier) ExporterOption {
+	return func(e *httpExporter) { e.topicClassifier = classifier }
+}
+
+// WithTraceUpdater configures where the labels computed by
+// WithTopicClassifier are sent.
+func WithTraceUpdater(updater TraceUpdater) ExporterOption {
+	return func(e *httpExporter) { e.traceUpdater = updater }
+}
+
+// classifyTopics runs e.topicClassifier over trace's text on a background
+// goroutine, if one is configured. It's called after Export so
+// classification never adds latency to the export path it rides along with.
+func (e *httpExporter) classifyTopics(ctx context.Context, trace *Trace) {
+	if e.topicClassifier == nil {
+		return
+	}
+	go func() {
+		text := traceText(trace)
+		if text == "" {
+			return
+		}
+		labels := e.topicClassifier(ctx, text)
+		if len(labels) == 0 {
+			return
+		}
+		if e.traceUpdater == nil {
+			debugLog("topic classifier produced %d label(s) for trace %s but no TraceUpdater is configured (see WithTraceUpdater)", len(labels), trace.ID())
+			return
+		}
+		if err := e.traceUpdater.UpdateLabels(ctx, trace.ID(), labels); err != nil {
+			debugLog("failed to update trace %s with classified topics: %v", trace.ID(), err)
+		}
+	}()
+}
+
+// traceText concatenates every span's captured input/output text into one
+// string for the classifier to work with.
+func traceText(trace *Trace) string {
+	var parts []string
+	for _, s := range trace.Spans() {
+		record := s.toRecord()
+		if t := textFromValue(record.Input); t != "" {
+			parts = append(parts, t)
+		}
+		if t := textFromOutputs(record.Outputs); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,87 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type traceUpdateCall struct {
+	traceID string
+	labels  []string
+}
+
+type fakeTraceUpdater struct {
+	calls chan traceUpdateCall
+}
+
+func (u *fakeTraceUpdater) UpdateLabels(ctx context.Context, traceID string, labels []string) error {
+	u.calls <- traceUpdateCall{traceID: traceID, labels: labels}
+	return nil
+}
+
+func TestClassifyTopicsSendsLabelsToTraceUpdater(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue("what's your refund policy?"))
+	span.End()
+
+	updater := &fakeTraceUpdater{calls: make(chan traceUpdateCall, 1)}
+	e := &httpExporter{
+		topicClassifier: func(ctx context.Context, text string) []string {
+			if text == "" {
+				t.Error("expected non-empty trace text")
+			}
+			return []string{"billing"}
+		},
+		traceUpdater: updater,
+	}
+
+	e.classifyTopics(context.Background(), trace)
+
+	select {
+	case call := <-updater.calls:
+		if call.traceID != trace.ID() {
+			t.Fatalf("traceID = %q, want %q", call.traceID, trace.ID())
+		}
+		if len(call.labels) != 1 || call.labels[0] != "billing" {
+			t.Fatalf("labels = %v, want [billing]", call.labels)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for TraceUpdater.UpdateLabels")
+	}
+}
+
+func TestClassifyTopicsSkipsUpdateWithoutLabels(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue("hello there"))
+	span.End()
+
+	updater := &fakeTraceUpdater{calls: make(chan traceUpdateCall, 1)}
+	e := &httpExporter{
+		topicClassifier: func(ctx context.Context, text string) []string { return nil },
+		traceUpdater:    updater,
+	}
+
+	e.classifyTopics(context.Background(), trace)
+
+	select {
+	case <-updater.calls:
+		t.Fatal("expected no update when the classifier returns no labels")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTraceTextConcatenatesSpanInputAndOutput(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+	span.RecordInput(NewTextValue("question"))
+	span.RecordOutput(NewTextValue("answer"))
+	span.End()
+
+	text := traceText(trace)
+	if text != "question answer" {
+		t.Fatalf("traceText = %q, want %q", text, "question answer")
+	}
+}
@@ -0,0 +1,191 @@
+package langwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Trace collects the spans produced by a single logical operation (an HTTP
+// request, a queue job, a workflow run, ...) so they can be exported
+// together as one unit.
+type Trace struct {
+	id string
+
+	// linkedFromTraceID, when set, is the ID of the trace that produced the
+	// job/message this trace continues (see InjectJob/ExtractJob). It is
+	// exported as a label since LangWatch traces don't have a native
+	// parent-trace link.
+	linkedFromTraceID string
+
+	// captureEnabled is snapshotted once when the trace is created, mirroring
+	// Span.captureEnabled: RecordInput/RecordOutput have no ctx parameter, so
+	// they can't re-check CaptureEnabled(ctx) themselves and instead defend
+	// in depth against being called directly instead of through
+	// RecordTraceInput/RecordTraceOutput.
+	captureEnabled bool
+
+	mu         sync.Mutex
+	spans      []*Span
+	metadata   map[string]string
+	userID     string
+	customerID string
+	labels     []string
+	input      *TypedValue
+	output     *TypedValue
+}
+
+// ID returns the trace's unique identifier.
+func (t *Trace) ID() string {
+	return t.id
+}
+
+// LinkedFromTraceID returns the ID of the trace that produced the job or
+// message this trace continues, if any.
+func (t *Trace) LinkedFromTraceID() string {
+	return t.linkedFromTraceID
+}
+
+func (t *Trace) addSpan(s *Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, s)
+}
+
+// Spans returns a snapshot of the spans recorded on the trace so far.
+func (t *Trace) Spans() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]*Span, len(t.spans))
+	copy(out, t.spans)
+	return out
+}
+
+// SetMetadata merges kv into the trace's metadata. String values are
+// stored as-is; any other value is JSON-encoded first, since metadata is
+// always sent to the collector as a flat string map. Calling it more than
+// once merges into what's already there - the same key overwrites its
+// previous value, everything else is left untouched.
+func (t *Trace) SetMetadata(kv map[string]any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.metadata == nil {
+		t.metadata = map[string]string{}
+	}
+	for k, v := range kv {
+		if s, ok := v.(string); ok {
+			t.metadata[k] = s
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("langwatch: marshal trace metadata %q: %w", k, err)
+		}
+		t.metadata[k] = string(encoded)
+	}
+	return nil
+}
+
+// Metadata returns a snapshot of the trace's metadata set with
+// SetMetadata/SetTraceMetadata.
+func (t *Trace) Metadata() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]string, len(t.metadata))
+	for k, v := range t.metadata {
+		out[k] = v
+	}
+	return out
+}
+
+// SetUserID sets the end-user identifier exported with the trace. It takes
+// precedence over WithUserID(ctx, ...) at export time, so a handler with
+// access to a span but not the original ctx (e.g. inside a callback) can
+// still set it - and since every span belongs to exactly one Trace, this is
+// how the value reaches every span in the trace, not just the one that set
+// it.
+func (t *Trace) SetUserID(userID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.userID = userID
+}
+
+// UserID returns the user identifier set with SetUserID, if any.
+func (t *Trace) UserID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.userID
+}
+
+// SetCustomerID sets the customer/tenant identifier exported with the
+// trace, taking precedence over WithCustomerID(ctx, ...) at export time.
+func (t *Trace) SetCustomerID(customerID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.customerID = customerID
+}
+
+// CustomerID returns the customer identifier set with SetCustomerID, if
+// any.
+func (t *Trace) CustomerID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.customerID
+}
+
+// AddLabels appends labels to the trace's exported labels, in addition to
+// any set with WithLabels(ctx, ...).
+func (t *Trace) AddLabels(labels ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.labels = append(t.labels, labels...)
+}
+
+// Labels returns the labels added with AddLabels, if any.
+func (t *Trace) Labels() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]string, len(t.labels))
+	copy(out, t.labels)
+	return out
+}
+
+// RecordInput sets the trace's top-level input, distinct from any
+// individual span's input, unless capture has been disabled. It's meant for
+// a caller whose root span is a generic HTTP handler or job runner that has
+// nothing meaningful to show as its own input, so the trace list still has
+// something to summarize the trace by.
+func (t *Trace) RecordInput(value TypedValue) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.captureEnabled {
+		return
+	}
+	t.input = &value
+}
+
+// Input returns the trace's top-level input, if RecordInput was called.
+func (t *Trace) Input() *TypedValue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.input
+}
+
+// RecordOutput sets the trace's top-level output, distinct from any
+// individual span's output, unless capture has been disabled; see
+// RecordInput.
+func (t *Trace) RecordOutput(value TypedValue) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.captureEnabled {
+		return
+	}
+	t.output = &value
+}
+
+// Output returns the trace's top-level output, if RecordOutput was called.
+func (t *Trace) Output() *TypedValue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.output
+}
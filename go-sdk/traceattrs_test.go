@@ -0,0 +1,88 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpanSetUserIDCustomerIDAndLabelsPropagateToExport(t *testing.T) {
+	var got CollectorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "answer")
+	span.SetUserID("user-42")
+	span.SetCustomerID("acme-corp")
+	span.AddLabels("beta-cohort")
+	span.End()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if got.UserID != "user-42" {
+		t.Fatalf("UserID = %q, want %q", got.UserID, "user-42")
+	}
+	if got.CustomerID != "acme-corp" {
+		t.Fatalf("CustomerID = %q, want %q", got.CustomerID, "acme-corp")
+	}
+	found := false
+	for _, l := range got.Labels {
+		if l == "beta-cohort" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Labels = %v, want to contain %q", got.Labels, "beta-cohort")
+	}
+}
+
+func TestSpanSetUserIDPropagatesToSiblingSpans(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, first := StartSpan(ctx, "first")
+	first.SetUserID("user-1")
+	first.End()
+
+	_, second := StartSpan(ctx, "second")
+	second.End()
+
+	if trace.UserID() != "user-1" {
+		t.Fatalf("trace.UserID() = %q, want %q - expected the value set on one span to reach the whole trace", trace.UserID(), "user-1")
+	}
+}
+
+func TestTraceUserIDTakesPrecedenceOverContext(t *testing.T) {
+	var got CollectorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := WithUserID(context.Background(), "ctx-user")
+	ctx, trace := NewTrace(ctx)
+	trace.SetUserID("trace-user")
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if got.UserID != "trace-user" {
+		t.Fatalf("UserID = %q, want %q", got.UserID, "trace-user")
+	}
+}
@@ -0,0 +1,92 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// traceFile is the on-disk representation written by ExportTraceToFile and
+// read back by LoadTraceFile. It mirrors CollectorRequest so a saved dump
+// can be resubmitted to a (possibly different) LangWatch project unchanged.
+type traceFile struct {
+	CollectorRequest
+}
+
+// ExportTraceToFile writes trace's spans to path as JSON, in the same shape
+// the collector accepts, so it can be attached to a bug report or replayed
+// into a staging project later with LoadTraceFile and Backfill.
+func ExportTraceToFile(ctx context.Context, trace *Trace, path string) error {
+	spans := trace.Spans()
+	records := make([]SpanRecord, len(spans))
+	for i, s := range spans {
+		records[i] = s.toRecord()
+	}
+
+	body, err := json.MarshalIndent(traceFile{CollectorRequest{
+		TraceID:    trace.ID(),
+		ThreadID:   ThreadID(ctx),
+		UserID:     UserID(ctx),
+		CustomerID: CustomerID(ctx),
+		Labels:     Labels(ctx),
+		Spans:      records,
+	}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("langwatch: marshal trace dump: %w", err)
+	}
+
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("langwatch: write trace dump %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadTraceFile reads a trace dump previously written by ExportTraceToFile.
+func LoadTraceFile(path string) (CollectorRequest, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return CollectorRequest{}, fmt.Errorf("langwatch: read trace dump %s: %w", path, err)
+	}
+	var file traceFile
+	if err := json.Unmarshal(body, &file); err != nil {
+		return CollectorRequest{}, fmt.Errorf("langwatch: parse trace dump %s: %w", path, err)
+	}
+	return file.CollectorRequest, nil
+}
+
+// ReplayTraceFile loads the trace dump at path and re-submits it to exporter
+// as a Backfill, so a trace captured from one project (or exported for a bug
+// report) can be replayed into another, e.g. a staging environment.
+func ReplayTraceFile(ctx context.Context, exporter Exporter, path string) error {
+	req, err := LoadTraceFile(path)
+	if err != nil {
+		return err
+	}
+
+	spans := make([]BackfillSpan, len(req.Spans))
+	for i, r := range req.Spans {
+		var parentID string
+		if r.ParentID != nil {
+			parentID = *r.ParentID
+		}
+		spans[i] = BackfillSpan{
+			TraceID:    req.TraceID,
+			ID:         r.ID,
+			ParentID:   parentID,
+			Name:       r.Name,
+			Type:       r.Type,
+			Vendor:     r.Vendor,
+			Model:      r.Model,
+			Input:      r.Input,
+			Outputs:    r.Outputs,
+			Error:      r.Error,
+			Metrics:    r.Metrics,
+			Params:     r.Params,
+			StartedAt:  time.UnixMilli(r.Timestamps.StartedAt),
+			FinishedAt: time.UnixMilli(r.Timestamps.FinishedAt),
+		}
+	}
+	return Backfill(ctx, exporter, spans)
+}
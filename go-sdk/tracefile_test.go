@@ -0,0 +1,40 @@
+package langwatch
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportTraceToFileAndReplay(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat", WithType(SpanTypeLLM))
+	span.RecordInput(NewTextValue("hello"))
+	span.End()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := ExportTraceToFile(ctx, trace, path); err != nil {
+		t.Fatalf("ExportTraceToFile returned error: %v", err)
+	}
+
+	req, err := LoadTraceFile(path)
+	if err != nil {
+		t.Fatalf("LoadTraceFile returned error: %v", err)
+	}
+	if req.TraceID != trace.ID() || len(req.Spans) != 1 {
+		t.Fatalf("expected the loaded dump to match the original trace, got %+v", req)
+	}
+
+	exporter := &fakeExporter{}
+	if err := ReplayTraceFile(context.Background(), exporter, path); err != nil {
+		t.Fatalf("ReplayTraceFile returned error: %v", err)
+	}
+	if exporter.trace == nil || exporter.trace.ID() != trace.ID() {
+		t.Fatal("expected ReplayTraceFile to re-submit the trace via Backfill")
+	}
+	original := trace.Spans()[0].toRecord()
+	replayed := exporter.trace.Spans()
+	if len(replayed) != 1 || replayed[0].toRecord().Timestamps.StartedAt != original.Timestamps.StartedAt {
+		t.Fatalf("expected the replayed span to preserve its start timestamp")
+	}
+}
@@ -0,0 +1,100 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceRecordInputAndOutput(t *testing.T) {
+	_, trace := NewTrace(context.Background())
+
+	trace.RecordInput(NewTextValue("incoming webhook"))
+	trace.RecordOutput(NewTextValue("200 OK"))
+
+	if got := trace.Input(); got == nil || got.Value != "incoming webhook" {
+		t.Fatalf("Input() = %+v", got)
+	}
+	if got := trace.Output(); got == nil || got.Value != "200 OK" {
+		t.Fatalf("Output() = %+v", got)
+	}
+}
+
+func TestRecordTraceInputAndOutputFreeFunctions(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+
+	RecordTraceInput(ctx, NewTextValue("incoming webhook"))
+	RecordTraceOutput(ctx, NewTextValue("200 OK"))
+
+	if got := trace.Input(); got == nil || got.Value != "incoming webhook" {
+		t.Fatalf("Input() = %+v", got)
+	}
+	if got := trace.Output(); got == nil || got.Value != "200 OK" {
+		t.Fatalf("Output() = %+v", got)
+	}
+}
+
+func TestTraceRecordInputAndOutputRespectCaptureDisabled(t *testing.T) {
+	_, trace := NewTrace(WithCaptureDisabled(context.Background()))
+
+	trace.RecordInput(NewTextValue("should not be captured"))
+	trace.RecordOutput(NewTextValue("should not be captured"))
+
+	if trace.Input() != nil {
+		t.Fatalf("Input() = %+v, want nil with capture disabled", trace.Input())
+	}
+	if trace.Output() != nil {
+		t.Fatalf("Output() = %+v, want nil with capture disabled", trace.Output())
+	}
+}
+
+func TestRecordTraceInputNoopWithoutTraceInContext(t *testing.T) {
+	RecordTraceInput(context.Background(), NewTextValue("x"))
+	RecordTraceOutput(context.Background(), NewTextValue("y"))
+}
+
+func TestRecordTraceInputRespectsCaptureDisabled(t *testing.T) {
+	ctx, trace := NewTrace(WithCaptureDisabled(context.Background()))
+
+	RecordTraceInput(ctx, NewTextValue("should not be captured"))
+	RecordTraceOutput(ctx, NewTextValue("should not be captured"))
+
+	if trace.Input() != nil {
+		t.Fatalf("Input() = %+v, want nil with capture disabled", trace.Input())
+	}
+	if trace.Output() != nil {
+		t.Fatalf("Output() = %+v, want nil with capture disabled", trace.Output())
+	}
+}
+
+func TestTraceInputOutputExportsUnderCollectorRequest(t *testing.T) {
+	var got CollectorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, trace := NewTrace(context.Background())
+	RecordTraceInput(ctx, NewTextValue("incoming webhook"))
+	RecordTraceOutput(ctx, NewTextValue("200 OK"))
+	_, span := StartSpan(ctx, "handle")
+	span.End()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if got.Input == nil || got.Input.Value != "incoming webhook" {
+		t.Fatalf("Input = %+v", got.Input)
+	}
+	if got.Output == nil || got.Output.Value != "200 OK" {
+		t.Fatalf("Output = %+v", got.Output)
+	}
+}
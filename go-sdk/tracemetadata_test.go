@@ -0,0 +1,86 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceSetMetadataMergesAcrossCalls(t *testing.T) {
+	_, trace := NewTrace(context.Background())
+
+	if err := trace.SetMetadata(map[string]any{"tenant": "acme"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := trace.SetMetadata(map[string]any{"cohort": "beta"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := trace.Metadata()
+	if got["tenant"] != "acme" || got["cohort"] != "beta" {
+		t.Fatalf("Metadata() = %+v, want tenant=acme cohort=beta", got)
+	}
+}
+
+func TestTraceSetMetadataOverwritesSameKey(t *testing.T) {
+	_, trace := NewTrace(context.Background())
+
+	trace.SetMetadata(map[string]any{"status": "pending"})
+	trace.SetMetadata(map[string]any{"status": "resolved"})
+
+	if got := trace.Metadata()["status"]; got != "resolved" {
+		t.Fatalf("status = %q, want %q", got, "resolved")
+	}
+}
+
+func TestTraceSetMetadataJSONEncodesNonStringValues(t *testing.T) {
+	_, trace := NewTrace(context.Background())
+
+	if err := trace.SetMetadata(map[string]any{"scores": []int{1, 2, 3}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var scores []int
+	if err := json.Unmarshal([]byte(trace.Metadata()["scores"]), &scores); err != nil {
+		t.Fatalf("unmarshal scores: %v", err)
+	}
+	if len(scores) != 3 || scores[2] != 3 {
+		t.Fatalf("scores = %v", scores)
+	}
+}
+
+func TestSetTraceMetadataFailsWithoutTraceInContext(t *testing.T) {
+	if err := SetTraceMetadata(context.Background(), map[string]any{"tenant": "acme"}); err == nil {
+		t.Fatal("expected an error when ctx carries no trace")
+	}
+}
+
+func TestSetTraceMetadataExportsUnderCollectorRequestMetadata(t *testing.T) {
+	var got CollectorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, trace := NewTrace(context.Background())
+	if err := SetTraceMetadata(ctx, map[string]any{"tenant": "acme"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, span := StartSpan(ctx, "answer")
+	span.End()
+
+	exporter, err := NewExporter(Config{APIKey: "key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building exporter: %v", err)
+	}
+	if err := exporter.Export(ctx, trace); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	if got.Metadata["tenant"] != "acme" {
+		t.Fatalf("Metadata[tenant] = %q, want %q", got.Metadata["tenant"], "acme")
+	}
+}
@@ -0,0 +1,90 @@
+package langwatch
+
+import "context"
+
+// Attribute is a key/value pair stamped onto every span a SpanTracer
+// starts, e.g. a component name or feature-flag cohort.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// TracerOption configures a SpanTracer built with Tracer.
+type TracerOption func(*SpanTracer)
+
+// WithDefaultAttributes sets the attributes every span the tracer starts is
+// stamped with as metadata, so team/service-specific context (component
+// name, feature flag cohort) doesn't need to be repeated at every Start
+// call.
+func WithDefaultAttributes(attrs ...Attribute) TracerOption {
+	return func(t *SpanTracer) { t.defaultAttributes = attrs }
+}
+
+// SpanTracer is a named span factory returned by Tracer. It's not required
+// to use this SDK - StartSpan works standalone - but is useful when many
+// call sites in the same component would otherwise repeat the same set of
+// SpanOptions.
+type SpanTracer struct {
+	name              string
+	defaultAttributes []Attribute
+}
+
+// Tracer returns a SpanTracer identified by name (e.g. a package or
+// component name), configured with opts. There's no dedicated Tracer type
+// in this SDK's data model - spans belong to a Trace, not a tracer - so
+// this exists purely as an ergonomic wrapper around StartSpan.
+func Tracer(name string, opts ...TracerOption) *SpanTracer {
+	t := &SpanTracer{name: name}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Start starts a span exactly like StartSpan, then stamps it with the
+// tracer's default attributes before returning it.
+func (t *SpanTracer) Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	ctx, span := StartSpan(ctx, name, opts...)
+	for _, attr := range t.defaultAttributes {
+		span.SetMetadata(attr.Key, attr.Value)
+	}
+	return ctx, span
+}
+
+// startTyped is Start with the given SpanType prepended to opts, so a
+// WithType passed by the caller still overrides it.
+func (t *SpanTracer) startTyped(ctx context.Context, spanType SpanType, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.Start(ctx, name, append([]SpanOption{WithType(spanType)}, opts...)...)
+}
+
+// StartLLMSpan starts a span of type SpanTypeLLM, so call sites don't need
+// to remember WithType(SpanTypeLLM) themselves.
+func (t *SpanTracer) StartLLMSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeLLM, name, opts...)
+}
+
+// StartRAGSpan starts a span of type SpanTypeRAG.
+func (t *SpanTracer) StartRAGSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeRAG, name, opts...)
+}
+
+// StartToolSpan starts a span of type SpanTypeTool.
+func (t *SpanTracer) StartToolSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeTool, name, opts...)
+}
+
+// StartAgentSpan starts a span of type SpanTypeAgent.
+func (t *SpanTracer) StartAgentSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeAgent, name, opts...)
+}
+
+// StartChainSpan starts a span of type SpanTypeChain.
+func (t *SpanTracer) StartChainSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeChain, name, opts...)
+}
+
+// StartGuardrailSpan starts a span of type SpanTypeGuardrail, for an
+// automated policy or safety check running alongside the main chain.
+func (t *SpanTracer) StartGuardrailSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return t.startTyped(ctx, SpanTypeGuardrail, name, opts...)
+}
@@ -0,0 +1,99 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTracerStartStampsDefaultAttributes(t *testing.T) {
+	tracer := Tracer("checkout", WithDefaultAttributes(
+		Attribute{Key: "component", Value: "checkout"},
+		Attribute{Key: "cohort", Value: "beta"},
+	))
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := tracer.Start(ctx, "charge_card")
+	span.End()
+
+	if span.metadata["component"] != "checkout" {
+		t.Fatalf("Metadata[component] = %q, want %q", span.metadata["component"], "checkout")
+	}
+	if span.metadata["cohort"] != "beta" {
+		t.Fatalf("Metadata[cohort] = %q, want %q", span.metadata["cohort"], "beta")
+	}
+}
+
+func TestTracerStartWithoutDefaultAttributesBehavesLikeStartSpan(t *testing.T) {
+	tracer := Tracer("checkout")
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := tracer.Start(ctx, "charge_card", WithType(SpanTypeLLM))
+	span.End()
+
+	if span.spanType != SpanTypeLLM {
+		t.Fatalf("spanType = %v, want %v", span.spanType, SpanTypeLLM)
+	}
+}
+
+func TestTracerStartPassesThroughSpanOptions(t *testing.T) {
+	tracer := Tracer("checkout", WithDefaultAttributes(Attribute{Key: "component", Value: "checkout"}))
+
+	ctx, _ := NewTrace(context.Background())
+	_, span := tracer.Start(ctx, "charge_card", WithModel("openai", "gpt-4o"))
+	span.End()
+
+	if span.model != "gpt-4o" {
+		t.Fatalf("model = %q, want %q", span.model, "gpt-4o")
+	}
+	if span.metadata["component"] != "checkout" {
+		t.Fatalf("Metadata[component] = %q, want %q", span.metadata["component"], "checkout")
+	}
+}
+
+func TestTracerTypedStartHelpersSetSpanType(t *testing.T) {
+	tracer := Tracer("checkout")
+	ctx, _ := NewTrace(context.Background())
+
+	cases := []struct {
+		start func(context.Context, string, ...SpanOption) (context.Context, *Span)
+		want  SpanType
+	}{
+		{tracer.StartLLMSpan, SpanTypeLLM},
+		{tracer.StartRAGSpan, SpanTypeRAG},
+		{tracer.StartToolSpan, SpanTypeTool},
+		{tracer.StartAgentSpan, SpanTypeAgent},
+		{tracer.StartChainSpan, SpanTypeChain},
+		{tracer.StartGuardrailSpan, SpanTypeGuardrail},
+	}
+	for _, c := range cases {
+		_, span := c.start(ctx, "step")
+		span.End()
+		if span.spanType != c.want {
+			t.Fatalf("spanType = %v, want %v", span.spanType, c.want)
+		}
+	}
+}
+
+func TestTracerTypedStartHelperOptionOverridesDefaultType(t *testing.T) {
+	tracer := Tracer("checkout")
+	ctx, _ := NewTrace(context.Background())
+
+	_, span := tracer.StartLLMSpan(ctx, "step", WithType(SpanTypeAgent))
+	span.End()
+
+	if span.spanType != SpanTypeAgent {
+		t.Fatalf("spanType = %v, want %v (caller's WithType should win)", span.spanType, SpanTypeAgent)
+	}
+}
+
+func TestSpanSetTypeReclassifiesAfterStart(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "step")
+	span.SetType(SpanTypeGuardrail)
+	span.End()
+
+	record := span.toRecord()
+	if record.Type != SpanTypeGuardrail {
+		t.Fatalf("Type = %v, want %v", record.Type, SpanTypeGuardrail)
+	}
+}
@@ -0,0 +1,241 @@
+package langwatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// traceUpdatePath is the LangWatch endpoint for patching attributes onto an
+// already-exported trace, mirroring the collector's own path convention.
+const traceUpdatePath = "/api/trace/%s/update"
+
+// TraceUpdate is the set of attributes TracesClient.Update can patch onto a
+// trace after it's been exported, for data only known after the fact - a
+// resolution status, a CSAT score, or labels computed by an asynchronous
+// classifier (see WithTopicClassifier).
+type TraceUpdate struct {
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Labels         []string          `json:"labels,omitempty"`
+	ExpectedOutput *TypedValue       `json:"expected_output,omitempty"`
+}
+
+// TracesClient patches attributes onto traces after they've been exported,
+// via the LangWatch trace update API.
+type TracesClient struct {
+	config     Config
+	httpClient *http.Client
+	retries    int
+}
+
+// TracesClientOption configures a TracesClient built with NewTracesClient.
+type TracesClientOption func(*TracesClient)
+
+// WithTracesClientRetries sets how many times Update retries after a
+// retriable collector response (429 or 5xx) before giving up. Defaults to 0.
+func WithTracesClientRetries(n int) TracesClientOption {
+	return func(c *TracesClient) { c.retries = n }
+}
+
+// NewTracesClient builds a TracesClient from cfg, applying the same
+// environment defaults as NewExporter.
+func NewTracesClient(cfg Config, opts ...TracesClientOption) *TracesClient {
+	c := &TracesClient{config: cfg.withDefaults(), httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+var _ TraceUpdater = (*TracesClient)(nil)
+
+// UpdateLabels implements TraceUpdater, so a TracesClient can be passed
+// directly to WithTraceUpdater.
+func (c *TracesClient) UpdateLabels(ctx context.Context, traceID string, labels []string) error {
+	return c.Update(ctx, traceID, TraceUpdate{Labels: labels})
+}
+
+// Update patches traceID's metadata, labels and/or expected output,
+// retrying up to WithTracesClientRetries times on a retriable (429 or 5xx)
+// response. Every attempt for a single Update call sends the same
+// idempotency key, so a request that succeeds server-side but whose
+// response is lost to a network error doesn't get applied twice on retry.
+func (c *TracesClient) Update(ctx context.Context, traceID string, update TraceUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("langwatch: marshal trace update: %w", err)
+	}
+	idempotencyKey := idempotencyKeyFor(traceID, body)
+
+	var status int
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		status, err = c.update(ctx, traceID, body, idempotencyKey)
+		if err == nil && status < 300 {
+			return nil
+		}
+		if err == nil && !isRetryableStatus(status) {
+			break
+		}
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return fmt.Errorf("langwatch: trace update rejected, check your API key: %w", ErrUnauthorized)
+	}
+	if err != nil {
+		return fmt.Errorf("langwatch: trace update request: %w", err)
+	}
+	return &ExportError{Status: status}
+}
+
+func (c *TracesClient) update(ctx context.Context, traceID string, body []byte, idempotencyKey string) (int, error) {
+	url := c.config.Endpoint + fmt.Sprintf(traceUpdatePath, traceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("langwatch: build trace update request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", resolveAPIKey(ctx, c.config.APIKey))
+	req.Header.Set("X-Idempotency-Key", idempotencyKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// BulkLabelOption configures a BulkLabel call.
+type BulkLabelOption func(*bulkLabelConfig)
+
+type bulkLabelConfig struct {
+	concurrency int
+	interval    time.Duration
+	onProgress  func(BulkLabelProgress)
+}
+
+// WithBulkLabelConcurrency caps how many Update requests BulkLabel has in
+// flight at once. Defaults to 1 (one trace at a time).
+func WithBulkLabelConcurrency(n int) BulkLabelOption {
+	return func(c *bulkLabelConfig) { c.concurrency = n }
+}
+
+// WithBulkLabelThrottle waits interval between starting each Update
+// request, e.g. to stay under the collector's rate limit when relabeling
+// thousands of historical traces at once.
+func WithBulkLabelThrottle(interval time.Duration) BulkLabelOption {
+	return func(c *bulkLabelConfig) { c.interval = interval }
+}
+
+// WithBulkLabelProgress registers a callback invoked once per trace as
+// BulkLabel works through the batch, so a long-running cleanup job can
+// report its own progress.
+func WithBulkLabelProgress(fn func(BulkLabelProgress)) BulkLabelOption {
+	return func(c *bulkLabelConfig) { c.onProgress = fn }
+}
+
+// BulkLabelProgress reports the outcome of labeling a single trace within
+// a BulkLabel call.
+type BulkLabelProgress struct {
+	Total     int
+	Completed int
+	TraceID   string
+	Err       error
+}
+
+// BulkLabelFailure records why BulkLabel failed to label a specific trace.
+type BulkLabelFailure struct {
+	TraceID string
+	Err     error
+}
+
+// BulkLabelResult summarizes a completed BulkLabel call.
+type BulkLabelResult struct {
+	Succeeded int
+	Failed    []BulkLabelFailure
+}
+
+// BulkLabel applies labels to every trace in traceIDs via UpdateLabels, for
+// cleanup jobs that need to tag thousands of historical traces (e.g.
+// "affected-by-incident-123") without writing their own retry/throttle
+// logic. Unlike Update, one trace failing doesn't stop the batch: every
+// trace is attempted and failures are collected into the result instead of
+// returned as an error.
+//
+// This SDK has no server-side trace search API, so BulkLabel takes the
+// trace IDs to label directly rather than a query - callers are expected
+// to already have them, e.g. from the LangWatch UI's export or their own
+// datastore. WithBulkLabelConcurrency and WithBulkLabelThrottle bound how
+// hard this hits the collector; WithBulkLabelProgress reports progress
+// through the batch.
+func (c *TracesClient) BulkLabel(ctx context.Context, traceIDs []string, labels []string, opts ...BulkLabelOption) BulkLabelResult {
+	cfg := bulkLabelConfig{concurrency: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		result BulkLabelResult
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, cfg.concurrency)
+	)
+
+	report := func(traceID string, err error) {
+		mu.Lock()
+		if err != nil {
+			result.Failed = append(result.Failed, BulkLabelFailure{TraceID: traceID, Err: err})
+		} else {
+			result.Succeeded++
+		}
+		completed := result.Succeeded + len(result.Failed)
+		mu.Unlock()
+
+		if cfg.onProgress != nil {
+			cfg.onProgress(BulkLabelProgress{Total: len(traceIDs), Completed: completed, TraceID: traceID, Err: err})
+		}
+	}
+
+	for _, traceID := range traceIDs {
+		if ctx.Err() != nil {
+			report(traceID, ctx.Err())
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(traceID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report(traceID, c.UpdateLabels(ctx, traceID, labels))
+		}(traceID)
+
+		if cfg.interval > 0 {
+			timer := time.NewTimer(cfg.interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+	}
+	wg.Wait()
+
+	return result
+}
+
+// idempotencyKeyFor derives a stable idempotency key from traceID and the
+// update body, so retries of the same logical update reuse the same key
+// while two different updates to the same trace don't collide.
+func idempotencyKeyFor(traceID string, body []byte) string {
+	sum := sha256.Sum256(append([]byte(traceID+":"), body...))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,178 @@
+package langwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTracesClientUpdateSendsPatchWithIdempotencyKey(t *testing.T) {
+	var requests int
+	var idempotencyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPatch {
+			t.Fatalf("method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/api/trace/trace-1/update" {
+			t.Fatalf("path = %s, want /api/trace/trace-1/update", r.URL.Path)
+		}
+		idempotencyKey = r.Header.Get("X-Idempotency-Key")
+		if idempotencyKey == "" {
+			t.Fatal("expected an X-Idempotency-Key header")
+		}
+
+		var update TraceUpdate
+		json.NewDecoder(r.Body).Decode(&update)
+		if len(update.Labels) != 1 || update.Labels[0] != "billing" {
+			t.Fatalf("Labels = %v, want [billing]", update.Labels)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "key", Endpoint: server.URL})
+	if err := client.Update(context.Background(), "trace-1", TraceUpdate{Labels: []string{"billing"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestTracesClientUpdateRetriesOnServerError(t *testing.T) {
+	var requests int
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		keys = append(keys, r.Header.Get("X-Idempotency-Key"))
+		if requests < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "key", Endpoint: server.URL}, WithTracesClientRetries(2))
+	if err := client.Update(context.Background(), "trace-1", TraceUpdate{Labels: []string{"billing"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+	if keys[0] != keys[1] {
+		t.Fatal("expected retries to reuse the same idempotency key")
+	}
+}
+
+func TestTracesClientUpdateRejectsBadAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "bad-key", Endpoint: server.URL})
+	if err := client.Update(context.Background(), "trace-1", TraceUpdate{}); err == nil {
+		t.Fatal("expected an error for a rejected API key")
+	}
+}
+
+func TestTracesClientImplementsTraceUpdater(t *testing.T) {
+	var got TraceUpdate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	var updater TraceUpdater = NewTracesClient(Config{APIKey: "key", Endpoint: server.URL})
+	if err := updater.UpdateLabels(context.Background(), "trace-1", []string{"refund"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Labels) != 1 || got.Labels[0] != "refund" {
+		t.Fatalf("Labels = %v, want [refund]", got.Labels)
+	}
+}
+
+func TestTracesClientBulkLabelLabelsEveryTrace(t *testing.T) {
+	var mu sync.Mutex
+	labeled := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/trace/"), "/update")
+		mu.Lock()
+		labeled[traceID] = true
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "key", Endpoint: server.URL})
+	traceIDs := []string{"trace-1", "trace-2", "trace-3"}
+	result := client.BulkLabel(context.Background(), traceIDs, []string{"affected-by-incident-123"}, WithBulkLabelConcurrency(2))
+
+	if result.Succeeded != 3 || len(result.Failed) != 0 {
+		t.Fatalf("expected all 3 traces to succeed, got %+v", result)
+	}
+	for _, traceID := range traceIDs {
+		if !labeled[traceID] {
+			t.Fatalf("expected %s to be labeled", traceID)
+		}
+	}
+}
+
+func TestTracesClientBulkLabelCollectsFailuresAndReportsProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "trace-bad") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "key", Endpoint: server.URL})
+
+	var mu sync.Mutex
+	var progressed int
+	result := client.BulkLabel(
+		context.Background(),
+		[]string{"trace-good", "trace-bad"},
+		[]string{"affected-by-incident-123"},
+		WithBulkLabelProgress(func(p BulkLabelProgress) {
+			mu.Lock()
+			progressed++
+			mu.Unlock()
+		}),
+	)
+
+	if result.Succeeded != 1 {
+		t.Fatalf("expected 1 success, got %d", result.Succeeded)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].TraceID != "trace-bad" {
+		t.Fatalf("expected trace-bad to fail, got %+v", result.Failed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if progressed != 2 {
+		t.Fatalf("expected 2 progress callbacks, got %d", progressed)
+	}
+}
+
+func TestTracesClientBulkLabelThrottlesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewTracesClient(Config{APIKey: "key", Endpoint: server.URL})
+
+	start := time.Now()
+	client.BulkLabel(
+		context.Background(),
+		[]string{"trace-1", "trace-2", "trace-3"},
+		[]string{"slow"},
+		WithBulkLabelThrottle(20*time.Millisecond),
+	)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expected throttling to space out requests, took %v", elapsed)
+	}
+}
@@ -0,0 +1,103 @@
+package langwatch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metadata keys set on a trace's root span by summarizeTrace.
+const (
+	metadataTraceTotalTokens   = "langwatch.trace.total_tokens"
+	metadataTraceTotalCostUSD  = "langwatch.trace.total_cost_usd"
+	metadataTraceModels        = "langwatch.trace.models"
+	metadataTraceErrorCount    = "langwatch.trace.error_count"
+	metadataTraceToolCallCount = "langwatch.trace.tool_call_count"
+)
+
+// spanSummaryContribution is one span's share of a trace-level summary.
+type spanSummaryContribution struct {
+	tokens     int
+	cost       float64
+	model      string
+	errored    bool
+	isToolCall bool
+}
+
+// summaryContribution reads s's own fields into a spanSummaryContribution.
+// Must be called with s.mu held.
+func (s *Span) summaryContribution() spanSummaryContribution {
+	c := spanSummaryContribution{errored: s.err != nil, isToolCall: s.spanType == SpanTypeTool}
+	if s.spanType == SpanTypeLLM {
+		if s.metrics != nil {
+			if s.metrics.PromptTokens != nil {
+				c.tokens += *s.metrics.PromptTokens
+			}
+			if s.metrics.CompletionTokens != nil {
+				c.tokens += *s.metrics.CompletionTokens
+			}
+			if s.metrics.Cost != nil {
+				c.cost = *s.metrics.Cost
+			}
+		}
+		c.model = s.model
+	}
+	return c
+}
+
+// summarizeTrace aggregates every span on s.trace onto s as metadata: total
+// LLM token usage and cost, the set of models called, how many spans
+// recorded an error, and how many tool calls were made. It's a no-op unless
+// s is the trace's local root span (s.parent == nil), so list views can
+// sort/filter on the root span alone without scanning every child.
+//
+// Must be called with s.mu held.
+func (s *Span) summarizeTrace() {
+	if s.parent != nil {
+		return
+	}
+
+	var totalTokens int
+	var totalCost float64
+	models := map[string]struct{}{}
+	var errorCount, toolCallCount int
+
+	for _, span := range s.trace.Spans() {
+		var c spanSummaryContribution
+		if span == s {
+			c = s.summaryContribution()
+		} else {
+			span.mu.Lock()
+			c = span.summaryContribution()
+			span.mu.Unlock()
+		}
+
+		totalTokens += c.tokens
+		totalCost += c.cost
+		if c.model != "" {
+			models[c.model] = struct{}{}
+		}
+		if c.errored {
+			errorCount++
+		}
+		if c.isToolCall {
+			toolCallCount++
+		}
+	}
+
+	if s.metadata == nil {
+		s.metadata = map[string]string{}
+	}
+	s.metadata[metadataTraceTotalTokens] = strconv.Itoa(totalTokens)
+	s.metadata[metadataTraceTotalCostUSD] = strconv.FormatFloat(totalCost, 'f', -1, 64)
+	if len(models) > 0 {
+		names := make([]string, 0, len(models))
+		for m := range models {
+			names = append(names, m)
+		}
+		sort.Strings(names)
+		s.metadata[metadataTraceModels] = strings.Join(names, ",")
+	}
+	s.metadata[metadataTraceErrorCount] = strconv.Itoa(errorCount)
+	s.metadata[metadataTraceToolCallCount] = strconv.Itoa(toolCallCount)
+}
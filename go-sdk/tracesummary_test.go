@@ -0,0 +1,54 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSummarizeTraceAggregatesChildSpansOntoRoot(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, root := StartSpan(ctx, "workflow", WithType(SpanTypeChain))
+
+	_, llm1 := StartSpan(ctx, "call-1", WithType(SpanTypeLLM), WithModel("openai", "gpt-4o"))
+	promptTokens, completionTokens, cost1 := 10, 5, 0.01
+	llm1.RecordMetrics(Metrics{PromptTokens: &promptTokens, CompletionTokens: &completionTokens, Cost: &cost1})
+	llm1.End()
+
+	_, llm2 := StartSpan(ctx, "call-2", WithType(SpanTypeLLM), WithModel("anthropic", "claude-3"))
+	cost2 := 0.02
+	llm2.RecordMetrics(Metrics{Cost: &cost2})
+	llm2.RecordError(ErrorCapture{Message: "rate limited"})
+	llm2.End()
+
+	_, tool := StartSpan(ctx, "lookup", WithType(SpanTypeTool))
+	tool.End()
+
+	root.End()
+
+	if root.metadata[metadataTraceTotalTokens] != "15" {
+		t.Fatalf("total tokens = %q, want %q", root.metadata[metadataTraceTotalTokens], "15")
+	}
+	if root.metadata[metadataTraceTotalCostUSD] != "0.03" {
+		t.Fatalf("total cost = %q, want %q", root.metadata[metadataTraceTotalCostUSD], "0.03")
+	}
+	if root.metadata[metadataTraceModels] != "claude-3,gpt-4o" {
+		t.Fatalf("models = %q, want sorted set %q", root.metadata[metadataTraceModels], "claude-3,gpt-4o")
+	}
+	if root.metadata[metadataTraceErrorCount] != "1" {
+		t.Fatalf("error count = %q, want %q", root.metadata[metadataTraceErrorCount], "1")
+	}
+	if root.metadata[metadataTraceToolCallCount] != "1" {
+		t.Fatalf("tool call count = %q, want %q", root.metadata[metadataTraceToolCallCount], "1")
+	}
+}
+
+func TestSummarizeTraceSkipsNonRootSpans(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, _ = StartSpan(ctx, "root", WithType(SpanTypeChain))
+	_, child := StartSpan(ctx, "child", WithType(SpanTypeLLM))
+	child.End()
+
+	if _, ok := child.metadata[metadataTraceTotalTokens]; ok {
+		t.Fatal("expected no trace summary metadata on a non-root span")
+	}
+}
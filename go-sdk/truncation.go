@@ -0,0 +1,90 @@
+package langwatch
+
+import "sync"
+
+// TruncationCategory distinguishes the kinds of content oversized-span
+// truncation can apply to, since the right strategy differs by category:
+// prompts tend to matter most at the tail (the latest turn), tool output
+// logs tend to matter most at the head (the summary line, a stack trace's
+// first frame), and tool call arguments are usually structured enough that
+// either end can be safely dropped.
+type TruncationCategory int
+
+const (
+	CategoryInput TruncationCategory = iota
+	CategoryOutput
+	CategoryToolArgs
+)
+
+// TruncationStrategy shrinks text longer than limit bytes and inserts a
+// marker to indicate truncation happened. TruncateHead and TruncateTail keep
+// limit bytes of text and add their marker on top of that; TruncateMiddle
+// fits text and marker within limit when limit is longer than its marker.
+type TruncationStrategy func(text string, limit int) string
+
+// TruncateHead keeps the beginning of text, dropping the tail. This was
+// truncateTypedValue's original (and still default) behavior, good for
+// content where what comes first matters most, e.g. a log's summary line.
+func TruncateHead(text string, limit int) string {
+	if limit <= 0 {
+		return "...[truncated]"
+	}
+	if len(text) <= limit {
+		return text
+	}
+	return text[:limit] + "...[truncated]"
+}
+
+// TruncateTail keeps the end of text, dropping the beginning. Prompts often
+// matter most at the tail - the latest turn of a conversation - so this is
+// the better default for input.
+func TruncateTail(text string, limit int) string {
+	if limit <= 0 {
+		return "[truncated]..."
+	}
+	if len(text) <= limit {
+		return text
+	}
+	return "[truncated]..." + text[len(text)-limit:]
+}
+
+// TruncateMiddle keeps a prefix and a suffix of text, dropping the middle,
+// for content where both ends carry information (e.g. a long tool argument
+// with an identifying prefix and a meaningful trailing value).
+func TruncateMiddle(text string, limit int) string {
+	const marker = "...[truncated]..."
+	if len(text) <= limit {
+		return text
+	}
+	if limit <= len(marker) {
+		return TruncateHead(text, limit)
+	}
+	keep := limit - len(marker)
+	head := keep / 2
+	tail := keep - head
+	return text[:head] + marker + text[len(text)-tail:]
+}
+
+var truncationStrategies = struct {
+	mu   sync.RWMutex
+	byID map[TruncationCategory]TruncationStrategy
+}{byID: map[TruncationCategory]TruncationStrategy{}}
+
+// SetTruncationStrategy configures which TruncationStrategy is used when an
+// oversized span attribute of the given category is truncated (see
+// WithAutoTruncateOversizedSpans). Categories default to TruncateHead if
+// never configured, matching truncateTypedValue's original behavior.
+func SetTruncationStrategy(category TruncationCategory, strategy TruncationStrategy) {
+	truncationStrategies.mu.Lock()
+	defer truncationStrategies.mu.Unlock()
+	truncationStrategies.byID[category] = strategy
+}
+
+func truncationStrategyFor(category TruncationCategory) TruncationStrategy {
+	truncationStrategies.mu.RLock()
+	defer truncationStrategies.mu.RUnlock()
+	if strategy, ok := truncationStrategies.byID[category]; ok {
+		return strategy
+	}
+	return TruncateHead
+}
@@ -0,0 +1,65 @@
+package langwatch
+
+import "testing"
+
+func TestTruncateHeadKeepsBeginning(t *testing.T) {
+	got := TruncateHead("hello world", 5)
+	if got != "hello...[truncated]" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTruncateTailKeepsEnd(t *testing.T) {
+	got := TruncateTail("hello world", 5)
+	if got != "[truncated]...world" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTruncateMiddleKeepsBothEnds(t *testing.T) {
+	got := TruncateMiddle("012345678901234567890123456789", 21)
+	if got != "01"+"...[truncated]..."+"89" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTruncateStrategiesNoOpUnderLimit(t *testing.T) {
+	for _, strategy := range []TruncationStrategy{TruncateHead, TruncateTail, TruncateMiddle} {
+		if got := strategy("short", 100); got != "short" {
+			t.Fatalf("got %q, want unchanged", got)
+		}
+	}
+}
+
+func TestSetTruncationStrategyAppliesPerCategory(t *testing.T) {
+	SetTruncationStrategy(CategoryInput, TruncateTail)
+	defer SetTruncationStrategy(CategoryInput, TruncateHead)
+
+	huge := TypedValue{Type: "text", Value: "0123456789"}
+	record := SpanRecord{ID: "span_1", Name: "big", Input: &huge}
+
+	checkSpanSize(&record, 5, true)
+
+	text := record.Input.Value.(string)
+	if text[len(text)-1] != '9' {
+		t.Fatalf("expected tail-truncation to preserve the end of the text, got %q", text)
+	}
+}
+
+func TestCheckSpanSizeUsesToolArgsCategoryForToolSpans(t *testing.T) {
+	called := false
+	SetTruncationStrategy(CategoryToolArgs, func(text string, limit int) string {
+		called = true
+		return TruncateHead(text, limit)
+	})
+	defer SetTruncationStrategy(CategoryToolArgs, TruncateHead)
+
+	huge := TypedValue{Type: "text", Value: string(make([]byte, 100))}
+	record := SpanRecord{ID: "span_1", Name: "tool", Type: SpanTypeTool, Input: &huge}
+
+	checkSpanSize(&record, 10, true)
+
+	if !called {
+		t.Fatal("expected the tool-args truncation strategy to be used for a tool span's input")
+	}
+}
@@ -0,0 +1,174 @@
+package langwatch
+
+// SpanType identifies the kind of operation a span represents, mirroring the
+// span types accepted by the LangWatch collector.
+type SpanType string
+
+const (
+	SpanTypeSpan      SpanType = "span"
+	SpanTypeLLM       SpanType = "llm"
+	SpanTypeChain     SpanType = "chain"
+	SpanTypeTool      SpanType = "tool"
+	SpanTypeAgent     SpanType = "agent"
+	SpanTypeRAG       SpanType = "rag"
+	SpanTypeGuardrail SpanType = "guardrail"
+)
+
+// ChatRole is the role of a chat message, following the OpenAI convention.
+type ChatRole string
+
+const (
+	RoleSystem    ChatRole = "system"
+	RoleUser      ChatRole = "user"
+	RoleAssistant ChatRole = "assistant"
+	RoleFunction  ChatRole = "function"
+	RoleTool      ChatRole = "tool"
+)
+
+// FunctionCall mirrors the OpenAI function_call field on a chat message.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolCall mirrors a single tool call requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// ChatMessage is a single turn of a chat conversation.
+type ChatMessage struct {
+	Role         ChatRole      `json:"role,omitempty"`
+	Content      *string       `json:"content,omitempty"`
+	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+}
+
+// TypedValue is the tagged union LangWatch uses for span input/output values.
+type TypedValue struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// NewTextValue wraps a plain string as a "text" typed value.
+func NewTextValue(text string) TypedValue {
+	return TypedValue{Type: "text", Value: text}
+}
+
+// NewChatMessagesValue wraps chat messages as a "chat_messages" typed value.
+func NewChatMessagesValue(messages []ChatMessage) TypedValue {
+	return TypedValue{Type: "chat_messages", Value: messages}
+}
+
+// NewJSONValue wraps arbitrary JSON-serializable data as a "json" typed value.
+func NewJSONValue(value interface{}) TypedValue {
+	return TypedValue{Type: "json", Value: value}
+}
+
+// NewRawValue wraps a raw string, sent through untouched by the backend.
+func NewRawValue(raw string) TypedValue {
+	return TypedValue{Type: "raw", Value: raw}
+}
+
+// ErrorCapture describes an error that occurred during a span.
+//
+// Type and Code are optional structured classifiers a caller can fill in
+// from a provider's own error response (e.g. OpenAI's error.type and
+// error.code fields) when it has them. RecordError uses them, together
+// with the span's model, to compute a stable fingerprint - see
+// errorfingerprint.go - so recurring failure modes can be grouped even
+// though Message routinely varies per request.
+type ErrorCapture struct {
+	Message    string   `json:"message"`
+	Stacktrace []string `json:"stacktrace"`
+	Type       string   `json:"type,omitempty"`
+	Code       string   `json:"code,omitempty"`
+}
+
+// Metrics carries token usage and cost accounting for an LLM span.
+type Metrics struct {
+	PromptTokens     *int     `json:"prompt_tokens,omitempty"`
+	CompletionTokens *int     `json:"completion_tokens,omitempty"`
+	TokensEstimated  *bool    `json:"tokens_estimated,omitempty"`
+	Cost             *float64 `json:"cost,omitempty"`
+}
+
+// Params carries the request parameters used for an LLM call.
+type Params struct {
+	Temperature *float64                 `json:"temperature,omitempty"`
+	Stream      *bool                    `json:"stream,omitempty"`
+	Functions   []map[string]interface{} `json:"functions,omitempty"`
+	Tools       []map[string]interface{} `json:"tools,omitempty"`
+	ToolChoice  string                   `json:"tool_choice,omitempty"`
+}
+
+// Timestamps records the lifecycle of a span, in unix milliseconds.
+type Timestamps struct {
+	StartedAt    int64  `json:"started_at"`
+	FirstTokenAt *int64 `json:"first_token_at,omitempty"`
+	FinishedAt   int64  `json:"finished_at"`
+}
+
+// SpanRecord is the wire representation of a span, matching the shape the
+// LangWatch collector expects at POST /api/collector.
+type SpanRecord struct {
+	Type       SpanType      `json:"type"`
+	Name       string        `json:"name,omitempty"`
+	ID         string        `json:"id"`
+	ParentID   *string       `json:"parent_id,omitempty"`
+	TraceID    string        `json:"trace_id"`
+	Input      *TypedValue   `json:"input,omitempty"`
+	Outputs    []TypedValue  `json:"outputs"`
+	Error      *ErrorCapture `json:"error,omitempty"`
+	Timestamps Timestamps    `json:"timestamps"`
+
+	// LLM-specific fields, each omitted when unset, whatever the span type.
+	Vendor      string      `json:"vendor,omitempty"`
+	Model       string      `json:"model,omitempty"`
+	RawResponse interface{} `json:"raw_response,omitempty"`
+	Params      *Params     `json:"params,omitempty"`
+	Metrics     *Metrics    `json:"metrics,omitempty"`
+
+	// RAG-specific field, omitted when empty, whatever the span type.
+	Contexts []string `json:"contexts,omitempty"`
+
+	// Metadata carries SDK-side annotations (hedging attempt numbers, timing
+	// marks, ...) that don't have a dedicated field on the wire schema yet.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// TimelineEvents are point-in-time events recorded with
+	// Span.AddTimelineEvent, rendered on LangWatch's UI timeline.
+	TimelineEvents []TimelineEvent `json:"timeline_events,omitempty"`
+}
+
+// TimelineEvent is a point-in-time event within a span's lifetime (a cache
+// hit, a retry, a guardrail decision, ...), distinct from span metadata:
+// metadata describes the span as a whole, while a span can carry any number
+// of timeline events, each with its own timestamp.
+type TimelineEvent struct {
+	Name       string            `json:"name"`
+	Timestamp  int64             `json:"timestamp"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// CollectorRequest is the top level payload sent to the LangWatch collector.
+type CollectorRequest struct {
+	TraceID    string       `json:"trace_id,omitempty"`
+	ThreadID   string       `json:"thread_id,omitempty"`
+	UserID     string       `json:"user_id,omitempty"`
+	CustomerID string       `json:"customer_id,omitempty"`
+	Labels     []string     `json:"labels,omitempty"`
+	Spans      []SpanRecord `json:"spans"`
+
+	// Metadata carries trace-level annotations set with SetTraceMetadata,
+	// distinct from any individual span's Metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Input and Output are the trace's top-level summary, set with
+	// RecordTraceInput/RecordTraceOutput, distinct from any individual
+	// span's Input/Outputs.
+	Input  *TypedValue `json:"input,omitempty"`
+	Output *TypedValue `json:"output,omitempty"`
+}
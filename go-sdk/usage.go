@@ -0,0 +1,64 @@
+package langwatch
+
+import (
+	"context"
+	"strconv"
+)
+
+// Usage summarizes the token accounting and cost for a single LLM call, in
+// a shape that's the same regardless of how the provider that produced it
+// names its own usage fields - OpenAI's prompt/completion tokens vs.
+// Anthropic's input/output tokens, reasoning tokens on o1-style models,
+// cached tokens from prompt caching, and so on.
+type Usage struct {
+	Input     int
+	Output    int
+	Reasoning int
+	Cached    int
+	CostUSD   float64
+}
+
+// metadataUsageReasoningTokens and metadataUsageCachedTokens record the two
+// Usage fields with no dedicated field on Metrics, which mirrors the
+// collector's older prompt/completion token schema.
+const (
+	metadataUsageReasoningTokens = "langwatch.usage.reasoning_tokens"
+	metadataUsageCachedTokens    = "langwatch.usage.cached_tokens"
+)
+
+// UsageObserver receives every Usage recorded via RecordUsage, regardless
+// of which provider or instrumentation package produced it - one place for
+// billing/cost-tracking code to hook in without special-casing each
+// vendor's response shape.
+type UsageObserver func(ctx context.Context, usage Usage)
+
+var usageObservers []UsageObserver
+
+// ObserveUsage registers a UsageObserver invoked by every RecordUsage call.
+// Like Subscribe, observers run synchronously on the calling goroutine and
+// should not block.
+func ObserveUsage(observer UsageObserver) {
+	usageObservers = append(usageObservers, observer)
+}
+
+// RecordUsage records u on the span found in ctx - Input/Output/CostUSD
+// onto the span's Metrics, Reasoning/Cached as metadata - and notifies
+// every UsageObserver, so billing code can consume a call's usage
+// programmatically instead of re-parsing each provider's raw response.
+// Like RecordError, this proceeds even when capture is disabled: token
+// counts and cost are accounting data, not user content.
+func RecordUsage(ctx context.Context, u Usage) {
+	if span, ok := SpanFromContext(ctx); ok {
+		input, output, cost := u.Input, u.Output, u.CostUSD
+		span.RecordMetrics(Metrics{PromptTokens: &input, CompletionTokens: &output, Cost: &cost})
+		if u.Reasoning != 0 {
+			span.SetMetadata(metadataUsageReasoningTokens, strconv.Itoa(u.Reasoning))
+		}
+		if u.Cached != 0 {
+			span.SetMetadata(metadataUsageCachedTokens, strconv.Itoa(u.Cached))
+		}
+	}
+	for _, observer := range usageObservers {
+		observer(ctx, u)
+	}
+}
@@ -0,0 +1,59 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordUsageSetsMetricsAndMetadata(t *testing.T) {
+	ctx, trace := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat")
+
+	RecordUsage(ctx, Usage{Input: 10, Output: 5, Reasoning: 3, Cached: 2, CostUSD: 0.0042})
+	span.End()
+
+	record := trace.Spans()[0].toRecord()
+	if record.Metrics == nil || *record.Metrics.PromptTokens != 10 || *record.Metrics.CompletionTokens != 5 {
+		t.Fatalf("expected Input/Output to be recorded as PromptTokens/CompletionTokens, got %+v", record.Metrics)
+	}
+	if *record.Metrics.Cost != 0.0042 {
+		t.Fatalf("Cost = %v, want 0.0042", *record.Metrics.Cost)
+	}
+	if record.Metadata[metadataUsageReasoningTokens] != "3" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataUsageReasoningTokens, record.Metadata[metadataUsageReasoningTokens], "3")
+	}
+	if record.Metadata[metadataUsageCachedTokens] != "2" {
+		t.Fatalf("Metadata[%s] = %q, want %q", metadataUsageCachedTokens, record.Metadata[metadataUsageCachedTokens], "2")
+	}
+}
+
+func TestRecordUsageNotifiesObservers(t *testing.T) {
+	var got []Usage
+	ObserveUsage(func(ctx context.Context, usage Usage) {
+		got = append(got, usage)
+	})
+
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "chat")
+	RecordUsage(ctx, Usage{Input: 1, Output: 2})
+	span.End()
+
+	if len(got) != 1 || got[0].Input != 1 || got[0].Output != 2 {
+		t.Fatalf("expected the observer to see the recorded usage, got %+v", got)
+	}
+}
+
+func TestRecordUsageWithoutSpanStillNotifiesObservers(t *testing.T) {
+	var called bool
+	ObserveUsage(func(ctx context.Context, usage Usage) {
+		if usage.CostUSD == 0.0099 {
+			called = true
+		}
+	})
+
+	RecordUsage(context.Background(), Usage{CostUSD: 0.0099})
+
+	if !called {
+		t.Fatal("expected the observer to run even without a span in ctx")
+	}
+}
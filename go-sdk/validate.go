@@ -0,0 +1,131 @@
+package langwatch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// timelineEventValidation is the timeline event name ValidatorChain.Validate
+// uses to record each validator's outcome.
+const timelineEventValidation = "validation"
+
+// Timeline event attribute keys a validation outcome is recorded with.
+const (
+	metadataValidationName   = "validator"
+	metadataValidationPassed = "passed"
+	metadataValidationReason = "reason"
+)
+
+// ValidationResult is the outcome of running a single Validator against a
+// completion's output text. Reason is populated when Passed is false, and
+// is meant to be fed back to the model on retry via FailureFeedback.
+type ValidationResult struct {
+	Passed bool
+	Reason string
+}
+
+// Validator inspects a completion's output text and reports whether it
+// meets some criterion.
+type Validator interface {
+	Name() string
+	Validate(output string) ValidationResult
+}
+
+// validatorFunc adapts a plain function to the Validator interface,
+// mirroring the standard library's http.HandlerFunc.
+type validatorFunc struct {
+	name string
+	fn   func(output string) ValidationResult
+}
+
+func (v validatorFunc) Name() string                            { return v.name }
+func (v validatorFunc) Validate(output string) ValidationResult { return v.fn(output) }
+
+// CustomFn wraps fn as a Validator named name, for validation logic that
+// doesn't fit one of this package's built-in validators.
+func CustomFn(name string, fn func(output string) ValidationResult) Validator {
+	return validatorFunc{name: name, fn: fn}
+}
+
+// NoEmptyOutput fails validation when the completion's output is empty or
+// whitespace-only.
+func NoEmptyOutput() Validator {
+	return validatorFunc{name: "no_empty_output", fn: func(output string) ValidationResult {
+		if strings.TrimSpace(output) == "" {
+			return ValidationResult{Reason: "output was empty"}
+		}
+		return ValidationResult{Passed: true}
+	}}
+}
+
+// MaxLength fails validation when the completion's output exceeds n runes.
+func MaxLength(n int) Validator {
+	return validatorFunc{name: "max_length", fn: func(output string) ValidationResult {
+		if utf8.RuneCountInString(output) > n {
+			return ValidationResult{Reason: fmt.Sprintf("output exceeded %d characters", n)}
+		}
+		return ValidationResult{Passed: true}
+	}}
+}
+
+// RegexMustMatch fails validation when the completion's output doesn't
+// match pattern. It panics if pattern doesn't compile, matching
+// regexp.MustCompile's own behavior, since an invalid pattern is a
+// programmer error to catch at startup rather than on every completion.
+func RegexMustMatch(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return validatorFunc{name: "regex_must_match", fn: func(output string) ValidationResult {
+		if !re.MatchString(output) {
+			return ValidationResult{Reason: fmt.Sprintf("output did not match pattern %q", pattern)}
+		}
+		return ValidationResult{Passed: true}
+	}}
+}
+
+// ValidatorChain runs an ordered set of Validators against a completion's
+// output text, recording each outcome as a timeline event on the span
+// found in ctx.
+type ValidatorChain struct {
+	validators []Validator
+}
+
+// NewValidatorChain builds a chain that runs validators in order.
+func NewValidatorChain(validators ...Validator) *ValidatorChain {
+	return &ValidatorChain{validators: validators}
+}
+
+// Validate runs every validator in the chain against output. It returns the
+// first failing result, or nil if every validator passed. Each validator's
+// outcome is recorded as a timeline event on the span found in ctx, if any,
+// regardless of pass/fail, so a completion's full validation history is
+// visible on its trace.
+func (c *ValidatorChain) Validate(ctx context.Context, output string) *ValidationResult {
+	span, hasSpan := SpanFromContext(ctx)
+	var firstFailure *ValidationResult
+	for _, v := range c.validators {
+		result := v.Validate(output)
+		if hasSpan {
+			span.AddTimelineEvent(timelineEventValidation, map[string]string{
+				metadataValidationName:   v.Name(),
+				metadataValidationPassed: strconv.FormatBool(result.Passed),
+				metadataValidationReason: result.Reason,
+			})
+		}
+		if !result.Passed && firstFailure == nil {
+			r := result
+			firstFailure = &r
+		}
+	}
+	return firstFailure
+}
+
+// FailureFeedback formats a failed ValidationResult as a short instruction
+// suitable for appending to a retry request, telling the model what was
+// wrong with its previous answer so the retry has a chance of fixing it.
+func FailureFeedback(result ValidationResult) string {
+	return fmt.Sprintf("Your previous response failed validation: %s. Please try again and address this.", result.Reason)
+}
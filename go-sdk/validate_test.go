@@ -0,0 +1,66 @@
+package langwatch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidatorChainReturnsFirstFailure(t *testing.T) {
+	chain := NewValidatorChain(NoEmptyOutput(), MaxLength(5))
+	result := chain.Validate(context.Background(), "way too long")
+	if result == nil {
+		t.Fatal("expected a validation failure")
+	}
+	if result.Reason == "" {
+		t.Fatal("expected a failure reason")
+	}
+}
+
+func TestValidatorChainPassesWhenAllValidatorsPass(t *testing.T) {
+	chain := NewValidatorChain(NoEmptyOutput(), MaxLength(50))
+	if result := chain.Validate(context.Background(), "short answer"); result != nil {
+		t.Fatalf("expected no failure, got %+v", result)
+	}
+}
+
+func TestRegexMustMatchFailsOnNonMatch(t *testing.T) {
+	chain := NewValidatorChain(RegexMustMatch(`^\d+$`))
+	result := chain.Validate(context.Background(), "not a number")
+	if result == nil {
+		t.Fatal("expected a validation failure")
+	}
+}
+
+func TestCustomFnRunsSuppliedLogic(t *testing.T) {
+	var ran bool
+	chain := NewValidatorChain(CustomFn("no-foo", func(output string) ValidationResult {
+		ran = true
+		if output == "foo" {
+			return ValidationResult{Reason: "output was foo"}
+		}
+		return ValidationResult{Passed: true}
+	}))
+	chain.Validate(context.Background(), "bar")
+	if !ran {
+		t.Fatal("expected the custom function to run")
+	}
+}
+
+func TestValidatorChainRecordsTimelineEventsOnSpan(t *testing.T) {
+	ctx, _ := NewTrace(context.Background())
+	ctx, span := StartSpan(ctx, "call")
+
+	chain := NewValidatorChain(NoEmptyOutput())
+	chain.Validate(ctx, "")
+	span.End()
+
+	span.mu.Lock()
+	events := span.timelineEvents
+	span.mu.Unlock()
+	if len(events) != 1 || events[0].Name != timelineEventValidation {
+		t.Fatalf("expected 1 validation timeline event, got %+v", events)
+	}
+	if events[0].Attributes[metadataValidationPassed] != "false" {
+		t.Fatalf("expected a failed validation event, got %+v", events[0].Attributes)
+	}
+}
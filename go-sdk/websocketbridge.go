@@ -0,0 +1,127 @@
+package langwatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// WebSocket message types, matching the RFC 6455 opcodes used by common
+// WebSocket libraries (e.g. gorilla/websocket's TextMessage/BinaryMessage/
+// CloseMessage), so callers don't need to import this package's dependency
+// alongside their own WebSocket library just to pass a message type.
+const (
+	WSTextMessage   = 1
+	WSBinaryMessage = 2
+	WSCloseMessage  = 8
+)
+
+// WebSocket close codes, per RFC 6455 section 7.4.1.
+const (
+	WSCloseNormalClosure = 1000
+	WSCloseInternalErr   = 1011
+)
+
+// metadataWSCloseCode is the metadata key WebSocketBridge stamps the final
+// close code onto, so a trace can be filtered by how a streamed response
+// ended.
+const metadataWSCloseCode = "langwatch.ws.close_code"
+
+// WSConn is the subset of a WebSocket connection WebSocketBridge needs.
+// *gorilla/websocket.Conn and other common WebSocket libraries already
+// satisfy this signature, so using WebSocketBridge doesn't pull in an
+// additional dependency on top of whichever one the caller already uses.
+type WSConn interface {
+	WriteMessage(messageType int, data []byte) error
+	ReadMessage() (messageType int, data []byte, err error)
+}
+
+// FormatWSCloseMessage builds the payload of a WebSocket close control
+// frame: a 2-byte big-endian close code followed by an optional UTF-8
+// reason, as required by RFC 6455 section 5.5.1.
+func FormatWSCloseMessage(code int, reason string) []byte {
+	buf := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(buf, uint16(code))
+	copy(buf[2:], reason)
+	return buf
+}
+
+// WebSocketBridge forwards the text deltas of an upstream SSE stream to
+// conn as WebSocket text frames, while feeding the same lines to a
+// StreamAccumulator and stamping the accumulated output/usage onto span
+// once upstream closes. It maps how the stream ended to a WebSocket close
+// code (WSCloseNormalClosure on success, WSCloseInternalErr if reading
+// upstream failed) and sends a close frame with that code.
+//
+// WebSocketBridge also drains conn in the background so it notices the
+// client closing the connection (or otherwise going away) mid-stream: once
+// that happens it stops writing to conn, but keeps draining and
+// accumulating from upstream so the trace still reflects the full
+// response, exactly like StreamBridge does for the SSE case.
+func WebSocketBridge(ctx context.Context, conn WSConn, upstream io.Reader, span SpanRecorder) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		// WebSocketBridge doesn't expect inbound application data; any read
+		// error (including the peer's close frame) means the client is
+		// gone or asked to stop, so treat it as mid-stream cancellation.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	acc := NewStreamAccumulator()
+	scanner := bufio.NewScanner(upstream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	clientGone := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		acc.Feed(line)
+
+		if clientGone {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			clientGone = true
+			continue
+		default:
+		}
+
+		chunk, ok := parseSSELine(line)
+		if !ok {
+			continue
+		}
+		var delta string
+		for _, choice := range chunk.Choices {
+			delta += choice.Delta.Content
+		}
+		if delta == "" {
+			continue
+		}
+		if err := conn.WriteMessage(WSTextMessage, []byte(delta)); err != nil {
+			clientGone = true
+		}
+	}
+
+	acc.StampOnto(span)
+
+	code := WSCloseNormalClosure
+	if err := scanner.Err(); err != nil {
+		code = WSCloseInternalErr
+		span.RecordError(ErrorCapture{Message: err.Error()})
+	}
+	span.SetMetadata(metadataWSCloseCode, strconv.Itoa(code))
+
+	if !clientGone {
+		_ = conn.WriteMessage(WSCloseMessage, FormatWSCloseMessage(code, acc.Result().FinishReason))
+	}
+	return scanner.Err()
+}
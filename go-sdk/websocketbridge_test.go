@@ -0,0 +1,96 @@
+package langwatch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeWSConn struct {
+	mu       sync.Mutex
+	written  [][]byte
+	incoming chan struct{}
+}
+
+func newFakeWSConn() *fakeWSConn {
+	return &fakeWSConn{incoming: make(chan struct{})}
+}
+
+func (c *fakeWSConn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := append([]byte(nil), data...)
+	c.written = append(c.written, cp)
+	return nil
+}
+
+func (c *fakeWSConn) ReadMessage() (int, []byte, error) {
+	<-c.incoming
+	return 0, nil, errors.New("connection closed")
+}
+
+func (c *fakeWSConn) close() {
+	close(c.incoming)
+}
+
+func (c *fakeWSConn) messages() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.written...)
+}
+
+func TestWebSocketBridgeForwardsDeltasAndAccumulates(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":2}}\n" +
+			"data: [DONE]\n",
+	)
+	conn := newFakeWSConn()
+	defer conn.close()
+
+	ctx, trace := NewTrace(context.Background())
+	_, span := StartSpan(ctx, "chat")
+
+	if err := WebSocketBridge(context.Background(), conn, upstream, span); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs := conn.messages()
+	if len(msgs) != 3 {
+		t.Fatalf("expected 2 text frames + 1 close frame, got %d", len(msgs))
+	}
+	if string(msgs[0]) != "Hel" || string(msgs[1]) != "lo" {
+		t.Fatalf("unexpected forwarded deltas: %q, %q", msgs[0], msgs[1])
+	}
+
+	record := trace.Spans()[0].toRecord()
+	if len(record.Outputs) != 1 || record.Outputs[0].Value != "Hello" {
+		t.Fatalf("expected accumulated output %q, got %+v", "Hello", record.Outputs)
+	}
+	if record.Metadata[metadataWSCloseCode] != "1000" {
+		t.Fatalf("expected close code 1000, got %q", record.Metadata[metadataWSCloseCode])
+	}
+}
+
+func TestWebSocketBridgeStopsWritingWhenContextCanceled(t *testing.T) {
+	upstream := strings.NewReader(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"b\"}}]}\n",
+	)
+	conn := newFakeWSConn()
+	defer conn.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, span := StartSpan(context.Background(), "chat")
+	if err := WebSocketBridge(ctx, conn, upstream, span); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(conn.messages()) != 0 {
+		t.Fatalf("expected no frames (not even a close frame, since the client is already gone), got %d", len(conn.messages()))
+	}
+}